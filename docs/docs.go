@@ -15,9 +15,23 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth": {
+        "/apikeys": {
             "get": {
-                "description": "Проверяет, зарегистрирован ли пользователь по telegram_id. Если пользователь найден, возвращает его данные, иначе – сообщение об ошибке.",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все API-ключи без секретной части. Доступно администраторам.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,33 +39,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Проверка авторизации пользователя",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    }
+                    "apikeys"
                 ],
+                "summary": "Список API-ключей",
                 "responses": {
                     "200": {
-                        "description": "Данные пользователя",
+                        "description": "Список ключей",
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterInput"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.APIKeyResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Ошибка telegram_id is required",
+                    "403": {
+                        "description": "Доступно только администратору",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Пользователь не найден",
+                    "500": {
+                        "description": "Ошибка при получении ключей",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -59,7 +67,21 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Регистрация пользователя с помощью уникального telegram_id",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт API-ключ с указанными правами. Ключ возвращается целиком только в этом ответе. Доступно администраторам и API-ключам с правом apikeys:write: ключ выдаёт только свои права, а can_act_on_behalf — только если он есть у него самого.",
                 "consumes": [
                     "application/json"
                 ],
@@ -67,41 +89,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "apikeys"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Создание API-ключа",
                 "parameters": [
                     {
-                        "description": "Данные пользователя для регистрации",
+                        "description": "Параметры ключа",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterInput"
+                            "$ref": "#/definitions/apikeys.CreateAPIKeyInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешная регистрация",
+                        "description": "Созданный ключ",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/response.APIKeySecretResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации",
+                        "description": "Ошибка валидации или неизвестное право",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Пользователь уже зарегистрирован",
+                    "403": {
+                        "description": "Error: Доступно только администратору, Error: У API-ключа нет права tasks:write Code: SCOPE_FORBIDDEN, Error: Разрешить действовать от имени пользователя может только администратор Code: ON_BEHALF_FORBIDDEN",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
-                    "507": {
-                        "description": "Не удалось создать пользователя",
+                    "500": {
+                        "description": "Ошибка при создании ключа",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -109,9 +131,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/meetings": {
-            "post": {
-                "description": "Создает новую встречу для команды. Доступно только для менеджеров.",
+        "/apikeys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает ключ. Запись остаётся в списке для истории. Администратор может отозвать любой ключ, API-ключ — только созданные им.",
                 "consumes": [
                     "application/json"
                 ],
@@ -119,60 +155,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meetings"
+                    "apikeys"
                 ],
-                "summary": "Создание встречи",
+                "summary": "Отзыв API-ключа",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Данные встречи",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meetings.CreateMeetingInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о созданной встрече",
+                        "description": "Ключ отозван",
                         "schema": {
-                            "$ref": "#/definitions/response.MeetingResponse"
+                            "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации или некорректные данные",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Пользователь не найден",
+                        "description": "Нельзя отозвать ключ, которым выполнен запрос",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                        "description": "Error: Доступно только администратору, Error: API-ключ может отзывать только созданные им ключи Code: API_KEY_FORBIDDEN",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
-                    "409": {
-                        "description": "Конфликт по времени и аудитории",
+                    "404": {
+                        "description": "Ключ не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Ошибка при отзыве ключа",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -180,9 +201,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/meetings/available-slots": {
-            "get": {
-                "description": "Возвращает список доступных временных слотов для указанной аудитории на выбранную дату",
+        "/apikeys/{id}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет значение ключа, сохраняя его права. Старое значение перестаёт действовать сразу. Администратор может ротировать любой ключ, API-ключ — только созданные им.",
                 "consumes": [
                     "application/json"
                 ],
@@ -190,46 +225,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meetings"
+                    "apikeys"
                 ],
-                "summary": "Получение доступных временных слотов",
+                "summary": "Ротация API-ключа",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Номер аудитории",
-                        "name": "room",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Дата в формате YYYY-MM-DD",
-                        "name": "date",
-                        "in": "query",
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список доступных временных слотов",
+                        "description": "Новое значение ключа",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/meetings.TimeSlot"
-                                }
-                            }
+                            "$ref": "#/definitions/response.APIKeySecretResponse"
                         }
                     },
-                    "400": {
-                        "description": "Отсутствуют обязательные параметры или неверный формат",
+                    "403": {
+                        "description": "Error: Доступно только администратору, Error: API-ключ может ротировать только созданные им ключи Code: API_KEY_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ключ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ключ отозван",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении данных",
+                        "description": "Ошибка при ротации ключа",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -237,9 +271,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/meetings/my": {
+        "/auth": {
             "get": {
-                "description": "Возвращает список всех встреч команды, к которой привязан пользователь",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Проверяет подписанные данные Telegram и то, зарегистрирован ли пользователь. Если пользователь найден, возвращает его данные и новую пару токенов, иначе – сообщение об ошибке. При запросе с Bearer-токеном или API-ключом новые токены не выпускаются.",
                 "consumes": [
                     "application/json"
                 ],
@@ -247,52 +295,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meetings"
-                ],
-                "summary": "Получение встреч команды",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Проверка авторизации пользователя",
                 "responses": {
                     "200": {
-                        "description": "Список встреч команды",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/response.MeetingResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Отсутствует telegram_id или у пользователя нет команды",
+                        "description": "Данные пользователя и токены",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/auth.AuthResponse"
                         }
                     },
                     "401": {
-                        "description": "Пользователь не найден",
+                        "description": "Пользователь не найден или неверные данные Telegram",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении встреч",
+                        "description": "Не удалось выпустить токены",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/meetings/{id}": {
-            "delete": {
-                "description": "Удаляет встречу из расписания команды. Доступно только для менеджеров.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Регистрация пользователя. telegram_id берётся из подписанных данных Telegram. Роль пользователь получает при создании команды или вступлении в неё.",
                 "consumes": [
                     "application/json"
                 ],
@@ -300,58 +339,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meetings"
+                    "auth"
                 ],
-                "summary": "Удаление встречи",
+                "summary": "Регистрация пользователя",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID встречи",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Данные пользователя для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Встреча успешно удалена",
+                        "description": "Успешная регистрация и токены",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/auth.RegisterResponse"
                         }
                     },
                     "400": {
-                        "description": "Отсутствует telegram_id или ID встречи",
+                        "description": "Ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Пользователь не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                        "description": "Неверные или устаревшие данные Telegram",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
-                    "404": {
-                        "description": "Встреча не найдена",
+                    "409": {
+                        "description": "Пользователь уже зарегистрирован",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка при удалении встречи",
+                    "507": {
+                        "description": "Не удалось создать пользователя",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -359,9 +387,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks": {
-            "get": {
-                "description": "Получение списка задач для пользователя",
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает текущий access-токен и переданный refresh-токен. С all=true отзываются все refresh-токены пользователя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -369,50 +405,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "auth"
                 ],
-                "summary": "Получение списка задач",
+                "summary": "Выход",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Telegram ID пользователя",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Параметры выхода",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/auth.LogoutInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список задач",
+                        "description": "Сессия завершена",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/response.TaskResponse"
-                            }
+                            "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "telegram_id is required",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Пользователь не найден",
+                    "401": {
+                        "description": "Пользователь не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении задач",
+                        "description": "Ошибка при завершении сессии",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "description": "Создание задачи для команды и индивидуально",
+                "description": "Обменивает действующий refresh-токен на новую пару токенов. Старый refresh-токен отзывается. Повторное использование отозванного токена завершает все сессии пользователя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -420,54 +450,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "auth"
                 ],
-                "summary": "Создание задачи",
+                "summary": "Обновление токенов",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Telegram ID управляющегоr",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Информация задачи",
-                        "name": "task",
+                        "description": "Refresh-токен",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/tasks.TaskInput"
+                            "$ref": "#/definitions/auth.RefreshInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Задача успешно создана",
+                        "description": "Новая пара токенов",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/auth.TokenPair"
                         }
                     },
                     "400": {
-                        "description": "assigned_to обязателен для персональных задач",
+                        "description": "Ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Пользователь не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Только менеджер может создавать задачу",
+                        "description": "Error: Недействительный refresh-токен Code: REFRESH_INVALID, Error: Refresh-токен отозван Code: REFRESH_REVOKED, Error: Срок действия refresh-токена истёк Code: REFRESH_EXPIRED",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при создании задачи",
+                        "description": "Не удалось выпустить токены",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -475,9 +492,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/issued": {
-            "get": {
-                "description": "Возвращает список задач, созданных менеджером. Отправляет уведомление в Telegram с списком задач или сообщением об их отсутствии.",
+        "/meetings": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создает новую встречу для команды. Доступно владельцу и руководителям команды.",
                 "consumes": [
                     "application/json"
                 ],
@@ -485,30 +516,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "meetings"
                 ],
-                "summary": "Получить выданные задачи",
+                "summary": "Создание встречи",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Telegram ID менеджера",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Данные встречи",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/meetings.CreateMeetingInput"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список выданных задач",
+                        "description": "Информация о созданной встрече",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/response.TaskResponse"
-                            }
+                            "$ref": "#/definitions/response.MeetingResponse"
                         }
                     },
                     "400": {
-                        "description": "telegram_id is required",
+                        "description": "Ошибка валидации или некорректные данные",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -520,13 +556,25 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Доступно только для руководителя",
+                        "description": "Error: Только руководитель может управлять встречами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт по времени и аудитории, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении задач",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -534,9 +582,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}": {
-            "delete": {
-                "description": "Удаление задачи менеджером команды",
+        "/meetings/available-slots": {
+            "get": {
+                "description": "Возвращает список доступных временных слотов для указанной аудитории на выбранную дату",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,52 +592,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "meetings"
                 ],
-                "summary": "Удаление задачи",
+                "summary": "Получение доступных временных слотов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Telegram ID менеджера",
-                        "name": "telegram_id",
+                        "description": "Номер аудитории",
+                        "name": "room",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "ID задачи",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Дата в формате YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Задача успешно удалена",
+                        "description": "Список доступных временных слотов",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/meetings.TimeSlot"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Error: У пользователя нет привязанной команды CODE: NOT_TEAM",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Пользователь не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Задачу создали не вы",
+                        "description": "Отсутствуют обязательные параметры или неверный формат",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Задача не найдена",
+                        "description": "Ошибка при получении данных",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -597,9 +639,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/status": {
-            "put": {
-                "description": "Обновление статуса задачи участником команды или исполнителем персональной задачи",
+        "/meetings/my": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список всех встреч команды, к которой привязан пользователь",
                 "consumes": [
                     "application/json"
                 ],
@@ -607,45 +663,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "meetings"
                 ],
-                "summary": "Обновление статуса задачи",
+                "summary": "Получение встреч команды",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Telegram ID пользователя",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID задачи",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Данные для обновления статуса",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/tasks.UpdateTaskStatusInput"
-                        }
+                        "type": "integer",
+                        "description": "ID отдела: встречи всех команд отдела и вложенных отделов (для руководителя отдела и владельца организации)",
+                        "name": "department_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Статус задачи успешно обновлен",
+                        "description": "Список встреч команды",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.MeetingResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Неверное значение статуса",
+                        "description": "Error: Некорректный department_id Code: INVALID_DEPARTMENT_ID",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "401": {
@@ -655,19 +703,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "У вас нет прав для изменения статуса этой задачи",
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM, Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "404": {
-                        "description": "Задача не найдена",
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM, Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обновлении статуса задачи",
+                        "description": "Ошибка при получении встреч",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -675,9 +723,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team": {
-            "put": {
-                "description": "Обновляет название и описание команды. Доступно только для менеджеров.",
+        "/meetings/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет встречу из расписания команды. Доступно только для менеджеров.",
                 "consumes": [
                     "application/json"
                 ],
@@ -685,36 +747,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "meetings"
                 ],
-                "summary": "Изменение информации о команде",
+                "summary": "Удаление встречи",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID встречи",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Данные для обновления команды",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/team.CreateTeamInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Команда успешно обновлена",
+                        "description": "Встреча успешно удалена",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации или отсутствует telegram_id",
+                        "description": "ID встречи не указан",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -726,27 +779,49 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                        "description": "Error: Только руководитель может управлять встречами Code: FORBIDDEN, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "404": {
-                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND",
+                        "description": "Встреча не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обновлении команды",
+                        "description": "Ошибка при удалении встречи",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/org": {
             "post": {
-                "description": "Создает команду, если запрос исходит от пользователя с ролью manager.",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт организацию, пользователь становится её владельцем. Владелец видит все отделы и команды организации.",
                 "consumes": [
                     "application/json"
                 ],
@@ -754,86 +829,104 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Создание команды",
+                "summary": "Создание организации",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные команды",
+                        "description": "Данные организации",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/team.CreateTeamInput"
+                            "$ref": "#/definitions/org.CreateOrganizationInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о созданной команде",
+                        "description": "Созданная организация",
                         "schema": {
-                            "$ref": "#/definitions/response.TeamResponse"
+                            "$ref": "#/definitions/response.OrganizationResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации или отсутствует telegram_id",
+                        "description": "Ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                    "401": {
+                        "description": "Пользователь не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка создания команды",
+                        "description": "Ошибка при создании организации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет команду и очищает связи со всеми участниками. Доступно только для владельца команды.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            }
+        },
+        "/org/departments/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Переименовывает отдел (доступно и его руководителю), меняет руководителя или переносит отдел в другой родительский. Руководителя и положение в структуре меняет владелец организации или руководитель родительского отдела. Отдел нельзя перенести внутрь самого себя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Удаление команды",
+                "summary": "Изменение отдела",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID отдела",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Изменения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/org.UpdateDepartmentInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Команда успешно удалена",
+                        "description": "Изменённый отдел",
                         "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
+                            "$ref": "#/definitions/response.DepartmentResponse"
                         }
                     },
                     "400": {
-                        "description": "Отсутствует telegram_id",
+                        "description": "Error: Отдел нельзя перенести внутрь самого себя Code: DEPARTMENT_CYCLE",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "401": {
@@ -843,23 +936,41 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Error: Только руководитель может удалить команду Code: ONLY_MANAGER_DELETE_TEAM, Error: Вы не являетесь владельцем команды Code: NOT_OWNER_OF_TEAM",
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND, Error: Пользователь не найден Code: USER_NOT_FOUND",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при удалении команды",
+                        "description": "Ошибка при изменении отдела",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/team/invite": {
-            "get": {
-                "description": "Возвращает ссылку для приглашения новых участников в команду. Доступно только для менеджеров.",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет отдел без вложенных отделов и команд. Доступно владельцу организации и руководителю родительского отдела.",
                 "consumes": [
                     "application/json"
                 ],
@@ -867,29 +978,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Получение ссылки-приглашения",
+                "summary": "Удаление отдела",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID отдела",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "URL ссылки-приглашения",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Отсутствует telegram_id",
+                        "description": "Отдел удалён",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
                     "401": {
@@ -899,19 +1004,25 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "404": {
-                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND",
+                        "description": "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: В отделе есть вложенные отделы или команды Code: DEPARTMENT_NOT_EMPTY",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при создании/получении ссылки-приглашения",
+                        "description": "Ошибка при удалении отдела",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -919,9 +1030,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/join": {
+        "/org/departments/{id}/teams": {
             "post": {
-                "description": "Позволяет пользователю присоединиться к команде, используя пригласительный код.",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Делает команду частью отдела. Нужны права на отдел (руководитель отдела, одного из родительских или владелец организации) и права руководителя в самой команде. Команда может входить только в один отдел, прежняя привязка заменяется.",
                 "consumes": [
                     "application/json"
                 ],
@@ -929,23 +1054,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Присоединение к команде",
+                "summary": "Добавление команды в отдел",
                 "parameters": [
                     {
-                        "description": "Данные для присоединения к команде",
+                        "type": "string",
+                        "description": "ID отдела",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Команда",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/team.InviteJoinRequest"
+                            "$ref": "#/definitions/org.AttachTeamInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное присоединение к команде",
+                        "description": "Команда добавлена в отдел",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessResponse"
                         }
@@ -956,20 +1088,32 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Error: Команда не найдена CODE: INVITE_CODE_INVALID, Error: Команда не найдена. CODE: TEAM_NOT_FOUND, Error: Пользователь не найден. Зарегистрируйтесь через бота. CODE: USER_NOT_FOUND",
+                        "description": "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "409": {
-                        "description": "Вы уже присоединились к этой команде",
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при присоединении к команде",
+                        "description": "Ошибка при добавлении команды",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -977,9 +1121,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/kick": {
-            "get": {
-                "description": "Позволяет менеджеру исключить участника из команды",
+        "/org/departments/{id}/teams/{team_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Убирает команду из отдела. Доступно руководителю команды и руководителю отдела.",
                 "consumes": [
                     "application/json"
                 ],
@@ -987,38 +1145,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Исключить участника из команды",
+                "summary": "Исключение команды из отдела",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID отдела",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram участника, который будет исключен",
-                        "name": "kick_telegram_id",
-                        "in": "query",
+                        "description": "ID команды",
+                        "name": "team_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Участник успешно исключен из команды",
+                        "description": "Команда исключена из отдела",
                         "schema": {
                             "$ref": "#/definitions/response.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Отсутствует telegram_id или kick_telegram_id",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
                     "401": {
                         "description": "Пользователь не найден",
                         "schema": {
@@ -1026,13 +1178,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Error: Только менеджер может исключить участника из команды. CODE: NOT_MANAGER, Error: Пользователь не находится в вашей команде, CODE: NOT_IN_TEAM",
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Команда не входит в этот отдел Code: TEAM_NOT_IN_DEPARTMENT",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при попытке исключить участника из команды",
+                        "description": "Ошибка при исключении команды",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1040,9 +1198,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/leave": {
+        "/org/my": {
             "get": {
-                "description": "Позволяет пользователю выйти из текущей команды",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает организации, которыми пользователь владеет или в которых руководит отделом.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1050,29 +1222,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
-                ],
-                "summary": "Покинуть команду",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
-                        "required": true
-                    }
+                    "org"
                 ],
+                "summary": "Мои организации",
                 "responses": {
                     "200": {
-                        "description": "Команда успешно покинута",
-                        "schema": {
-                            "$ref": "#/definitions/response.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Отсутствует telegram_id",
+                        "description": "Список организаций",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.OrganizationResponse"
+                            }
                         }
                     },
                     "401": {
@@ -1081,20 +1241,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Manager не может просто так покинуть команду",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorCodeResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Ошибка при попытке покинуть команду",
+                        "description": "Ошибка при получении организаций",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1102,9 +1250,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/members": {
+        "/org/{id}": {
             "get": {
-                "description": "Возвращает список всех участников команды, кроме текущего пользователя. Доступно только для менеджеров.",
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает дерево отделов организации с командами. Владелец организации видит всё дерево, руководитель отдела — свои отделы вместе с вложенными.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1112,32 +1274,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Получение списка участников команды",
+                "summary": "Структура организации",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID организации",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список участников команды",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/response.UserResponse"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Отсутствует telegram_id или ошибка валидации",
+                        "description": "Структура организации",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.OrganizationTreeResponse"
                         }
                     },
                     "401": {
@@ -1147,19 +1300,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Доступ запрещен (не менеджер)",
+                        "description": "Error: Нет доступа к организации Code: NOT_IN_ORGANIZATION",
                         "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "404": {
-                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND",
+                        "description": "Error: Организация не найдена Code: ORGANIZATION_NOT_FOUND",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении участников команды",
+                        "description": "Ошибка при получении структуры организации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1167,9 +1320,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/my": {
-            "get": {
-                "description": "Возвращает данные о команде, к которой принадлежит пользователь",
+        "/org/{id}/departments": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт отдел в организации. Отдел верхнего уровня создаёт владелец организации, вложенный — владелец или руководитель родительского отдела.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1177,27 +1344,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "org"
                 ],
-                "summary": "Получение информации о своей команде",
+                "summary": "Создание отдела",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Уникальный идентификатор Telegram",
-                        "name": "telegram_id",
-                        "in": "query",
+                        "description": "ID организации",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Данные отдела",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/org.CreateDepartmentInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о команде",
+                        "description": "Созданный отдел",
                         "schema": {
-                            "$ref": "#/definitions/response.TeamResponse"
+                            "$ref": "#/definitions/response.DepartmentResponse"
                         }
                     },
                     "400": {
-                        "description": "Отсутствует telegram_id",
+                        "description": "Ошибка валидации или родительский отдел из другой организации",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1208,49 +1384,325 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER, Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Error:Отсутствует команда у пользователя Сode:USER_HAS_NO_TEAM, Error: Команда не найдена Сode:TEAM_NOT_FOUND",
+                        "description": "Error: Организация не найдена Code: ORGANIZATION_NOT_FOUND, Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND, Error: Пользователь не найден Code: USER_NOT_FOUND",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorCodeResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании отдела",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/user": {
+        "/search": {
             "get": {
-                "description": "Получает информацию о пользователе по его Telegram ID.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
                 ],
-                "summary": "Получение информации о пользователе",
+                "description": "Ищет по заголовкам и описаниям задач, текстам комментариев и названиям встреч в командах пользователя. Запрос понимает словоформы на русском и английском, \"точные фразы\", OR и -исключения. Результаты упорядочены по релевантности, в snippet найденные слова выделены тегами \u003cb\u003e\u003c/b\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Полнотекстовый поиск",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Telegram ID of the user",
-                        "name": "telegram_id",
+                        "description": "Поисковый запрос",
+                        "name": "q",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Типы через запятую: task, comment, meeting (по умолчанию все)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Искать только в этой команде",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество результатов, от 1 до 100 (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о пользователе",
+                        "description": "Результаты поиска",
                         "schema": {
-                            "$ref": "#/definitions/response.UserInfoResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.SearchResultResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Некорректный тип: task1 Code: INVALID_SEARCH_TYPE, Error: limit должен быть от 1 до 100 Code: INVALID_LIMIT",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка поиска",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает одним списком задачи команды и персональные задачи пользователя из всех команд. Список можно отфильтровать по статусу, приоритету, меткам, исполнителю, сроку, просрочке и отсутствию оценки и отсортировать по сроку, дате создания, приоритету или оценке.\nСписок выдаётся страницами по limit задач: чтобы получить следующую, передайте next_cursor из ответа в cursor с теми же фильтрами и сортировкой. total — количество задач, подходящих под фильтры.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получение списка задач",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID отдела: задачи всех команд отдела и вложенных отделов (для руководителя отдела и владельца организации)",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статусы через запятую",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Приоритеты через запятую: low, medium, high, urgent",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID меток через запятую: задачи хотя бы с одной из них",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Telegram ID исполнителя или me: его персональные задачи и командные задачи, над которыми он работает",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339)",
+                        "name": "deadline_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не позже (RFC 3339)",
+                        "name": "deadline_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только просроченные незавершённые задачи",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только задачи без оценки",
+                        "name": "unestimated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: deadline (по умолчанию), created_at, priority, story_points, estimate_hours",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: asc (по умолчанию) или desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, от 1 до 200 (по умолчанию 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor предыдущей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskPageResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации или отсутствует telegram_id",
+                        "description": "Error: Курсор не подходит к запросу Code: INVALID_CURSOR",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении задач",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создание задачи для команды и индивидуально. Командная задача заводится у каждого участника команды, который может работать с задачами; правило completion_rule определяет, когда она считается выполненной.\nС parent_id задача создаётся подзадачей другой задачи команды: её срок должен быть не позже срока родительской, а прогресс родительской задачи складывается из прогресса подзадач.\nЗадаче можно указать приоритет, оценку в story points и часах и метки команды (label_ids, см. GET /team/labels).\nС recurrence задача становится первой в серии повторяющихся задач: следующая создаётся автоматически, когда выполнена предыдущая или наступил её срок (см. GET /tasks/series).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Создание задачи",
+                "parameters": [
+                    {
+                        "description": "Информация задачи",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.TaskInput"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача успешно создана",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: в правиле нет FREQ Code: INVALID_RECURRENCE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
                     },
                     "401": {
                         "description": "Пользователь не найден",
@@ -1258,309 +1710,8106 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка создания команды",
+                        "description": "Ошибка при создании задачи",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.RegisterInput": {
-            "type": "object",
-            "required": [
-                "name",
-                "role",
-                "telegram_id"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "role": {
-                    "description": "\"manager\" или \"member\"",
-                    "type": "string",
-                    "enum": [
-                        "manager",
-                        "member"
-                    ]
-                },
-                "telegram_id": {
+        },
+        "/tasks/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Меняет статус (status), исполнителя (assign), срок (deadline) или удаляет (delete) до 100 задач за один запрос в одной транзакции.\nПрава и ограничения проверяются для каждой задачи так же, как в PUT /tasks/{id}/status (для status) и DELETE /tasks/{id} (для остальных операций): если операция невозможна хотя бы для одной задачи, не меняется ни одна, а в ответе перечисляются причины по задачам.\nИсполнителя можно назначить только персональным задачам. Подзадачи удаляемых задач можно удалить в том же запросе, а срок подзадачи проверяется по новому сроку родителя, если он тоже в запросе.\nВместо уведомления на каждую задачу каждый получатель получает одно уведомление со списком изменённых задач: при смене статуса — авторы задач, при остальных операциях — исполнители.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Массовые операции над задачами",
+                "parameters": [
+                    {
+                        "description": "Задачи и операция",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.BulkTaskInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат операции",
+                        "schema": {
+                            "$ref": "#/definitions/response.BulkTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Для операции assign укажите assigned_to Code: BULK_VALUE_REQUIRED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Задачи не найдены: 3, 7 Code: TASK_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Операция невозможна для 2 из 5 задач, ничего не изменено Code: BULK_REJECTED",
+                        "schema": {
+                            "$ref": "#/definitions/response.BulkTaskErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка массового изменения задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/department": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Руководитель отдела или владелец организации ставит командную задачу выбранным командам отдела и вложенных отделов. Каждая команда получает свою копию задачи с отметкой отдела, участники команд получают уведомление. Архивные команды пропускаются, если не указаны явно.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Создание межкомандной задачи",
+                "parameters": [
+                    {
+                        "description": "Информация задачи",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.DepartmentTaskInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданные задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Команда не входит в отдел Code: TEAM_NOT_IN_DEPARTMENT, Error: В отделе нет команд Code: DEPARTMENT_HAS_NO_TEAMS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/issued": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список задач, созданных текущим пользователем.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Получить выданные задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статусы через запятую",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Приоритеты через запятую: low, medium, high, urgent",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID меток через запятую: задачи хотя бы с одной из них",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Telegram ID исполнителя",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не раньше (RFC 3339)",
+                        "name": "deadline_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок не позже (RFC 3339)",
+                        "name": "deadline_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только просроченные незавершённые задачи",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только задачи без оценки",
+                        "name": "unestimated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: deadline (по умолчанию), created_at, priority, story_points, estimate_hours",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: asc (по умолчанию) или desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список выданных задач",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: deadline_from указывается в формате RFC 3339 Code: INVALID_DATE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает работы исполнителей по задачам, которые создал текущий пользователь. По умолчанию — только ожидающие решения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Работы на проверке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending (по умолчанию), approved, rejected или all",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Работы на проверке",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskReviewResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Некорректный статус Code: INVALID_STATUS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении работ на проверке",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/reviews/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Автор задачи принимает работу: работа переходит из статуса проверки в завершающий статус процесса команды (completed в стандартном процессе) — у персональной задачи это статус самой задачи, у командной — статус участника, после чего общий статус пересчитывается по правилу выполнения. Исполнитель получает уведомление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Принять работу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID работы на проверке",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/tasks.ReviewDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Работа принята",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskReviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Работа на проверке не найдена Code: REVIEW_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении решения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/reviews/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Автор задачи отклоняет работу с комментарием: работа возвращается из статуса проверки в рабочий статус процесса команды (in_progress в стандартном процессе), исполнитель получает комментарий.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Вернуть работу на доработку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID работы на проверке",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отклонения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.ReviewDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Работа возвращена на доработку",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Укажите, что нужно доработать Code: COMMENT_REQUIRED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Работа на проверке не найдена Code: REVIEW_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении решения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/series": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает серии повторяющихся задач команды: шаблон задачи, правило повторения, срок следующей задачи и последнюю созданную задачу. С active=true — только серии, по которым ещё будут созданы задачи.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Повторяющиеся задачи команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только действующие серии",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Серии задач",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskSeriesResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении серий задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/series/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает шаблон и правило повторения серии. Доступно участникам команды и автору серии.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Серия повторяющихся задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Серия задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskSeriesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Серия задач не найдена Code: SERIES_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Меняет шаблон задачи и правило повторения. Изменения применяются к следующим задачам серии, уже созданные задачи не меняются. С next_at серия отсчитывается заново от этого срока; при смене правила без next_at следующий срок пересчитывается от начала серии. COUNT учитывает все созданные задачи серии. Доступно автору серии и владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Изменение серии задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateSeriesInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Серия задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Серия задач не найдена Code: SERIES_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Серия задач остановлена Code: SERIES_STOPPED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении серии задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Прекращает создание новых задач серии. Уже созданные задачи остаются. Доступно автору серии и владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Остановка серии задач",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID серии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Остановленная серия",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskSeriesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Серия задач не найдена Code: SERIES_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Серия задач уже остановлена Code: SERIES_STOPPED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при остановке серии задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет заголовок, описание, дедлайн, исполнителя, родительскую задачу, приоритет, оценку, метки или переключает задачу между командной и персональной. Передаются только изменяемые поля. Срок подзадачи не может быть позже срока родительской задачи, а срок задачи — раньше сроков её подзадач. Участники, которых касалась задача до или после изменения, получают одно уведомление со списком изменений. Доступно автору задачи и владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Изменение задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля задачи",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateTaskInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача и список изменений",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Метка не найдена в команде Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаление задачи её автором или владельцем команды. Автор должен оставаться руководителем команды. Задачу с подзадачами удалить нельзя — сначала удалите подзадачи или отвяжите их.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удаление задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача успешно удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: task_id is required CODE: NOT_TASK_ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: У задачи есть подзадачи Code: TASK_HAS_SUBTASKS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/assignments": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает статус выполнения командной задачи у каждого текущего участника команды. Доступно участникам команды и руководителю отдела, в который входит команда.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Статусы участников командной задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статусы участников",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskAssignmentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Задача не командная Code: NOT_TEAM_TASK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении статусов участников",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все файлы задачи, в том числе приложенные к комментариям и к работам на проверке. Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Файлы задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файлы задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.AttachmentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении файлов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Загружает файл (multipart/form-data, поле file) к задаче. Тип файла определяется по содержимому и должен входить в ATTACHMENT_ALLOWED_TYPES (по умолчанию картинки, PDF, текст и zip, в том числе документы Office), размер — не больше ATTACHMENT_MAX_SIZE_MB (по умолчанию 20 МБ).\nЗагруженный файл виден в задаче; чтобы приложить его к комментарию или к работе на проверку, передайте его ID в attachment_ids. Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Загрузка файла к задаче",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Файл загружен",
+                        "schema": {
+                            "$ref": "#/definitions/response.AttachmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Файл не передан Code: FILE_REQUIRED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Error: Файл больше 20 МБ Code: FILE_TOO_LARGE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Error: Файлы типа application/x-msdownload загружать нельзя Code: UNSUPPORTED_FILE_TYPE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении файла",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments/{attachment_id}": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отдаёт файл задачи с исходным именем. Ссылка на скачивание приходит в поле url описания файла; доступ проверяется так же, как к самой задаче.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Скачивание файла задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID файла",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Содержимое файла",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Файл не найден Code: ATTACHMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при чтении файла",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет файл из задачи и из хранилища. Доступно загрузившему файл и автору задачи.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удаление файла задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID файла",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Удалить файл может только загрузивший его или автор задачи Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Файл не найден Code: ATTACHMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении файла",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пункты чек-листа задачи по порядку. Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Чек-лист задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пункты чек-листа",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.ChecklistItemResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении чек-листа",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет пункт в конец чек-листа задачи. Доступно автору задачи и исполнителям: исполнителю персональной задачи или участникам команды, работающим с командной задачей.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Пункт чек-листа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст пункта",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.ChecklistItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Пункт добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ChecklistItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Текст пункта не может быть пустым",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении пункта",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/checklist/{item_id}": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает пункт выполненным (done=true), снимает отметку или меняет текст. Доступно автору задачи и исполнителям.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Изменение пункта чек-листа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пункта",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateChecklistItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пункт изменён",
+                        "schema": {
+                            "$ref": "#/definitions/response.ChecklistItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Текст пункта не может быть пустым",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Пункт чек-листа не найден Code: CHECKLIST_ITEM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении пункта",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет пункт из чек-листа задачи. Доступно автору задачи и исполнителям.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удаление пункта чек-листа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пункта",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пункт удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Пункт чек-листа не найден Code: CHECKLIST_ITEM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении пункта",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает комментарии задачи деревом: у каждого комментария — ответы в replies. Удалённый комментарий, на который есть ответы, возвращается без текста с deleted=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Комментарии к задаче",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарии",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskCommentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении комментариев",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет комментарий или ответ на комментарий (parent_id). Участники команды, упомянутые через @Имя, @Имя_Фамилия, @TelegramID или в списке mentions, и автор комментария, на который дан ответ, получают уведомление в Telegram. Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Комментарий к задаче",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст комментария",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.CommentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Комментарий добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Файл не найден среди ваших вложений к задаче Code: ATTACHMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/comments/{comment_id}": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет текст собственного комментария. Уведомление получают только участники, которые упомянуты впервые. Автор, вышедший из команды, свои комментарии изменять не может.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Изменение комментария",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID комментария",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый текст",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateCommentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий изменён",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Комментарий не может быть пустым",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Можно изменять только свои комментарии Code: NOT_COMMENT_AUTHOR, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Комментарий не найден Code: COMMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет собственный комментарий вместе с приложенными к нему файлами. Ответы на него остаются в обсуждении. Автор, вышедший из команды, свои комментарии удалять не может.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удаление комментария",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID комментария",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Можно удалять только свои комментарии Code: NOT_COMMENT_AUTHOR, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Комментарий не найден Code: COMMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении комментария",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает задачи, которые блокируют эту (blocked_by), и задачи, которые ждут её завершения (blocks). Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Зависимости задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Зависимости",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskDependenciesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении зависимостей",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Указывает, что задачу нельзя начать, пока не завершена задача blocker_id. Блокирующая задача должна быть доступна пользователю; зависимость, замыкающая цепочку блокировок в цикл, отклоняется. Доступно автору задачи и владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Блокирующая задача",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Блокирующая задача",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.DependencyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Зависимости задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskDependenciesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Задача не может блокировать саму себя Code: SELF_DEPENDENCY",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Блокирующая задача не найдена Code: BLOCKER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Зависимость уже добавлена Code: DEPENDENCY_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении зависимости",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/dependencies/{blocker_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Убирает блокирующую задачу. Если у задачи больше не осталось незавершённых блокирующих задач, исполнители получают уведомление. Доступно автору задачи и владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Удаление зависимости",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID блокирующей задачи",
+                        "name": "blocker_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Зависимости задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskDependenciesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Зависимость не найдена Code: DEPENDENCY_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении зависимости",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает события задачи в хронологическом порядке: создание, изменения полей, переназначение, переходы статусов с отчётом и вложением, решения по проверке и удаление. История удалённой задачи остаётся доступной. Доступно автору задачи, участникам команды и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "История задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskEventResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении истории задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все отправки работы на проверку по задаче и решения автора. Доступно автору задачи, участникам команды и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "История проверок задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История проверок",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskReviewResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении истории проверок",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Обновление статуса задачи участником команды или исполнителем персональной задачи.\nДопустимые статусы и переходы задаёт процесс команды (GET /team/workflow): переход должен быть в процессе и разрешён роли пользователя.\nЗавершающий статус (completed в стандартном процессе) отправляет работу на проверку, если из текущего статуса есть переход в статус проверки: автор получает уведомление и принимает или отклоняет работу через /tasks/reviews/{id}/approve и /tasks/reviews/{id}/reject. Автор собственной задачи завершает её сразу.\nУ командной задачи меняется статус текущего участника, а общий статус задачи пересчитывается по правилу completion_rule: all — выполнена, когда выполнили все участники, any — любой, quorum — не меньше quorum_percent участников.\nЗадачу с незавершёнными блокирующими задачами (GET /tasks/{id}/dependencies) нельзя вывести из начального статуса. Когда завершается последняя блокирующая задача, исполнители зависимой задачи получают уведомление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Обновление статуса задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления статуса",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateTaskStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статус задачи успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Файл не найден среди ваших вложений к задаче Code: ATTACHMENT_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении статуса задачи",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/subtasks": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает прямые подзадачи задачи по сроку. Доступно участникам команды, автору задачи и руководителю отдела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Подзадачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подзадачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении подзадач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет название, описание и настройку одобрения заявок на вступление. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Изменение информации о команде",
+                "parameters": [
+                    {
+                        "description": "Данные для обновления команды",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.CreateTeamInput"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда успешно обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создает команду. Пользователь, создавший команду, получает в ней роль owner, новая команда становится активной.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Создание команды",
+                "parameters": [
+                    {
+                        "description": "Данные команды",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.CreateTeamInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о созданной команде",
+                        "schema": {
+                            "$ref": "#/definitions/response.TeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Переводит команду в архив: задачи и встречи доступны только для просмотра, вступление по приглашениям и управление командой отключены, неотвеченные предложения о передаче команды отменяются. Участники и данные команды сохраняются, команду можно восстановить. Доступно только владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Архивация команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда перенесена в архив",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может удалить команду Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда уже в архиве Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при архивации команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/active": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Делает команду активной: запросы к задачам, встречам и команде без параметра team_id выполняются для неё.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Выбор активной команды",
+                "parameters": [
+                    {
+                        "description": "ID команды",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.SetActiveTeamInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Активная команда",
+                        "schema": {
+                            "$ref": "#/definitions/response.MyTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Команда не найдена Code: TEAM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при выборе команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/archive": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Переводит команду в архив: задачи и встречи доступны только для просмотра, вступление по приглашениям и управление командой отключены, неотвеченные предложения о передаче команды отменяются. Участники и данные команды сохраняются, команду можно восстановить. Доступно только владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Архивация команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда перенесена в архив",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может удалить команду Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда уже в архиве Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при архивации команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/invite": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт ссылку для приглашения новых участников в команду с настройками по умолчанию: роль member, без ограничения использований, срок 24 часа. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Получение ссылки-приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "URL ссылки-приглашения",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании/получении ссылки-приглашения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/invites": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает действующие ссылки-приглашения команды со счётчиками использований. С параметром all=true возвращает также отозванные, истёкшие и исчерпанные ссылки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Список ссылок-приглашений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включить недействующие ссылки",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ссылки-приглашения",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.InviteResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении ссылок",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт ссылку с ролью для вступивших, ограничением числа использований и сроком действия (до 720 часов). Приглашать сразу в руководители может только владелец.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Создание ссылки-приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Параметры приглашения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.CreateInviteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданная ссылка",
+                        "schema": {
+                            "$ref": "#/definitions/response.InviteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Приглашать руководителей может только владелец Code: ROLE_TOO_HIGH",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения ссылки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/invites/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает ссылку: по ней больше нельзя вступить в команду. Запись остаётся в списке с параметром all=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Отзыв ссылки-приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код приглашения",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отозванная ссылка",
+                        "schema": {
+                            "$ref": "#/definitions/response.InviteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Ссылка-приглашение не найдена Code: INVITE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отзыве ссылки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/invites/{code}/uses": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователей, вступивших в команду по ссылке, с ролью и временем вступления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Использования ссылки-приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код приглашения",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вступившие пользователи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.InviteUseResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Ссылка-приглашение не найдена Code: INVITE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении использований",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/join": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Позволяет пользователю присоединиться к команде, используя пригласительный код. Роль в команде задаётся приглашением. Пользователь может состоять в нескольких командах, новая команда становится активной.\nЕсли команда требует одобрения, вместо вступления создаётся заявка (ответ 202), а владелец и руководители получают уведомление с кнопками одобрения и отклонения. Заявка расходует одно использование приглашения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Присоединение к команде",
+                "parameters": [
+                    {
+                        "description": "Данные для присоединения к команде",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.InviteJoinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное присоединение к команде",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Заявка на вступление отправлена руководителю",
+                        "schema": {
+                            "$ref": "#/definitions/response.JoinRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Неверный или просроченный код приглашения CODE: INVITE_CODE_INVALID, Error: Приглашение уже использовано максимальное число раз CODE: INVITE_EXHAUSTED, Error: Команда не найдена. CODE: TEAM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Вы уже присоединились к этой команде, Error: Заявка на вступление уже ожидает решения Code: JOIN_REQUEST_PENDING, Error: Команда в архиве, вступление закрыто Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при присоединении к команде",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/kick": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Позволяет владельцу или руководителю исключить участника из команды. Руководителей может исключить только владелец, владельца исключить нельзя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Исключить участника из команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный идентификатор Telegram участника, который будет исключен",
+                        "name": "kick_telegram_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник успешно исключен из команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Отсутствует kick_telegram_id",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой CODE: FORBIDDEN, Error: Пользователь не находится в вашей команде CODE: NOT_IN_TEAM, Error: Недостаточно прав для исключения этого участника CODE: ROLE_TOO_HIGH",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при попытке исключить участника из команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/labels": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает метки команды по названию и количество задач с каждой меткой.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Метки команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Метки",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.LabelResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении меток",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт метку команды с цветом в формате #RRGGBB. Названия меток в команде не повторяются. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Создание метки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Метка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.LabelInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Метка создана",
+                        "schema": {
+                            "$ref": "#/definitions/response.LabelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Цвет метки указывается в формате #RRGGBB Code: INVALID_COLOR",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Метка с таким названием уже есть Code: LABEL_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании метки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/labels/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Меняет название или цвет метки. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Изменение метки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.UpdateLabelInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Метка",
+                        "schema": {
+                            "$ref": "#/definitions/response.LabelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Цвет метки указывается в формате #RRGGBB Code: INVALID_COLOR",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Метка не найдена Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Метка с таким названием уже есть Code: LABEL_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении метки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет метку и снимает её со всех задач, серий и шаблонов команды. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Удаление метки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID метки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Метка удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Метка не найдена Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении метки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/leave": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Позволяет пользователю выйти из текущей команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Покинуть команду",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда успешно покинута",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Владелец не может покинуть команду. Сначала передайте права владельца другому участнику Code: OWNER_CANNOT_LEAVE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при попытке покинуть команду",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/members": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список всех участников команды с их ролями, кроме текущего пользователя. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Получение списка участников команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список участников команды",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.MemberResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении участников команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/members/role": {
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Владелец может назначить участнику роль manager, member или observer. Руководитель может переключать между member и observer только обычных участников.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Изменение роли участника",
+                "parameters": [
+                    {
+                        "description": "Участник и новая роль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.ChangeMemberRoleInput"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Участник с новой ролью",
+                        "schema": {
+                            "$ref": "#/definitions/response.MemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Пользователь не находится в вашей команде CODE: NOT_IN_TEAM, Error: Недостаточно прав для изменения роли этого участника CODE: ROLE_TOO_HIGH",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении роли",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/my": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все команды, в которых состоит пользователь, с его ролью в каждой и отметкой активной команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Получение списка своих команд",
+                "responses": {
+                    "200": {
+                        "description": "Команды пользователя",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.MyTeamResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении команд",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/purge": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает количество задач, встреч, приглашений, участников и других записей, которые будут безвозвратно удалены вместе с архивной командой. Доступно только владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Предпросмотр окончательного удаления команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Что будет удалено",
+                        "schema": {
+                            "$ref": "#/definitions/response.TeamPurgeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может удалить команду Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при подсчёте данных команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "В одной транзакции безвозвратно удаляет архивную команду вместе с задачами, встречами, приглашениями, участиями, заявками и предложениями о передаче. У участников, для которых команда была активной, активной становится другая их команда. Возвращает количество удалённых записей. Доступно только владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Окончательное удаление команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Удалённые данные",
+                        "schema": {
+                            "$ref": "#/definitions/response.TeamPurgeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может удалить команду Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/requests": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает ожидающие решения заявки на вступление. С параметром status=all возвращается и история: одобренные и отклонённые заявки с датой решения и руководителем, который его принял. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Заявки на вступление в команду",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (по умолчанию), approved, rejected или all",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список заявок",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.JoinRequestResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный статус",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении заявок",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Пользователь становится участником команды с ролью из приглашения и получает уведомление. Если у него нет активной команды, она становится активной. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Одобрение заявки на вступление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка одобрена",
+                        "schema": {
+                            "$ref": "#/definitions/response.JoinRequestResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Заявка не найдена Code: JOIN_REQUEST_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Заявка уже рассмотрена Code: JOIN_REQUEST_CLOSED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при одобрении заявки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/requests/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Заявка отклоняется, пользователь получает уведомление. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Отклонение заявки на вступление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявка отклонена",
+                        "schema": {
+                            "$ref": "#/definitions/response.JoinRequestResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Заявка не найдена Code: JOIN_REQUEST_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Заявка уже рассмотрена Code: JOIN_REQUEST_CLOSED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отклонении заявки",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/restore": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Снимает с команды режим только для просмотра. Ссылки-приглашения, срок которых не истёк, снова начинают действовать. Доступно только владельцу команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Восстановление команды из архива",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда восстановлена",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может удалить команду Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при восстановлении команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/templates": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает шаблоны задач команды по названию. В variables перечислены переменные {{name}}, значения которых нужно передать при создании задач; {{assignee}} и {{date}} подставляются автоматически.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Шаблоны задач команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблоны",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskTemplateResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении шаблонов",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт шаблон задачи команды. В заголовке, описании и пунктах чек-листа можно использовать переменные {{name}}; {{assignee}} заменяется именем исполнителя (для командной задачи — названием команды), {{date}} — датой, от которой отсчитывается срок. Срок задаётся относительно создания задачи. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Создание шаблона задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Шаблон",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.TemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Шаблон создан",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Шаблон с таким названием уже есть Code: TEMPLATE_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает шаблон задачи команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Шаблон задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskTemplateResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет шаблон задачи целиком, включая чек-лист. Задачи, уже созданные по шаблону, не меняются. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Изменение шаблона задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Шаблон",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.TemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон",
+                        "schema": {
+                            "$ref": "#/definitions/response.TaskTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Шаблон с таким названием уже есть Code: TEMPLATE_EXISTS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет шаблон. Задачи, созданные по шаблону, остаются. Доступно тем, кто управляет задачами команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Удаление шаблона задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Шаблон удалён",
+                        "schema": {
+                            "$ref": "#/definitions/response.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении шаблона",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/templates/{id}/instantiate": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт задачи по шаблону в одной транзакции: если хотя бы одну задачу создать нельзя, не создаётся ни одна. Каждый элемент items — свои значения переменных и срок; по командному шаблону элемент даёт одну командную задачу, по персональному — по задаче каждому исполнителю из assignees (по умолчанию — исполнители шаблона, которые ещё состоят в команде). Срок считается от start_at (по умолчанию — сейчас), если не указан deadline. Задачи получают чек-лист и метки шаблона, исполнители — уведомления. За один запрос можно создать до 100 задач.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Создание задач по шаблону",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Значения переменных, исполнители и сроки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tasks.InstantiateTemplateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданные задачи",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: За один раз можно создать не больше 100 задач Code: TOO_MANY_TASKS",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять задачами Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании задач",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/transfer": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает неотвеченные и не истёкшие предложения стать владельцем команды, адресованные текущему пользователю.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Входящие предложения о передаче команды",
+                "responses": {
+                    "200": {
+                        "description": "Список предложений",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.OwnershipTransferResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении предложений",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт предложение передать команду участнику. Участник получает уведомление в Telegram и должен принять его в течение 48 часов. После принятия прежний владелец становится руководителем. Предыдущее неотвеченное предложение по команде отменяется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Передача прав владельца",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Новый владелец",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/team.TransferOwnershipInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Предложение создано",
+                        "schema": {
+                            "$ref": "#/definitions/response.OwnershipTransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или попытка передать команду самому себе",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только владелец может передать команду Code: FORBIDDEN, Error: Пользователь не находится в вашей команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании предложения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/transfer/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Владелец отзывает своё предложение, пока получатель на него не ответил.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Отзыв предложения о передаче команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID предложения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Предложение отозвано",
+                        "schema": {
+                            "$ref": "#/definitions/response.OwnershipTransferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Отозвать предложение может только его автор Code: TRANSFER_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отзыве предложения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/transfer/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Новый владелец принимает предложение. Прежний владелец становится руководителем команды и получает уведомление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Принятие прав владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID предложения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Права владельца переданы",
+                        "schema": {
+                            "$ref": "#/definitions/response.OwnershipTransferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Предложение адресовано другому пользователю Code: TRANSFER_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED, Error: Предложение больше не действительно Code: TRANSFER_STALE",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при передаче прав",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/transfer/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получатель отклоняет предложение, владелец получает уведомление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Отклонение прав владельца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID предложения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Предложение отклонено",
+                        "schema": {
+                            "$ref": "#/definitions/response.OwnershipTransferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Предложение адресовано другому пользователю Code: TRANSFER_FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отклонении предложения",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/workflow": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает статусы задач команды по порядку и разрешённые переходы с ролями, которые могут их выполнять. Если команда не настраивала процесс, возвращается стандартный: assigned → in_progress → in_review → completed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Процесс работы над задачами команды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Процесс команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.WorkflowResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении процесса команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет статусы и переходы команды. Первый статус назначается новым задачам, завершающие статусы (terminal) означают, что работа выполнена, а в статусе проверки (review) работа ждёт решения автора задачи — из него нужны переходы в завершающий статус и обратно в работу. Для каждого перехода можно ограничить роли. Статусы, в которых сейчас находятся задачи команды, убрать нельзя. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Настройка процесса работы над задачами",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Статусы и переходы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/workflow.WorkflowInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Процесс команды сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/response.WorkflowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error: в процессе нужен хотя бы один завершающий статус Code: INVALID_WORKFLOW",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении процесса команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет настроенный процесс команды, после чего задачи работают по стандартному процессу. Если задачи команды находятся в статусах, которых нет в стандартном процессе, сброс невозможен. Доступно владельцу и руководителям команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Сброс процесса к стандартному",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID команды (по умолчанию — активная команда пользователя)",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Стандартный процесс",
+                        "schema": {
+                            "$ref": "#/definitions/response.WorkflowResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Error: Только руководитель может управлять командой Code: FORBIDDEN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorCodeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сбросе процесса команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "TelegramInitData": []
+                    },
+                    {
+                        "TelegramLogin": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получает информацию о пользователе по его Telegram ID. Роль указывается для текущей команды пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение информации о пользователе",
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователе",
+                        "schema": {
+                            "$ref": "#/definitions/response.UserInfoResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания команды",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikeys.CreateAPIKeyInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "can_act_on_behalf": {
+                    "description": "Разрешить заголовок X-On-Behalf-Of",
+                    "type": "boolean"
+                },
+                "expires_in_days": {
+                    "description": "0 — бессрочный ключ",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Например: [\"tasks:read\", \"tasks:write\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_expires_at": {
+                    "type": "string"
+                },
+                "access_token": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "refresh_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Роль в текущей команде: owner, manager, member, observer или пустая строка",
+                    "type": "string"
+                },
+                "teamID": {
+                    "description": "Активная команда пользователя, все его команды — в TeamMembership",
+                    "type": "integer"
+                },
+                "telegramID": {
+                    "description": "Уникальный идентификатор Telegram",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LogoutInput": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "description": "Завершить все сессии пользователя",
+                    "type": "boolean"
+                },
+                "refresh_token": {
+                    "description": "Refresh-токен текущей сессии (опционально)",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshInput": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "access_expires_at": {
+                    "type": "string"
+                },
+                "access_token": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "refresh_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_expires_at": {
+                    "type": "string"
+                },
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "meetings.CreateMeetingInput": {
+            "type": "object",
+            "required": [
+                "date",
+                "end_time",
+                "meeting_type",
+                "start_time",
+                "title"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Формат \"YYYY-MM-DD\"",
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "Формат \"HH:MM\"",
+                    "type": "string"
+                },
+                "meeting_type": {
+                    "description": "\"online\" или \"offline\"",
+                    "type": "string"
+                },
+                "room": {
+                    "description": "Обязательное для офлайн встреч",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "Формат \"HH:MM\"",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "meetings.TimeSlot": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "Например, \"13:20\"",
+                    "type": "string"
+                },
+                "start": {
+                    "description": "Например, \"12:00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "org.AttachTeamInput": {
+            "type": "object",
+            "required": [
+                "team_id"
+            ],
+            "properties": {
+                "team_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "org.CreateDepartmentInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "head_telegram_id": {
+                    "description": "Руководитель отдела",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Родительский отдел, если не указан — отдел верхнего уровня",
+                    "type": "integer"
+                }
+            }
+        },
+        "org.CreateOrganizationInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "org.UpdateDepartmentInput": {
+            "type": "object",
+            "properties": {
+                "head_telegram_id": {
+                    "description": "Новый руководитель; пустая строка снимает руководителя",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Новое название, если пусто — не меняется",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Новый родительский отдел; 0 — сделать отдел верхнего уровня",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "can_act_on_behalf": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "created_by_key": {
+                    "description": "API-ключ, которым создан ключ",
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.APIKeySecretResponse": {
+            "type": "object",
+            "properties": {
+                "can_act_on_behalf": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "created_by_key": {
+                    "description": "API-ключ, которым создан ключ",
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.AttachmentResponse": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "integer"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "review_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Размер в байтах",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "uploader_name": {
+                    "type": "string"
+                },
+                "uploader_telegram_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Ссылка на скачивание, например /tasks/12/attachments/5",
+                    "type": "string"
+                }
+            }
+        },
+        "response.BulkTaskErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BulkTaskFailureResponse"
+                    }
+                }
+            }
+        },
+        "response.BulkTaskFailureResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP-статус, который вернул бы запрос для одной задачи",
+                    "type": "integer"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.BulkTaskResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "description": "ID удалённых задач",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tasks": {
+                    "description": "Задачи после изменения; для delete — пустой список",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskResponse"
+                    }
+                }
+            }
+        },
+        "response.ChecklistItemResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "done": {
+                    "type": "boolean"
+                },
+                "done_at": {
+                    "type": "string"
+                },
+                "done_by_name": {
+                    "type": "string"
+                },
+                "done_by_telegram_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.DepartmentResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Вложенные отделы",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DepartmentResponse"
+                    }
+                },
+                "head_name": {
+                    "type": "string"
+                },
+                "head_telegram_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DepartmentTeamResponse"
+                    }
+                }
+            }
+        },
+        "response.DepartmentTeamResponse": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ErrorCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.InviteResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "0 — без ограничения",
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.InviteUseResponse": {
+            "type": "object",
+            "properties": {
+                "joined_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.JoinRequestResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by_telegram_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, approved или rejected",
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.LabelResponse": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "#RRGGBB",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "task_count": {
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.MeetingResponse": {
+            "type": "object",
+            "properties": {
+                "conference_link": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "meeting_type": {
+                    "type": "string"
+                },
+                "room": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.MemberResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "owner, manager, member или observer",
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.MyTeamResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "Команда в архиве, если задано",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "description": "Команда выбрана активной",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "requires_approval": {
+                    "description": "Вступление требует одобрения руководителя",
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "Роль пользователя в команде",
+                    "type": "string"
+                }
+            }
+        },
+        "response.OrganizationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.OrganizationTreeResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "departments": {
+                    "description": "Отделы верхнего уровня",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.DepartmentResponse"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.OwnershipTransferResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "from_telegram_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "pending, accepted, declined или cancelled",
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "to_telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.SearchResultResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "description": "Фрагмент текста, найденные слова выделены \u003cb\u003e\u003c/b\u003e",
+                    "type": "string"
+                },
+                "task_id": {
+                    "description": "Задача, к которой относится комментарий",
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Заголовок задачи или название встречи",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "task, comment или meeting",
+                    "type": "string"
+                }
+            }
+        },
+        "response.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskAssignmentResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "completion_text": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskChangeResponse": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskCommentResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AttachmentResponse"
+                    }
+                },
+                "author_name": {
+                    "type": "string"
+                },
+                "author_telegram_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "description": "Комментарий удалён, но на него есть ответы",
+                    "type": "boolean"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mentions": {
+                    "description": "Telegram ID упомянутых участников",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskCommentResponse"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskDependenciesResponse": {
+            "type": "object",
+            "properties": {
+                "blocked_by": {
+                    "description": "Задачи, без завершения которых эту нельзя начать",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskDependencyResponse"
+                    }
+                },
+                "blocks": {
+                    "description": "Задачи, которые ждут эту",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskDependencyResponse"
+                    }
+                }
+            }
+        },
+        "response.TaskDependencyResponse": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "done": {
+                    "description": "Задача в завершающем статусе",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskEventResponse": {
+            "type": "object",
+            "properties": {
+                "actor_name": {
+                    "type": "string"
+                },
+                "actor_telegram_id": {
+                    "type": "string"
+                },
+                "attachment": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskChangeResponse"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "completion_text": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "task_status": {
+                    "description": "Общий статус задачи после события",
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "description": "Исполнитель, чей статус изменился",
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "created, updated, reassigned, status_changed, review_approved, review_rejected, deleted",
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskPageResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Пусто, если это последняя страница",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Сколько задач подходит под фильтры",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.TaskResponse": {
+            "type": "object",
+            "properties": {
+                "assigned_to": {
+                    "type": "string"
+                },
+                "attachments": {
+                    "description": "Файлы задачи, кроме приложенных к комментариям",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AttachmentResponse"
+                    }
+                },
+                "blocked_by": {
+                    "description": "Сколько незавершённых задач блокируют эту задачу",
+                    "type": "integer"
+                },
+                "checklist_done": {
+                    "type": "integer"
+                },
+                "checklist_total": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "completion_rule": {
+                    "description": "all, any или quorum",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "deadline": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "description": "Отдел, если задача поставлена руководителем отдела нескольким командам",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_hours": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LabelResponse"
+                    }
+                },
+                "parent_id": {
+                    "description": "Родительская задача, если это подзадача",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "low, medium, high или urgent",
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "Процент выполнения: для командной задачи — доля участников, выполнивших свою часть",
+                    "type": "integer"
+                },
+                "quorum_percent": {
+                    "type": "integer"
+                },
+                "series_id": {
+                    "description": "Серия повторяющихся задач, по которой создана задача",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "story_points": {
+                    "type": "integer"
+                },
+                "subtask_count": {
+                    "description": "Количество подзадач; прогресс задачи с подзадачами — средний прогресс подзадач",
+                    "type": "integer"
+                },
+                "subtasks_done": {
+                    "description": "Выполненные подзадачи",
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskReviewResponse": {
+            "type": "object",
+            "properties": {
+                "attachment": {
+                    "type": "string"
+                },
+                "attachments": {
+                    "description": "Файлы к отчёту исполнителя",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.AttachmentResponse"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "completion_text": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reviewer_telegram_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, approved или rejected",
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                },
+                "task_title": {
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "description": "Исполнитель",
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "assigned_to": {
+                    "type": "string"
+                },
+                "completion_rule": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_hours": {
+                    "type": "number"
+                },
+                "generated": {
+                    "description": "Сколько задач создано",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LabelResponse"
+                    }
+                },
+                "last_at": {
+                    "description": "Срок последней созданной задачи",
+                    "type": "string"
+                },
+                "last_task_id": {
+                    "description": "Последняя созданная задача серии",
+                    "type": "integer"
+                },
+                "next_at": {
+                    "description": "Срок следующей задачи; null, если серия закончилась или остановлена",
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "quorum_percent": {
+                    "type": "integer"
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE",
+                    "type": "string"
+                },
+                "start_at": {
+                    "description": "Срок первого повторения",
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "type": "string"
+                },
+                "story_points": {
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TaskTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "assignees": {
+                    "description": "Исполнители персональных задач по умолчанию",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TemplateAssigneeResponse"
+                    }
+                },
+                "checklist": {
+                    "description": "Пункты чек-листа по умолчанию",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "completion_rule": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "deadline": {
+                    "description": "Срок относительно создания задачи, например \"+3 days\"",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_hours": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.LabelResponse"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "quorum_percent": {
+                    "type": "integer"
+                },
+                "story_points": {
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variables": {
+                    "description": "Переменные {{name}}, которые нужно передать при создании задач",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.TaskUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TaskChangeResponse"
+                    }
+                },
+                "task": {
+                    "$ref": "#/definitions/response.TaskResponse"
+                }
+            }
+        },
+        "response.TeamPurgeResponse": {
+            "type": "object",
+            "properties": {
+                "invite_links": {
+                    "type": "integer"
+                },
+                "invite_uses": {
+                    "type": "integer"
+                },
+                "join_requests": {
+                    "type": "integer"
+                },
+                "meetings": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "integer"
+                },
+                "ownership_transfers": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "integer"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TeamResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "invitelink": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.TemplateAssigneeResponse": {
+            "type": "object",
+            "properties": {
+                "in_team": {
+                    "description": "false — пользователь вышел из команды, задачи ему по шаблону не создаются",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "telegram_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.UserInfoResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.WorkflowResponse": {
+            "type": "object",
+            "properties": {
+                "custom": {
+                    "description": "false — команда использует стандартный процесс",
+                    "type": "boolean"
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.WorkflowStatusResponse"
+                    }
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.WorkflowTransitionResponse"
+                    }
+                }
+            }
+        },
+        "response.WorkflowStatusResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "review": {
+                    "description": "Работа ждёт проверки автором задачи",
+                    "type": "boolean"
+                },
+                "terminal": {
+                    "description": "Работа по задаче завершена",
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.WorkflowTransitionResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Пусто — все, кто может работать с задачами",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasks.BulkTaskInput": {
+            "type": "object",
+            "required": [
+                "action",
+                "task_ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "status",
+                        "assign",
+                        "deadline",
+                        "delete"
+                    ]
+                },
+                "assigned_to": {
+                    "description": "Для assign: Telegram ID нового исполнителя",
+                    "type": "string"
+                },
+                "completion_text": {
+                    "description": "Для status: отчёт по выполнению (опционально)",
+                    "type": "string"
+                },
+                "deadline": {
+                    "description": "Для deadline: новый срок, RFC 3339",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Для status: статус из процесса команды",
+                    "type": "string"
+                },
+                "task_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "tasks.ChecklistItemInput": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "tasks.CommentInput": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "attachment_ids": {
+                    "description": "Файлы, загруженные к задаче через POST /tasks/{id}/attachments",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "mentions": {
+                    "description": "Telegram ID упомянутых участников, дополнительно к @упоминаниям в тексте",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "description": "Комментарий, на который это ответ",
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 4000
+                }
+            }
+        },
+        "tasks.DepartmentTaskInput": {
+            "type": "object",
+            "required": [
+                "deadline",
+                "department_id",
+                "description",
+                "title"
+            ],
+            "properties": {
+                "completion_rule": {
+                    "description": "Когда задача команды считается выполненной: all (по умолчанию), any или quorum",
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "any",
+                        "quorum"
+                    ]
+                },
+                "deadline": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_hours": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "priority": {
+                    "description": "Приоритет: low, medium (по умолчанию), high или urgent",
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "quorum_percent": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "story_points": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "team_ids": {
+                    "description": "Команды отдела; если не указаны — все активные команды отдела и вложенных отделов",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "tasks.DependencyInput": {
+            "type": "object",
+            "required": [
+                "blocker_id"
+            ],
+            "properties": {
+                "blocker_id": {
+                    "description": "Задача, которую нужно завершить раньше",
+                    "type": "integer"
+                }
+            }
+        },
+        "tasks.InstantiateTemplateInput": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/tasks.TemplateInstanceInput"
+                    }
+                }
+            }
+        },
+        "tasks.LabelInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "#RRGGBB, по умолчанию серый",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "tasks.ReviewDecisionInput": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Комментарий исполнителю, обязателен при отклонении",
+                    "type": "string"
+                }
+            }
+        },
+        "tasks.TaskInput": {
+            "type": "object",
+            "required": [
+                "deadline",
+                "description",
+                "title"
+            ],
+            "properties": {
+                "assigned_to": {
+                    "type": "string"
+                },
+                "completion_rule": {
+                    "description": "Когда командная задача считается выполненной: all (по умолчанию), any или quorum",
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "any",
+                        "quorum"
+                    ]
+                },
+                "deadline": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "estimate_hours": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "label_ids": {
+                    "description": "Метки команды",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "parent_id": {
+                    "description": "Родительская задача той же команды; срок подзадачи не может быть позже её срока",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Приоритет: low, medium (по умолчанию), high или urgent",
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "quorum_percent": {
+                    "description": "Для правила quorum, по умолчанию 50",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "recurrence": {
+                    "description": "Правило повторения в формате RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL.\nСрок задачи — первое повторение серии",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=FR"
+                },
+                "story_points": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "meetings.CreateMeetingInput": {
+        "tasks.TemplateInput": {
             "type": "object",
             "required": [
-                "date",
-                "end_time",
-                "meeting_type",
-                "start_time",
+                "checklist",
+                "deadline",
+                "name",
                 "title"
             ],
             "properties": {
-                "date": {
-                    "description": "Формат \"YYYY-MM-DD\"",
-                    "type": "string"
+                "assignees": {
+                    "description": "Telegram ID исполнителей персональных задач по умолчанию",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "end_time": {
-                    "description": "Формат \"HH:MM\"",
-                    "type": "string"
+                "checklist": {
+                    "description": "Пункты чек-листа по умолчанию",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "meeting_type": {
-                    "description": "\"online\" или \"offline\"",
-                    "type": "string"
+                "completion_rule": {
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "any",
+                        "quorum"
+                    ]
                 },
-                "room": {
-                    "description": "Обязательное для офлайн встреч",
-                    "type": "string"
+                "deadline": {
+                    "description": "Срок относительно создания задачи: \"+3 days\", \"+1 week 2 days\", \"+4h\"",
+                    "type": "string",
+                    "example": "+3 days"
                 },
-                "start_time": {
-                    "description": "Формат \"HH:MM\"",
+                "description": {
                     "type": "string"
                 },
+                "estimate_hours": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "label_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "quorum_percent": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "story_points": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "title": {
+                    "description": "Может содержать переменные {{name}}",
                     "type": "string"
                 }
             }
         },
-        "meetings.TimeSlot": {
+        "tasks.TemplateInstanceInput": {
             "type": "object",
             "properties": {
-                "end": {
-                    "description": "Например, \"13:20\"",
+                "assignees": {
+                    "description": "Исполнители персональных задач вместо указанных в шаблоне; каждому создаётся своя задача",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deadline": {
+                    "description": "Точный срок вместо рассчитанного по шаблону",
                     "type": "string"
                 },
-                "start": {
-                    "description": "Например, \"12:00\"",
+                "start_at": {
+                    "description": "От этого момента отсчитывается срок шаблона, по умолчанию — сейчас",
                     "type": "string"
+                },
+                "variables": {
+                    "description": "Значения переменных шаблона",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "response.ErrorCodeResponse": {
+        "tasks.UpdateChecklistItemInput": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "done": {
+                    "type": "boolean"
                 },
-                "error": {
-                    "type": "string"
+                "text": {
+                    "type": "string",
+                    "maxLength": 500
                 }
             }
         },
-        "response.ErrorResponse": {
+        "tasks.UpdateCommentInput": {
             "type": "object",
+            "required": [
+                "text"
+            ],
             "properties": {
-                "error": {
-                    "type": "string"
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 4000
                 }
             }
         },
-        "response.MeetingResponse": {
+        "tasks.UpdateLabelInput": {
             "type": "object",
             "properties": {
-                "conference_link": {
+                "color": {
+                    "description": "#RRGGBB",
                     "type": "string"
                 },
-                "created_at": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
+        "tasks.UpdateSeriesInput": {
+            "type": "object",
+            "properties": {
+                "assigned_to": {
+                    "description": "Telegram ID исполнителя персональной серии",
                     "type": "string"
                 },
-                "created_by": {
-                    "type": "integer"
-                },
-                "date": {
+                "description": {
                     "type": "string"
                 },
-                "end_time": {
-                    "type": "string"
+                "estimate_hours": {
+                    "description": "-1 — убрать оценку",
+                    "type": "number",
+                    "minimum": -1
                 },
-                "id": {
-                    "type": "integer"
+                "label_ids": {
+                    "description": "Новый набор меток; пустой список убирает все метки",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "meeting_type": {
+                "next_at": {
+                    "description": "Срок следующей задачи (RFC 3339); от него отсчитываются дальнейшие повторения",
                     "type": "string"
                 },
-                "room": {
-                    "type": "string"
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
                 },
-                "start_time": {
-                    "type": "string"
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=MONTHLY;BYMONTHDAY=-1"
                 },
-                "team_id": {
-                    "type": "integer"
+                "story_points": {
+                    "description": "-1 — убрать оценку",
+                    "type": "integer",
+                    "minimum": -1
                 },
                 "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "response.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 1
                 }
             }
         },
-        "response.TaskResponse": {
+        "tasks.UpdateTaskInput": {
             "type": "object",
             "properties": {
                 "assigned_to": {
+                    "description": "Telegram ID исполнителя персональной задачи",
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "integer"
+                "completion_rule": {
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "any",
+                        "quorum"
+                    ]
                 },
                 "deadline": {
+                    "description": "RFC 3339",
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "estimate_hours": {
+                    "description": "-1 — убрать оценку",
+                    "type": "number",
+                    "minimum": -1
                 },
                 "is_team": {
                     "type": "boolean"
                 },
-                "status": {
-                    "type": "string"
+                "label_ids": {
+                    "description": "Новый набор меток; пустой список убирает все метки",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "team_id": {
+                "parent_id": {
+                    "description": "Родительская задача; 0 — сделать задачу самостоятельной",
                     "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
                 },
-                "updated_at": {
+                "quorum_percent": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "story_points": {
+                    "description": "-1 — убрать оценку",
+                    "type": "integer",
+                    "minimum": -1
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "response.TeamResponse": {
+        "tasks.UpdateTaskStatusInput": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "description": {
+                "attachment": {
+                    "description": "Ссылка на файл или описание вложения (опционально)",
                     "type": "string"
                 },
-                "invitelink": {
+                "attachment_ids": {
+                    "description": "Файлы, загруженные к задаче через POST /tasks/{id}/attachments, — прикладываются к работе на проверке",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "completion_text": {
+                    "description": "Отчёт по выполнению (опционально)",
                     "type": "string"
                 },
-                "name": {
+                "status": {
+                    "description": "Статус из процесса команды, например \"in_progress\" или \"completed\" (для исполнителя — отправка на проверку)",
                     "type": "string"
                 }
             }
         },
-        "response.UserInfoResponse": {
+        "team.ChangeMemberRoleInput": {
             "type": "object",
+            "required": [
+                "role",
+                "telegram_id"
+            ],
             "properties": {
-                "name": {
-                    "type": "string"
-                },
                 "role": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "manager",
+                        "member",
+                        "observer"
+                    ]
                 },
-                "team_name": {
+                "telegram_id": {
                     "type": "string"
                 }
             }
         },
-        "response.UserResponse": {
+        "team.CreateInviteInput": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
+                "expires_in_hours": {
+                    "description": "По умолчанию 24",
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 0
                 },
-                "telegram_id": {
-                    "type": "string"
+                "max_uses": {
+                    "description": "0 — без ограничения, 1 — одноразовая ссылка",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "role": {
+                    "description": "По умолчанию member",
+                    "type": "string",
+                    "enum": [
+                        "manager",
+                        "member",
+                        "observer"
+                    ]
                 }
             }
         },
-        "tasks.TaskInput": {
+        "team.CreateTeamInput": {
             "type": "object",
             "required": [
-                "deadline",
-                "description",
-                "title"
+                "name"
             ],
             "properties": {
-                "assigned_to": {
-                    "type": "string"
-                },
-                "deadline": {
-                    "description": "RFC 3339",
+                "description": {
                     "type": "string"
                 },
-                "description": {
+                "name": {
                     "type": "string"
                 },
-                "is_team": {
+                "requires_approval": {
+                    "description": "Вступление по приглашению только после одобрения руководителем; если не передано при изменении — не меняется",
                     "type": "boolean"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "tasks.UpdateTaskStatusInput": {
+        "team.InviteJoinRequest": {
             "type": "object",
             "required": [
-                "status"
+                "invite_code"
             ],
             "properties": {
-                "attachment": {
-                    "description": "Ссылка на файл или описание вложения (опционально)",
+                "invite_code": {
                     "type": "string"
-                },
-                "completion_text": {
-                    "description": "Отчёт по выполнению (опционально)",
+                }
+            }
+        },
+        "team.SetActiveTeamInput": {
+            "type": "object",
+            "required": [
+                "team_id"
+            ],
+            "properties": {
+                "team_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "team.TransferOwnershipInput": {
+            "type": "object",
+            "required": [
+                "telegram_id"
+            ],
+            "properties": {
+                "telegram_id": {
+                    "description": "Участник команды, которому передаются права владельца",
                     "type": "string"
+                }
+            }
+        },
+        "workflow.WorkflowInput": {
+            "type": "object",
+            "required": [
+                "statuses",
+                "transitions"
+            ],
+            "properties": {
+                "statuses": {
+                    "description": "Первый статус назначается новым задачам",
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/workflow.WorkflowStatusInput"
+                    }
                 },
-                "status": {
-                    "description": "Ожидаемые значения: \"in_progress\" или \"completed\"",
-                    "type": "string"
+                "transitions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/workflow.WorkflowTransitionInput"
+                    }
                 }
             }
         },
-        "team.CreateTeamInput": {
+        "workflow.WorkflowStatusInput": {
             "type": "object",
             "required": [
-                "name"
+                "key",
+                "title"
             ],
             "properties": {
-                "description": {
+                "key": {
+                    "description": "Латинские буквы в нижнем регистре, цифры и _",
                     "type": "string"
                 },
-                "name": {
+                "review": {
+                    "description": "Работа ждёт проверки автором задачи",
+                    "type": "boolean"
+                },
+                "terminal": {
+                    "description": "Работа по задаче завершена",
+                    "type": "boolean"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "team.InviteJoinRequest": {
+        "workflow.WorkflowTransitionInput": {
             "type": "object",
             "required": [
-                "invite_code",
-                "telegram_id"
+                "from",
+                "to"
             ],
             "properties": {
-                "invite_code": {
+                "from": {
                     "type": "string"
                 },
-                "telegram_id": {
+                "roles": {
+                    "description": "owner, manager, member; пусто — все, кто может работать с задачами",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "Сервисный API-ключ. Ключ с правом действовать от имени пользователя принимает его telegram_id в заголовке X-On-Behalf-Of",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Access-токен в формате \"Bearer \u003ctoken\u003e\", выданный /auth",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "TelegramInitData": {
+            "description": "initData из Telegram WebApp",
+            "type": "apiKey",
+            "name": "X-Telegram-Init-Data",
+            "in": "header"
+        },
+        "TelegramLogin": {
+            "description": "Данные Telegram Login Widget в виде query-строки (id, auth_date, hash, ...)",
+            "type": "apiKey",
+            "name": "X-Telegram-Login",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,293 @@
+package access
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Роли участника внутри команды.
+const (
+	RoleOwner    = "owner"    // Создатель команды, может всё, включая удаление
+	RoleManager  = "manager"  // Управляет командой, задачами и встречами
+	RoleMember   = "member"   // Выполняет задачи команды
+	RoleObserver = "observer" // Только просмотр
+)
+
+// Action — действие в команде, на которое проверяются права.
+type Action string
+
+const (
+	ViewTeam       Action = "view_team"
+	ManageTeam     Action = "manage_team"
+	DeleteTeam     Action = "delete_team"
+	TransferTeam   Action = "transfer_team"
+	ManageTasks    Action = "manage_tasks"
+	WorkOnTasks    Action = "work_on_tasks"
+	ManageMeetings Action = "manage_meetings"
+)
+
+var permissions = map[string][]Action{
+	RoleOwner:    {ViewTeam, ManageTeam, DeleteTeam, TransferTeam, ManageTasks, WorkOnTasks, ManageMeetings},
+	RoleManager:  {ViewTeam, ManageTeam, ManageTasks, WorkOnTasks, ManageMeetings},
+	RoleMember:   {ViewTeam, WorkOnTasks},
+	RoleObserver: {ViewTeam},
+}
+
+// archivedActions — действия, доступные в команде, которая находится в архиве.
+// Восстановление и окончательное удаление проверяются правом DeleteTeam.
+var archivedActions = map[Action]bool{
+	ViewTeam:   true,
+	DeleteTeam: true,
+}
+
+var forbiddenMessages = map[Action]string{
+	ViewTeam:       "Нет доступа к команде",
+	ManageTeam:     "Только руководитель может управлять командой",
+	DeleteTeam:     "Только владелец может удалить команду",
+	TransferTeam:   "Только владелец может передать команду",
+	ManageTasks:    "Только руководитель может управлять задачами",
+	WorkOnTasks:    "Наблюдатель не может работать с задачами",
+	ManageMeetings: "Только руководитель может управлять встречами",
+}
+
+// Denial — отказ в действии: HTTP-статус, сообщение и код ошибки для ответа API.
+type Denial struct {
+	Status  int
+	Message string
+	Code    string
+}
+
+func (d *Denial) Error() string {
+	return d.Message
+}
+
+// Write пишет отказ в ответ. Код ошибки добавляется, только если он задан.
+func (d *Denial) Write(c *gin.Context) {
+	body := gin.H{"error": d.Message}
+	if d.Code != "" {
+		body["code"] = d.Code
+	}
+	c.JSON(d.Status, body)
+}
+
+// ValidRole проверяет, что роль входит в список известных.
+func ValidRole(role string) bool {
+	_, ok := permissions[role]
+	return ok
+}
+
+// Can проверяет, разрешено ли роли действие.
+func Can(role string, action Action) bool {
+	for _, a := range permissions[role] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMembership возвращает участие пользователя в команде.
+func GetMembership(userID, teamID uint) (*models.TeamMembership, error) {
+	var membership models.TeamMembership
+	if err := storage.DB.Where("user_id = ? AND team_id = ?", userID, teamID).First(&membership).Error; err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// RoleIn возвращает роль пользователя в команде или пустую строку, если он в ней не состоит.
+func RoleIn(userID uint, teamID *uint) string {
+	if teamID == nil {
+		return ""
+	}
+	membership, err := GetMembership(userID, *teamID)
+	if err != nil {
+		return ""
+	}
+	return membership.Role
+}
+
+// Require проверяет, что пользователь состоит в команде и его роль позволяет действие.
+// В архивной команде разрешены только просмотр и действия владельца над архивом.
+// При отказе пишет ответ и возвращает false.
+func Require(c *gin.Context, user *models.User, teamID uint, action Action) (*models.TeamMembership, bool) {
+	return require(c, user, teamID, action, !archivedActions[action])
+}
+
+// RequireRead выполняет Require для эндпоинтов, которые только читают данные команды,
+// поэтому работают и в архивной команде.
+func RequireRead(c *gin.Context, user *models.User, teamID uint, action Action) (*models.TeamMembership, bool) {
+	return require(c, user, teamID, action, false)
+}
+
+func require(c *gin.Context, user *models.User, teamID uint, action Action, checkArchive bool) (*models.TeamMembership, bool) {
+	membership, denial := check(user, teamID, action, checkArchive)
+	if denial != nil {
+		denial.Write(c)
+		return nil, false
+	}
+	return membership, true
+}
+
+// Check выполняет проверку Require, не записывая ответ: при отказе возвращает его причину.
+// Нужен там, где отказы собираются по нескольким объектам сразу.
+func Check(user *models.User, teamID uint, action Action) (*models.TeamMembership, *Denial) {
+	return check(user, teamID, action, !archivedActions[action])
+}
+
+func check(user *models.User, teamID uint, action Action, checkArchive bool) (*models.TeamMembership, *Denial) {
+	membership, err := GetMembership(user.ID, teamID)
+	if err != nil {
+		return nil, &Denial{Status: http.StatusForbidden, Message: "Вы не состоите в этой команде", Code: "NOT_IN_TEAM"}
+	}
+
+	if !Can(membership.Role, action) {
+		return nil, &Denial{Status: http.StatusForbidden, Message: forbiddenMessages[action], Code: "FORBIDDEN"}
+	}
+
+	if checkArchive {
+		if denial := CheckActive(teamID); denial != nil {
+			return nil, denial
+		}
+	}
+
+	return membership, nil
+}
+
+// RequireActive проверяет, что команда не находится в архиве. При отказе пишет ответ и возвращает false.
+func RequireActive(c *gin.Context, teamID uint) bool {
+	if denial := CheckActive(teamID); denial != nil {
+		denial.Write(c)
+		return false
+	}
+	return true
+}
+
+// CheckActive выполняет проверку RequireActive, не записывая ответ.
+func CheckActive(teamID uint) *Denial {
+	var team models.Team
+	if err := storage.DB.Select("id", "archived_at").First(&team, teamID).Error; err != nil {
+		return &Denial{Status: http.StatusNotFound, Message: "Команда не найдена", Code: "TEAM_NOT_FOUND"}
+	}
+	if team.ArchivedAt != nil {
+		return &Denial{Status: http.StatusConflict, Message: "Команда в архиве, доступен только просмотр", Code: "TEAM_ARCHIVED"}
+	}
+	return nil
+}
+
+// TeamID возвращает команду, к которой относится запрос: параметр team_id или активную команду пользователя.
+// При ошибке пишет ответ и возвращает false.
+func TeamID(c *gin.Context, user *models.User) (uint, bool) {
+	if raw := c.Query("team_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный team_id", "code": "INVALID_TEAM_ID"})
+			return 0, false
+		}
+		return uint(id), true
+	}
+
+	if user.TeamID == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Отсутствует команда у пользователя",
+			"code":  "USER_HAS_NO_TEAM",
+		})
+		return 0, false
+	}
+	return *user.TeamID, true
+}
+
+// RequireTeam выполняет Require для команды из параметра team_id или для активной команды пользователя.
+func RequireTeam(c *gin.Context, user *models.User, action Action) (*models.TeamMembership, bool) {
+	teamID, ok := TeamID(c, user)
+	if !ok {
+		return nil, false
+	}
+	return Require(c, user, teamID, action)
+}
+
+// RequireTeamRead выполняет RequireRead для команды из параметра team_id или для активной команды пользователя.
+func RequireTeamRead(c *gin.Context, user *models.User, action Action) (*models.TeamMembership, bool) {
+	teamID, ok := TeamID(c, user)
+	if !ok {
+		return nil, false
+	}
+	return RequireRead(c, user, teamID, action)
+}
+
+// TeamUsers возвращает всех участников команды.
+func TeamUsers(teamID uint) ([]models.User, error) {
+	var users []models.User
+	err := storage.DB.
+		Joins("JOIN team_memberships ON team_memberships.user_id = users.id").
+		Where("team_memberships.team_id = ?", teamID).
+		Find(&users).Error
+	return users, err
+}
+
+// TeamManagers возвращает владельца и руководителей команды.
+func TeamManagers(teamID uint) ([]models.User, error) {
+	var users []models.User
+	err := storage.DB.
+		Joins("JOIN team_memberships ON team_memberships.user_id = users.id").
+		Where("team_memberships.team_id = ? AND team_memberships.role IN ?", teamID, []string{RoleOwner, RoleManager}).
+		Find(&users).Error
+	return users, err
+}
+
+// ResetActiveTeam вызывается после выхода пользователя из команды: если она была активной,
+// активной становится другая команда пользователя, а если других нет — активная команда сбрасывается.
+func ResetActiveTeam(tx *gorm.DB, userID, teamID uint) error {
+	var next models.TeamMembership
+	err := tx.Where("user_id = ? AND team_id != ?", userID, teamID).Order("created_at").First(&next).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var nextTeamID *uint
+	if err == nil {
+		nextTeamID = &next.TeamID
+	}
+	return tx.Model(&models.User{}).
+		Where("id = ? AND team_id = ?", userID, teamID).
+		Update("team_id", nextTeamID).Error
+}
+
+// MigrateLegacyRoles переносит глобальную роль users.role в участия в командах и удаляет колонку.
+// Руководитель, указанный в teams.manager_id, становится владельцем своей команды.
+func MigrateLegacyRoles(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.User{}, "role") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO team_memberships (user_id, team_id, role, created_at, updated_at)
+			SELECT u.id, u.team_id,
+				CASE WHEN t.manager_id = u.id THEN 'owner' ELSE 'member' END,
+				NOW(), NOW()
+			FROM users u
+			JOIN teams t ON t.id = u.team_id AND t.deleted_at IS NULL
+			WHERE u.team_id IS NOT NULL AND u.deleted_at IS NULL
+			ON CONFLICT (user_id, team_id) DO NOTHING`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO team_memberships (user_id, team_id, role, created_at, updated_at)
+			SELECT t.manager_id, t.id, 'owner', NOW(), NOW()
+			FROM teams t
+			WHERE t.deleted_at IS NULL
+			ON CONFLICT (user_id, team_id) DO UPDATE SET role = 'owner'`).Error; err != nil {
+			return err
+		}
+
+		return tx.Migrator().DropColumn(&models.User{}, "role")
+	})
+}
@@ -0,0 +1,157 @@
+package access
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// orgDepartments возвращает все отделы организации по ID.
+func orgDepartments(orgID uint) (map[uint]models.Department, error) {
+	var departments []models.Department
+	if err := storage.DB.Where("organization_id = ?", orgID).Find(&departments).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Department, len(departments))
+	for _, d := range departments {
+		byID[d.ID] = d
+	}
+	return byID, nil
+}
+
+// OverseesDepartment проверяет, что пользователь владеет организацией отдела
+// или руководит этим отделом либо одним из родительских.
+func OverseesDepartment(userID uint, department *models.Department) (bool, error) {
+	var org models.Organization
+	if err := storage.DB.First(&org, department.OrganizationID).Error; err != nil {
+		return false, err
+	}
+	if org.OwnerID == userID {
+		return true, nil
+	}
+
+	departments, err := orgDepartments(department.OrganizationID)
+	if err != nil {
+		return false, err
+	}
+
+	// Поднимаемся к корню; visited защищает от цикла в данных
+	visited := make(map[uint]bool)
+	for id := &department.ID; id != nil && !visited[*id]; {
+		visited[*id] = true
+		current, ok := departments[*id]
+		if !ok {
+			break
+		}
+		if current.HeadID != nil && *current.HeadID == userID {
+			return true, nil
+		}
+		id = current.ParentID
+	}
+	return false, nil
+}
+
+// DepartmentSubtree возвращает ID отдела и всех вложенных в него отделов.
+func DepartmentSubtree(department *models.Department) ([]uint, error) {
+	departments, err := orgDepartments(department.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	for _, d := range departments {
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+	}
+
+	ids := []uint{department.ID}
+	visited := map[uint]bool{department.ID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// DepartmentTeamIDs возвращает команды отдела и всех вложенных в него отделов.
+func DepartmentTeamIDs(department *models.Department) ([]uint, error) {
+	subtree, err := DepartmentSubtree(department)
+	if err != nil {
+		return nil, err
+	}
+
+	var teamIDs []uint
+	err = storage.DB.Model(&models.Team{}).Where("department_id IN ?", subtree).Pluck("id", &teamIDs).Error
+	return teamIDs, err
+}
+
+// RequireDepartment проверяет, что отдел существует и пользователь руководит им, одним из родительских
+// отделов или владеет организацией. При отказе пишет ответ и возвращает false.
+func RequireDepartment(c *gin.Context, user *models.User, departmentID uint) (*models.Department, bool) {
+	var department models.Department
+	if err := storage.DB.First(&department, departmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отдел не найден", "code": "DEPARTMENT_NOT_FOUND"})
+		return nil, false
+	}
+
+	oversees, err := OverseesDepartment(user.ID, &department)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке прав на отдел"})
+		return nil, false
+	}
+	if !oversees {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Только руководитель отдела или владелец организации может выполнить это действие",
+			"code":  "NOT_DEPARTMENT_HEAD",
+		})
+		return nil, false
+	}
+	return &department, true
+}
+
+// RequireDepartmentTeams возвращает команды отдела из параметра department_id с учётом вложенных отделов.
+// При ошибке пишет ответ и возвращает false.
+func RequireDepartmentTeams(c *gin.Context, user *models.User) ([]uint, bool) {
+	id, err := strconv.ParseUint(c.Query("department_id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный department_id", "code": "INVALID_DEPARTMENT_ID"})
+		return nil, false
+	}
+
+	department, ok := RequireDepartment(c, user, uint(id))
+	if !ok {
+		return nil, false
+	}
+
+	teamIDs, err := DepartmentTeamIDs(department)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении команд отдела"})
+		return nil, false
+	}
+	return teamIDs, true
+}
+
+// OverseesTeam проверяет, что команда входит в отдел, за которым пользователь наблюдает как руководитель.
+func OverseesTeam(userID, teamID uint) bool {
+	var team models.Team
+	if err := storage.DB.Select("id", "department_id").First(&team, teamID).Error; err != nil || team.DepartmentID == nil {
+		return false
+	}
+
+	var department models.Department
+	if err := storage.DB.First(&department, *team.DepartmentID).Error; err != nil {
+		return false
+	}
+
+	oversees, err := OverseesDepartment(userID, &department)
+	return err == nil && oversees
+}
@@ -0,0 +1,250 @@
+package apikeys
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateAPIKeyInput struct {
+	Name           string   `json:"name" binding:"required"`
+	Scopes         []string `json:"scopes" binding:"required,min=1"` // Например: ["tasks:read", "tasks:write"]
+	CanActOnBehalf bool     `json:"can_act_on_behalf"`               // Разрешить заголовок X-On-Behalf-Of
+	ExpiresInDays  int      `json:"expires_in_days"`                 // 0 — бессрочный ключ
+}
+
+// canManage проверяет, может ли запрос ротировать и отзывать ключ: администратор — любой,
+// API-ключ — только созданные им ключи.
+func canManage(c *gin.Context, key models.APIKey) bool {
+	if auth.IsAdmin(c) {
+		return true
+	}
+	current := auth.CurrentAPIKey(c)
+	return current != nil && key.CreatedByKey != nil && *key.CreatedByKey == current.ID
+}
+
+func toResponse(key models.APIKey) response.APIKeyResponse {
+	return response.APIKeyResponse{
+		ID:             key.ID,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		Scopes:         strings.Fields(key.Scopes),
+		CanActOnBehalf: key.CanActOnBehalf,
+		CreatedBy:      key.CreatedBy,
+		CreatedByKey:   key.CreatedByKey,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		RevokedAt:      key.RevokedAt,
+		CreatedAt:      key.CreatedAt,
+	}
+}
+
+// CreateAPIKeyHandler создаёт сервисный API-ключ
+// @Summary Создание API-ключа
+// @Description Создаёт API-ключ с указанными правами. Ключ возвращается целиком только в этом ответе. Доступно администраторам и API-ключам с правом apikeys:write: ключ выдаёт только свои права, а can_act_on_behalf — только если он есть у него самого.
+// @Tags apikeys
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body CreateAPIKeyInput true "Параметры ключа"
+// @Success 200 {object} response.APIKeySecretResponse "Созданный ключ"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации или неизвестное право"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Доступно только администратору, Error: У API-ключа нет права tasks:write Code: SCOPE_FORBIDDEN, Error: Разрешить действовать от имени пользователя может только администратор Code: ON_BEHALF_FORBIDDEN"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании ключа"
+// @Router /apikeys [post]
+func CreateAPIKeyHandler(c *gin.Context) {
+	var input CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := auth.CurrentAPIKey(c)
+	for _, scope := range input.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неизвестное право: " + scope})
+			return
+		}
+		// Ключ не может выдать больше прав, чем есть у него самого
+		if current != nil && !auth.HasScope(current, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "У API-ключа нет права " + scope, "code": "SCOPE_FORBIDDEN"})
+			return
+		}
+	}
+
+	if input.CanActOnBehalf && !auth.IsAdmin(c) && (current == nil || !current.CanActOnBehalf) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Разрешить действовать от имени пользователя может только администратор", "code": "ON_BEHALF_FORBIDDEN"})
+		return
+	}
+
+	if input.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_days не может быть отрицательным"})
+		return
+	}
+
+	raw, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации ключа"})
+		return
+	}
+
+	key := models.APIKey{
+		Name:           input.Name,
+		Prefix:         auth.APIKeyDisplayPrefix(raw),
+		KeyHash:        hash,
+		Scopes:         strings.Join(input.Scopes, " "),
+		CanActOnBehalf: input.CanActOnBehalf,
+	}
+	if user := auth.CurrentUser(c); user != nil {
+		key.CreatedBy = user.ID
+	}
+	if current != nil {
+		key.CreatedByKey = &current.ID
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := storage.DB.Create(&key).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании ключа"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIKeySecretResponse{APIKeyResponse: toResponse(key), Key: raw})
+}
+
+// ListAPIKeysHandler возвращает список API-ключей
+// @Summary Список API-ключей
+// @Description Возвращает все API-ключи без секретной части. Доступно администраторам.
+// @Tags apikeys
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} response.APIKeyResponse "Список ключей"
+// @Failure 403 {object} response.ErrorResponse "Доступно только администратору"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении ключей"
+// @Router /apikeys [get]
+func ListAPIKeysHandler(c *gin.Context) {
+	var keys []models.APIKey
+	if err := storage.DB.Order("created_at DESC").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении ключей"})
+		return
+	}
+
+	resp := make([]response.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, toResponse(key))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RotateAPIKeyHandler выпускает новое значение ключа
+// @Summary Ротация API-ключа
+// @Description Заменяет значение ключа, сохраняя его права. Старое значение перестаёт действовать сразу. Администратор может ротировать любой ключ, API-ключ — только созданные им.
+// @Tags apikeys
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID ключа"
+// @Success 200 {object} response.APIKeySecretResponse "Новое значение ключа"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Доступно только администратору, Error: API-ключ может ротировать только созданные им ключи Code: API_KEY_FORBIDDEN"
+// @Failure 404 {object} response.ErrorResponse "Ключ не найден"
+// @Failure 409 {object} response.ErrorResponse "Ключ отозван"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при ротации ключа"
+// @Router /apikeys/{id}/rotate [post]
+func RotateAPIKeyHandler(c *gin.Context) {
+	var key models.APIKey
+	if err := storage.DB.First(&key, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ключ не найден"})
+		return
+	}
+
+	if !canManage(c, key) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API-ключ может ротировать только созданные им ключи", "code": "API_KEY_FORBIDDEN"})
+		return
+	}
+
+	if key.RevokedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ключ отозван"})
+		return
+	}
+
+	raw, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации ключа"})
+		return
+	}
+
+	key.KeyHash = hash
+	key.Prefix = auth.APIKeyDisplayPrefix(raw)
+	if err := storage.DB.Save(&key).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при ротации ключа"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIKeySecretResponse{APIKeyResponse: toResponse(key), Key: raw})
+}
+
+// RevokeAPIKeyHandler отзывает API-ключ
+// @Summary Отзыв API-ключа
+// @Description Отзывает ключ. Запись остаётся в списке для истории. Администратор может отозвать любой ключ, API-ключ — только созданные им.
+// @Tags apikeys
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID ключа"
+// @Success 200 {object} response.SuccessResponse "Ключ отозван"
+// @Failure 400 {object} response.ErrorResponse "Нельзя отозвать ключ, которым выполнен запрос"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Доступно только администратору, Error: API-ключ может отзывать только созданные им ключи Code: API_KEY_FORBIDDEN"
+// @Failure 404 {object} response.ErrorResponse "Ключ не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при отзыве ключа"
+// @Router /apikeys/{id} [delete]
+func RevokeAPIKeyHandler(c *gin.Context) {
+	var key models.APIKey
+	if err := storage.DB.First(&key, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ключ не найден"})
+		return
+	}
+
+	if current := auth.CurrentAPIKey(c); current != nil && current.ID == key.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Нельзя отозвать ключ, которым выполнен запрос"})
+		return
+	}
+
+	if !canManage(c, key) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API-ключ может отзывать только созданные им ключи", "code": "API_KEY_FORBIDDEN"})
+		return
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := storage.DB.Save(&key).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отзыве ключа"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ключ отозван"})
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderAPIKey — заголовок с сервисным API-ключом.
+	HeaderAPIKey = "X-API-Key"
+	// HeaderOnBehalfOf — telegram_id пользователя, от имени которого действует ключ.
+	HeaderOnBehalfOf = "X-On-Behalf-Of"
+
+	apiKeyKey = "auth_api_key"
+
+	// apiKeyPrefix отличает ключи сервиса от прочих секретов в логах и конфигах.
+	apiKeyPrefix = "ltb_"
+)
+
+// Scopes — права, которые можно выдать API-ключу.
+var Scopes = []string{
+	"users:read", "users:write",
+	"team:read", "team:write",
+	"tasks:read", "tasks:write",
+	"meetings:read", "meetings:write",
+	"org:read", "org:write",
+	"search:read",
+	"apikeys:read", "apikeys:write",
+}
+
+var (
+	ErrAPIKeyInvalid = errors.New("недействительный API-ключ")
+	ErrAPIKeyRevoked = errors.New("API-ключ отозван")
+	ErrAPIKeyExpired = errors.New("срок действия API-ключа истёк")
+)
+
+// GenerateAPIKey возвращает новый ключ и его хеш для хранения.
+func GenerateAPIKey() (key, hash string, err error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + secret
+	return key, hashToken(key), nil
+}
+
+// APIKeyDisplayPrefix возвращает начало ключа, по которому его можно узнать в списке.
+func APIKeyDisplayPrefix(key string) string {
+	return key[:len(apiKeyPrefix)+6]
+}
+
+// FindAPIKey ищет действующий ключ по его значению.
+func FindAPIKey(raw string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := storage.DB.Where("key_hash = ?", hashToken(raw)).First(&key).Error; err != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return nil, ErrAPIKeyExpired
+	}
+	return &key, nil
+}
+
+// HasScope проверяет, выдано ли ключу указанное право.
+func HasScope(key *models.APIKey, scope string) bool {
+	return slices.Contains(strings.Fields(key.Scopes), scope)
+}
+
+// apiKeyAuth аутентифицирует запрос по API-ключу. Если передан X-On-Behalf-Of и ключу это разрешено,
+// запрос выполняется от имени указанного пользователя Telegram.
+func apiKeyAuth(c *gin.Context, raw string) {
+	key, err := FindAPIKey(raw)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "API_KEY_INVALID"})
+		return
+	}
+
+	// Не пишем в БД на каждый запрос — достаточно точности до минуты
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > time.Minute {
+		storage.DB.Model(key).UpdateColumn("last_used_at", time.Now())
+	}
+	c.Set(apiKeyKey, key)
+
+	telegramID := c.GetHeader(HeaderOnBehalfOf)
+	if telegramID != "" {
+		if !key.CanActOnBehalf {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API-ключ не может действовать от имени пользователя", "code": "ON_BEHALF_FORBIDDEN"})
+			return
+		}
+
+		c.Set(telegramIDKey, telegramID)
+
+		var user models.User
+		if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err == nil {
+			c.Set(userKey, &user)
+		}
+	}
+
+	c.Next()
+}
+
+// RequireScope проверяет право API-ключа на ресурс: для GET нужен "<resource>:read", для остальных методов — "<resource>:write".
+// Запросы пользователей (Telegram, Bearer) пропускаются без проверки.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := CurrentAPIKey(c)
+		if key == nil {
+			c.Next()
+			return
+		}
+
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		if !HasScope(key, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "У API-ключа нет права " + scope, "code": "SCOPE_FORBIDDEN"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdmin пропускает только администраторов из ADMIN_TELEGRAM_IDS.
+func RequireAdmin() gin.HandlerFunc {
+	return requireAdmin(false)
+}
+
+// RequireAdminOrServiceKey пропускает администраторов из ADMIN_TELEGRAM_IDS и запросы по API-ключу без X-On-Behalf-Of
+// (права такого ключа уже проверены RequireScope). Обработчик сам ограничивает, что может сделать ключ.
+func RequireAdminOrServiceKey() gin.HandlerFunc {
+	return requireAdmin(true)
+}
+
+func requireAdmin(allowServiceKey bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allowServiceKey && CurrentAPIKey(c) != nil && TelegramID(c) == "" {
+			c.Next()
+			return
+		}
+
+		user := CurrentUser(c)
+		if user == nil || !isAdmin(user.TelegramID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Доступно только администратору"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAdmin сообщает, что запрос выполнил сам администратор из ADMIN_TELEGRAM_IDS, а не API-ключ.
+func IsAdmin(c *gin.Context) bool {
+	user := CurrentUser(c)
+	return CurrentAPIKey(c) == nil && user != nil && isAdmin(user.TelegramID)
+}
+
+func isAdmin(telegramID string) bool {
+	if telegramID == "" {
+		return false
+	}
+	for _, id := range strings.Split(os.Getenv("ADMIN_TELEGRAM_IDS"), ",") {
+		if strings.TrimSpace(id) == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentAPIKey возвращает API-ключ, которым аутентифицирован запрос, или nil.
+func CurrentAPIKey(c *gin.Context) *models.APIKey {
+	if v, ok := c.Get(apiKeyKey); ok {
+		if key, ok := v.(*models.APIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
@@ -3,27 +3,53 @@ package auth
 import (
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
 	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
 	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
 	"github.com/gin-gonic/gin"
 )
 
 type RegisterInput struct {
-	TelegramID string `json:"telegram_id" binding:"required"`
-	Name       string `json:"name" binding:"required"`
-	Role       string `json:"role" binding:"required,oneof=manager member"` // "manager" или "member"
+	Name string `json:"name" binding:"required"`
+}
+
+// RegisterResponse — ответ на успешную регистрацию вместе с первой парой токенов.
+type RegisterResponse struct {
+	Message string `json:"message"`
+	TokenPair
+}
+
+// AuthResponse — данные пользователя и, при входе через Telegram, новая пара токенов.
+type AuthResponse struct {
+	models.User
+	Role string // Роль в текущей команде: owner, manager, member, observer или пустая строка
+	*TokenPair
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"` // Refresh-токен текущей сессии (опционально)
+	All          bool   `json:"all"`           // Завершить все сессии пользователя
 }
 
 // AuthHandler godoc
 // @Summary Регистрация пользователя
-// @Description Регистрация пользователя с помощью уникального telegram_id
+// @Description Регистрация пользователя. telegram_id берётся из подписанных данных Telegram. Роль пользователь получает при создании команды или вступлении в неё.
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security APIKeyAuth
 // @Param input body RegisterInput true "Данные пользователя для регистрации"
-// @Success 200 {object} response.SuccessResponse "Успешная регистрация"
+// @Success 200 {object} RegisterResponse "Успешная регистрация и токены"
 // @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorCodeResponse "Неверные или устаревшие данные Telegram"
 // @Failure 409 {object} response.ErrorResponse "Пользователь уже зарегистрирован"
 // @Failure 507 {object} response.ErrorResponse "Не удалось создать пользователя"
 // @Router /auth [post]
@@ -34,16 +60,14 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	var existing models.User
-	if err := storage.DB.Where("telegram_id = ?", input.TelegramID).First(&existing).Error; err == nil {
+	if CurrentUser(c) != nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Пользователь уже зарегистрирован"})
 		return
 	}
 
 	user := models.User{
-		TelegramID: input.TelegramID,
+		TelegramID: TelegramID(c),
 		Name:       input.Name,
-		Role:       input.Role,
 	}
 
 	if err := storage.DB.Create(&user).Error; err != nil {
@@ -52,32 +76,159 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Успешная регистрация"})
+	tokens, err := IssueTokens(&user)
+	if err != nil {
+		log.Println("Не удалось выпустить токены", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выпустить токены"})
+		return
+	}
+
+	c.JSON(http.StatusOK, RegisterResponse{Message: "Успешная регистрация", TokenPair: *tokens})
 }
 
 // CheckAuthHandler godoc
 // @Summary Проверка авторизации пользователя
-// @Description Проверяет, зарегистрирован ли пользователь по telegram_id. Если пользователь найден, возвращает его данные, иначе – сообщение об ошибке.
+// @Description Проверяет подписанные данные Telegram и то, зарегистрирован ли пользователь. Если пользователь найден, возвращает его данные и новую пару токенов, иначе – сообщение об ошибке. При запросе с Bearer-токеном или API-ключом новые токены не выпускаются.
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {object} RegisterInput "Данные пользователя"
-// @Failure 400 {object} response.ErrorResponse "Ошибка telegram_id is required"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {object} AuthResponse "Данные пользователя и токены"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден или неверные данные Telegram"
+// @Failure 500 {object} response.ErrorResponse "Не удалось выпустить токены"
 // @Router /auth [get]
 func CheckAuthHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
+	user := CurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	resp := AuthResponse{User: *user, Role: access.RoleIn(user.ID, user.TeamID)}
+	if currentClaims(c) == nil && CurrentAPIKey(c) == nil {
+		tokens, err := IssueTokens(user)
+		if err != nil {
+			log.Println("Не удалось выпустить токены", err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выпустить токены"})
+			return
+		}
+		resp.TokenPair = tokens
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshHandler godoc
+// @Summary Обновление токенов
+// @Description Обменивает действующий refresh-токен на новую пару токенов. Старый refresh-токен отзывается. Повторное использование отозванного токена завершает все сессии пользователя.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body RefreshInput true "Refresh-токен"
+// @Success 200 {object} TokenPair "Новая пара токенов"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorCodeResponse "Error: Недействительный refresh-токен Code: REFRESH_INVALID, Error: Refresh-токен отозван Code: REFRESH_REVOKED, Error: Срок действия refresh-токена истёк Code: REFRESH_EXPIRED"
+// @Failure 500 {object} response.ErrorResponse "Не удалось выпустить токены"
+// @Router /auth/refresh [post]
+func RefreshHandler(c *gin.Context) {
+	var input RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var stored models.RefreshToken
+	if err := storage.DB.Where("token_hash = ?", hashToken(input.RefreshToken)).First(&stored).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный refresh-токен", "code": "REFRESH_INVALID"})
+		return
+	}
+
+	now := time.Now()
+	if stored.ExpiresAt.Before(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Срок действия refresh-токена истёк", "code": "REFRESH_EXPIRED"})
+		return
+	}
+
+	// Отзываем токен условным UPDATE, чтобы два параллельных запроса не обменяли его дважды
+	result := storage.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", stored.ID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить токены"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		// Токен уже был использован — возможна утечка, завершаем все сессии пользователя
+		storage.DB.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", stored.UserID).
+			Update("revoked_at", now)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh-токен отозван", "code": "REFRESH_REVOKED"})
 		return
 	}
 
 	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
+	if err := storage.DB.First(&user, stored.UserID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	tokens, err := IssueTokens(&user)
+	if err != nil {
+		log.Println("Не удалось выпустить токены", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выпустить токены"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LogoutHandler godoc
+// @Summary Выход
+// @Description Отзывает текущий access-токен и переданный refresh-токен. С all=true отзываются все refresh-токены пользователя.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body LogoutInput false "Параметры выхода"
+// @Success 200 {object} response.SuccessResponse "Сессия завершена"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при завершении сессии"
+// @Router /auth/logout [post]
+func LogoutHandler(c *gin.Context) {
+	user := CurrentUser(c)
+
+	var input LogoutInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if claims := currentClaims(c); claims != nil {
+		if err := RevokeAccessToken(claims); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при завершении сессии"})
+			return
+		}
+	}
+
+	query := storage.DB.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", user.ID)
+	switch {
+	case input.All:
+	case input.RefreshToken != "":
+		query = query.Where("token_hash = ?", hashToken(input.RefreshToken))
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Сессия завершена"})
+		return
+	}
+	if err := query.Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при завершении сессии"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Сессия завершена"})
 }
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrJWTSecretMissing = errors.New("JWT_SECRET не задан")
+	ErrTokenRevoked     = errors.New("токен отозван")
+)
+
+// AccessClaims — содержимое access-токена.
+type AccessClaims struct {
+	TelegramID string `json:"tg"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair — пара токенов, выдаваемая при входе и обновлении сессии.
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// IssueTokens выпускает access-токен и сохраняет хеш нового refresh-токена.
+func IssueTokens(user *models.User) (*TokenPair, error) {
+	secret, err := jwtSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(durationFromEnv("JWT_ACCESS_TTL", defaultAccessTTL))
+	claims := AccessClaims{
+		TelegramID: user.TelegramID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	refresh := models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(durationFromEnv("JWT_REFRESH_TTL", defaultRefreshTTL)),
+	}
+	if err := storage.DB.Create(&refresh).Error; err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// ParseAccessToken проверяет подпись и срок действия access-токена, а также его отсутствие в списке отзыва.
+func ParseAccessToken(raw string) (*AccessClaims, error) {
+	secret, err := jwtSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	var claims AccessClaims
+	_, err = jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := storage.DB.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrTokenRevoked
+	}
+
+	return &claims, nil
+}
+
+// RevokeAccessToken добавляет access-токен в список отзыва и чистит записи об истёкших токенах.
+func RevokeAccessToken(claims *AccessClaims) error {
+	storage.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+
+	return storage.DB.Create(&models.RevokedToken{
+		JTI:       claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}).Error
+}
+
+func jwtSecret() ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, ErrJWTSecretMissing
+	}
+	return []byte(secret), nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	if raw := os.Getenv(key); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// HeaderInitData — заголовок с initData из Telegram WebApp.
+	HeaderInitData = "X-Telegram-Init-Data"
+	// HeaderLoginData — заголовок с данными Telegram Login Widget в виде query-строки.
+	HeaderLoginData = "X-Telegram-Login"
+
+	telegramIDKey = "auth_telegram_id"
+	userKey       = "auth_user"
+	claimsKey     = "auth_claims"
+)
+
+// Middleware проверяет API-ключ, Bearer access-токен или подпись данных Telegram и кладёт в контекст
+// подтверждённый telegram_id, а также пользователя, если он уже зарегистрирован.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(HeaderAPIKey); key != "" {
+			apiKeyAuth(c, key)
+			return
+		}
+		if header := c.GetHeader("Authorization"); header != "" {
+			bearerAuth(c, header)
+			return
+		}
+
+		botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
+		if botToken == "" {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": ErrBotTokenMissing.Error()})
+			return
+		}
+
+		var (
+			identity *TelegramIdentity
+			err      error
+		)
+		switch {
+		case c.GetHeader(HeaderInitData) != "":
+			identity, err = ValidateWebAppInitData(c.GetHeader(HeaderInitData), botToken, authMaxAge())
+		case c.GetHeader(HeaderLoginData) != "":
+			identity, err = ValidateLoginWidget(c.GetHeader(HeaderLoginData), botToken, authMaxAge())
+		default:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Требуется авторизация Telegram", "code": "AUTH_REQUIRED"})
+			return
+		}
+		if err != nil {
+			code := "AUTH_INVALID"
+			if err == ErrAuthDataExpired {
+				code = "AUTH_EXPIRED"
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": code})
+			return
+		}
+
+		c.Set(telegramIDKey, identity.ID)
+
+		var user models.User
+		if err := storage.DB.Where("telegram_id = ?", identity.ID).First(&user).Error; err == nil {
+			c.Set(userKey, &user)
+		}
+
+		c.Next()
+	}
+}
+
+// bearerAuth аутентифицирует запрос по access-токену из заголовка Authorization.
+func bearerAuth(c *gin.Context, header string) {
+	raw, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || raw == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Ожидается заголовок Authorization: Bearer <token>", "code": "AUTH_INVALID"})
+		return
+	}
+
+	claims, err := ParseAccessToken(raw)
+	if err != nil {
+		code := "TOKEN_INVALID"
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			code = "TOKEN_EXPIRED"
+		case errors.Is(err, ErrTokenRevoked):
+			code = "TOKEN_REVOKED"
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": code})
+		return
+	}
+
+	var user models.User
+	if err := storage.DB.First(&user, claims.Subject).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	c.Set(telegramIDKey, user.TelegramID)
+	c.Set(userKey, &user)
+	c.Set(claimsKey, claims)
+	c.Next()
+}
+
+// RequireUser пропускает запрос дальше, только если подтверждённый пользователь зарегистрирован.
+// Используется после Middleware.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentUser(c) == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// TelegramID возвращает telegram_id, подтверждённый Middleware.
+func TelegramID(c *gin.Context) string {
+	return c.GetString(telegramIDKey)
+}
+
+// CurrentUser возвращает пользователя, от имени которого выполняется запрос, или nil.
+func CurrentUser(c *gin.Context) *models.User {
+	if v, ok := c.Get(userKey); ok {
+		if user, ok := v.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
+// currentClaims возвращает claims access-токена, если запрос аутентифицирован по Bearer.
+func currentClaims(c *gin.Context) *AccessClaims {
+	if v, ok := c.Get(claimsKey); ok {
+		if claims, ok := v.(*AccessClaims); ok {
+			return claims
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultAuthMaxAge — срок годности подписанных данных Telegram, если TELEGRAM_AUTH_MAX_AGE не задан.
+const defaultAuthMaxAge = 24 * time.Hour
+
+var (
+	ErrBotTokenMissing = errors.New("TELEGRAM_BOT_TOKEN не задан")
+	ErrHashMissing     = errors.New("в данных Telegram отсутствует hash")
+	ErrHashInvalid     = errors.New("неверная подпись данных Telegram")
+	ErrAuthDateInvalid = errors.New("неверное значение auth_date")
+	ErrAuthDataExpired = errors.New("данные авторизации Telegram устарели")
+	ErrUserMissing     = errors.New("в данных Telegram отсутствует пользователь")
+)
+
+// TelegramIdentity — пользователь Telegram, подтверждённый подписью бота.
+type TelegramIdentity struct {
+	ID        string
+	FirstName string
+	LastName  string
+	Username  string
+	AuthDate  time.Time
+}
+
+// ValidateWebAppInitData проверяет initData, переданные Telegram WebApp.
+// Секретный ключ — HMAC-SHA256 токена бота с ключом "WebAppData".
+func ValidateWebAppInitData(initData, botToken string, maxAge time.Duration) (*TelegramIdentity, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := hmacSHA256([]byte("WebAppData"), []byte(botToken))
+	authDate, err := checkSignature(values, secret, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := values.Get("user")
+	if rawUser == "" {
+		return nil, ErrUserMissing
+	}
+	var user struct {
+		ID        int64  `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Username  string `json:"username"`
+	}
+	if err := json.Unmarshal([]byte(rawUser), &user); err != nil || user.ID == 0 {
+		return nil, ErrUserMissing
+	}
+
+	return &TelegramIdentity{
+		ID:        strconv.FormatInt(user.ID, 10),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Username:  user.Username,
+		AuthDate:  authDate,
+	}, nil
+}
+
+// ValidateLoginWidget проверяет данные Telegram Login Widget.
+// Секретный ключ — SHA256 токена бота.
+func ValidateLoginWidget(data, botToken string, maxAge time.Duration) (*TelegramIdentity, error) {
+	values, err := url.ParseQuery(data)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := sha256.Sum256([]byte(botToken))
+	authDate, err := checkSignature(values, secret[:], maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	id := values.Get("id")
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, ErrUserMissing
+	}
+
+	return &TelegramIdentity{
+		ID:        id,
+		FirstName: values.Get("first_name"),
+		LastName:  values.Get("last_name"),
+		Username:  values.Get("username"),
+		AuthDate:  authDate,
+	}, nil
+}
+
+// checkSignature сверяет hash с data-check-string и проверяет свежесть auth_date.
+func checkSignature(values url.Values, secret []byte, maxAge time.Duration) (time.Time, error) {
+	hash := values.Get("hash")
+	if hash == "" {
+		return time.Time{}, ErrHashMissing
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key != "hash" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	dataCheckString := strings.Join(pairs, "\n")
+
+	expected := hex.EncodeToString(hmacSHA256(secret, []byte(dataCheckString)))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(hash))) {
+		return time.Time{}, ErrHashInvalid
+	}
+
+	unix, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrAuthDateInvalid
+	}
+	authDate := time.Unix(unix, 0)
+	if time.Since(authDate) > maxAge {
+		return time.Time{}, ErrAuthDataExpired
+	}
+
+	return authDate, nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// authMaxAge возвращает допустимый возраст auth_date из TELEGRAM_AUTH_MAX_AGE (например, "1h").
+func authMaxAge() time.Duration {
+	return durationFromEnv("TELEGRAM_AUTH_MAX_AGE", defaultAuthMaxAge)
+}
@@ -0,0 +1,54 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// ErrNotFound возвращается, если объекта с таким ключом нет в хранилище.
+var ErrNotFound = errors.New("объект не найден в хранилище")
+
+// Store — хранилище файлов. Ключ — путь объекта вида tasks/12/3f9c...; хранилище не знает, к чему относится файл.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Files — хранилище вложений, выбранное при запуске через Connect.
+var Files Store
+
+// Connect выбирает хранилище по переменной BLOB_STORAGE: local (по умолчанию) или s3.
+func Connect() {
+	store, err := FromEnv()
+	if err != nil {
+		log.Fatal("Ошибка подключения хранилища файлов: ", err)
+	}
+	Files = store
+}
+
+// FromEnv создаёт хранилище по переменным окружения.
+func FromEnv() (Store, error) {
+	switch driver := os.Getenv("BLOB_STORAGE"); driver {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("неизвестное хранилище %q: ожидается local или s3", driver)
+	}
+}
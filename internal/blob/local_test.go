@@ -0,0 +1,40 @@
+package blob
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalPath(t *testing.T) {
+	l := &Local{Dir: "uploads"}
+
+	valid := map[string]string{
+		"tasks/12/3f9c": filepath.Join("uploads", "tasks", "12", "3f9c"),
+		"a.txt":         filepath.Join("uploads", "a.txt"),
+	}
+	for key, want := range valid {
+		got, err := l.path(key)
+		if err != nil || got != want {
+			t.Errorf("path(%q) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"..",
+		"../secret",
+		"tasks/../../secret",
+		"tasks/..",
+		"./a",
+		"/etc/passwd",
+		"tasks//a",
+		"tasks/",
+		`..\secret`,
+		`tasks\12\a`,
+	}
+	for _, key := range invalid {
+		if got, err := l.path(key); err == nil {
+			t.Errorf("path(%q) = %q; want ошибку", key, got)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey — ключ для обращения к API от имени сервиса (бот, интеграции).
+type APIKey struct {
+	gorm.Model
+	Name           string     `gorm:"not null"`
+	Prefix         string     `gorm:"not null"`             // Начало ключа для отображения в списке
+	KeyHash        string     `gorm:"uniqueIndex;not null"` // SHA-256 от ключа, сам ключ не хранится
+	Scopes         string     `gorm:"not null"`             // Права через пробел, например "tasks:read tasks:write"
+	CanActOnBehalf bool       `gorm:"default:false"`        // Может выполнять запросы от имени пользователя Telegram
+	CreatedBy      uint       // ID пользователя, создавшего ключ
+	CreatedByKey   *uint      // ID API-ключа, которым создан ключ; такой ключ может его ротировать и отзывать
+	ExpiresAt      *time.Time // nil — бессрочный ключ
+	LastUsedAt     *time.Time
+	RevokedAt      *time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Attachment — файл, загруженный к задаче, комментарию или работе на проверке. Содержимое лежит
+// в хранилище файлов (см. пакет blob) под ключом StorageKey, в базе — только описание.
+type Attachment struct {
+	ID          uint   `gorm:"primaryKey"`
+	TaskID      uint   `gorm:"not null;index"`
+	CommentID   *uint  `gorm:"index"` // Комментарий, к которому приложен файл
+	ReviewID    *uint  `gorm:"index"` // Работа на проверке, к отчёту которой приложен файл
+	UploaderID  uint   `gorm:"not null"`
+	FileName    string `gorm:"not null"`
+	ContentType string `gorm:"not null"`
+	Size        int64  `gorm:"not null"`
+	StorageKey  string `gorm:"not null;uniqueIndex"`
+	Uploader    User   `gorm:"foreignKey:UploaderID"`
+	CreatedAt   time.Time
+}
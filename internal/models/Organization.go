@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organization объединяет отделы и команды компании.
+type Organization struct {
+	gorm.Model
+	Name        string `gorm:"not null"`
+	Description string
+	OwnerID     uint         `gorm:"not null;index"` // ID владельца организации, видит все отделы и команды
+	Departments []Department `gorm:"foreignKey:OrganizationID"`
+}
+
+// Department — отдел организации. Отделы могут быть вложены друг в друга,
+// команды привязываются к отделу через Team.DepartmentID.
+type Department struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"not null;index;constraint:OnDelete:CASCADE;"`
+	ParentID       *uint  `gorm:"index"` // Родительский отдел, nil — отдел верхнего уровня
+	Name           string `gorm:"not null"`
+	HeadID         *uint  // ID руководителя отдела, видит задачи и встречи всех команд отдела и вложенных отделов
+	Head           *User  `gorm:"foreignKey:HeadID"`
+	Teams          []Team `gorm:"foreignKey:DepartmentID"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
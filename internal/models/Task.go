@@ -1,20 +1,218 @@
-package models
-
-import (
-	"time"
-
-	"gorm.io/gorm"
-)
-
-// Task представляет задачу, которая может быть назначена команде или конкретному участнику.
-type Task struct {
-	gorm.Model
-	Title       string `gorm:"not null"`
-	Description string
-	Deadline    time.Time // Срок выполнения
-	Status      string    `gorm:"not null; default:'assigned'"` // Статусы: assigned, in_progress, completed
-	IsTeam      bool      `gorm:"default:false"`                // true — задача для всей команды
-	AssignedTo  *string   // ID пользователя (nil, если IsTeam = true)
-	CreatedBy   uint      `gorm:"not null"` // ID создателя
-	TeamID      uint      `gorm:"not null"` // ID команды
-}
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Task представляет задачу, которая может быть назначена команде или конкретному участнику.
+type Task struct {
+	gorm.Model
+	Title          string `gorm:"not null"`
+	Description    string
+	Deadline       time.Time // Срок выполнения
+	Status         string    `gorm:"not null; default:'assigned'"` // Статус из процесса команды; стандартный: assigned, in_progress, in_review, completed
+	IsTeam         bool      `gorm:"default:false"`                // true — задача для всей команды
+	AssignedTo     *string   // ID пользователя (nil, если IsTeam = true)
+	CreatedBy      uint      `gorm:"not null"` // ID создателя
+	TeamID         uint      `gorm:"not null"` // ID команды
+	DepartmentID   *uint     // Отдел, руководитель которого поставил задачу нескольким командам
+	CompletionRule string    `gorm:"not null;default:'all'"`    // Когда командная задача выполнена: all — всеми участниками, any — любым, quorum — долей QuorumPercent
+	QuorumPercent  int       `gorm:"not null;default:50"`       // Доля участников для правила quorum, %
+	ParentID       *uint     `gorm:"index"`                     // Родительская задача, если это подзадача
+	Priority       string    `gorm:"not null;default:'medium'"` // low, medium, high или urgent
+	StoryPoints    *int      // Оценка в story points
+	EstimateHours  *float64  // Оценка в часах
+	SeriesID       *uint     `gorm:"index"` // Серия повторяющихся задач, по которой создана задача
+}
+
+// TaskSeries — повторяющаяся задача: шаблон задачи и правило, по которому создаются её повторения.
+// Повторение — это срок задачи; следующее повторение создаётся, когда выполнено предыдущее или наступил его срок.
+type TaskSeries struct {
+	ID             uint   `gorm:"primaryKey"`
+	TeamID         uint   `gorm:"not null;index"`
+	CreatedBy      uint   `gorm:"not null"`
+	Title          string `gorm:"not null"`
+	Description    string
+	IsTeam         bool    `gorm:"default:false"`
+	AssignedTo     *string // Telegram ID исполнителя персональной задачи
+	CompletionRule string  `gorm:"not null;default:'all'"`
+	QuorumPercent  int     `gorm:"not null;default:50"`
+	Priority       string  `gorm:"not null;default:'medium'"`
+	StoryPoints    *int
+	EstimateHours  *float64
+	Labels         []TaskSeriesLabel `gorm:"foreignKey:SeriesID"` // Метки, которые получает каждая задача серии
+	Rule           string            `gorm:"not null"`            // Правило повторения в формате RRULE, например FREQ=WEEKLY;BYDAY=MO
+	StartAt        time.Time         // Срок первого повторения, от него отсчитываются остальные
+	Generated      int               `gorm:"not null;default:0"` // Сколько повторений создано
+	LastAt         *time.Time        // Срок последнего созданного повторения
+	NextAt         *time.Time        // Срок следующего повторения; nil, если серия закончилась или остановлена
+	StoppedAt      *time.Time        // Когда серию остановили
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskTemplate — шаблон задачи команды. В заголовке, описании и пунктах чек-листа могут быть переменные
+// вида {{name}}, которые подставляются при создании задач по шаблону.
+type TaskTemplate struct {
+	ID             uint   `gorm:"primaryKey"`
+	TeamID         uint   `gorm:"not null;uniqueIndex:idx_template_team_name"`
+	Name           string `gorm:"not null;uniqueIndex:idx_template_team_name"`
+	Title          string `gorm:"not null"`
+	Description    string
+	Deadline       string                 `gorm:"not null"` // Срок относительно создания задачи, например "+3 days"
+	IsTeam         bool                   `gorm:"default:false"`
+	Assignees      []TaskTemplateAssignee `gorm:"foreignKey:TemplateID"` // Исполнители персональных задач по умолчанию
+	CompletionRule string                 `gorm:"not null;default:'all'"`
+	QuorumPercent  int                    `gorm:"not null;default:50"`
+	Priority       string                 `gorm:"not null;default:'medium'"`
+	StoryPoints    *int
+	EstimateHours  *float64
+	Labels         []TaskTemplateLabel `gorm:"foreignKey:TemplateID"` // Метки, которые получает каждая задача по шаблону
+	CreatedBy      uint                `gorm:"not null"`
+	Items          []TaskTemplateItem  `gorm:"foreignKey:TemplateID"` // Пункты чек-листа по умолчанию
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskTemplateItem — пункт чек-листа, который получает задача, созданная по шаблону.
+type TaskTemplateItem struct {
+	ID         uint   `gorm:"primaryKey"`
+	TemplateID uint   `gorm:"not null;index"`
+	Text       string `gorm:"not null"`
+	Position   int    `gorm:"not null"`
+}
+
+// TaskTemplateAssignee — исполнитель персональных задач по умолчанию в шаблоне. Связь хранится и после
+// выхода пользователя из команды, чтобы это было видно в шаблоне.
+type TaskTemplateAssignee struct {
+	TemplateID uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"primaryKey;index"`
+	User       User `gorm:"foreignKey:UserID"`
+}
+
+// Label — метка команды, которой помечают задачи; у каждой команды свой набор меток.
+type Label struct {
+	ID        uint   `gorm:"primaryKey"`
+	TeamID    uint   `gorm:"not null;uniqueIndex:idx_label_team_name"`
+	Name      string `gorm:"not null;uniqueIndex:idx_label_team_name"`
+	Color     string `gorm:"not null"` // Цвет в формате #RRGGBB
+	CreatedBy uint   `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TaskLabel — метка на задаче.
+type TaskLabel struct {
+	TaskID  uint  `gorm:"primaryKey"`
+	LabelID uint  `gorm:"primaryKey;index"`
+	Label   Label `gorm:"foreignKey:LabelID"`
+}
+
+// TaskSeriesLabel — метка серии повторяющихся задач.
+type TaskSeriesLabel struct {
+	SeriesID uint  `gorm:"primaryKey"`
+	LabelID  uint  `gorm:"primaryKey;index"`
+	Label    Label `gorm:"foreignKey:LabelID"`
+}
+
+// TaskTemplateLabel — метка шаблона задачи.
+type TaskTemplateLabel struct {
+	TemplateID uint  `gorm:"primaryKey"`
+	LabelID    uint  `gorm:"primaryKey;index"`
+	Label      Label `gorm:"foreignKey:LabelID"`
+}
+
+// TaskDependency — зависимость между задачами: задачу TaskID нельзя начать, пока не завершена BlockerID.
+type TaskDependency struct {
+	ID        uint `gorm:"primaryKey"`
+	TaskID    uint `gorm:"not null;uniqueIndex:idx_dependency_task_blocker"`
+	BlockerID uint `gorm:"not null;uniqueIndex:idx_dependency_task_blocker;index"`
+	CreatedBy uint `gorm:"not null"`
+	Blocker   Task `gorm:"foreignKey:BlockerID"`
+	Task      Task `gorm:"foreignKey:TaskID"`
+	CreatedAt time.Time
+}
+
+// ChecklistItem — пункт чек-листа внутри задачи, который исполнитель отмечает по ходу работы.
+type ChecklistItem struct {
+	ID         uint   `gorm:"primaryKey"`
+	TaskID     uint   `gorm:"not null;index"`
+	Text       string `gorm:"not null"`
+	Position   int    `gorm:"not null"`
+	Done       bool   `gorm:"not null;default:false"`
+	DoneBy     *uint  // Кто отметил пункт выполненным
+	DoneAt     *time.Time
+	CreatedBy  uint  `gorm:"not null"`
+	DoneByUser *User `gorm:"foreignKey:DoneBy"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TaskAssignment — статус командной задачи у отдельного участника команды.
+type TaskAssignment struct {
+	ID             uint   `gorm:"primaryKey"`
+	TaskID         uint   `gorm:"not null;uniqueIndex:idx_assignment_task_user;constraint:OnDelete:CASCADE;"`
+	UserID         uint   `gorm:"not null;uniqueIndex:idx_assignment_task_user"`
+	Status         string `gorm:"not null;default:'assigned'"` // Статус из процесса команды
+	CompletionText string // Отчёт участника о выполнении своей части
+	CompletedAt    *time.Time
+	User           User `gorm:"foreignKey:UserID"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskReview — работа исполнителя, отправленная на проверку автору задачи, и решение по ней.
+type TaskReview struct {
+	ID             uint   `gorm:"primaryKey"`
+	TaskID         uint   `gorm:"not null;index;constraint:OnDelete:CASCADE;"`
+	UserID         uint   `gorm:"not null"` // Исполнитель, отправивший работу
+	CompletionText string // Отчёт исполнителя
+	Attachment     string
+	Status         string `gorm:"not null;default:'pending'"` // pending, approved или rejected
+	ReviewerID     *uint
+	Comment        string // Комментарий проверяющего, обязателен при отклонении
+	DecidedAt      *time.Time
+	Task           Task         `gorm:"foreignKey:TaskID"`
+	User           User         `gorm:"foreignKey:UserID"`
+	Reviewer       *User        `gorm:"foreignKey:ReviewerID"`
+	Attachments    []Attachment `gorm:"foreignKey:ReviewID"` // Файлы к отчёту исполнителя
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskEvent — запись истории задачи: создание, изменения, переходы статусов, проверка работы и удаление.
+type TaskEvent struct {
+	ID             uint   `gorm:"primaryKey"`
+	TaskID         uint   `gorm:"not null;index"`
+	Type           string `gorm:"not null"` // created, updated, reassigned, status_changed, review_approved, review_rejected, deleted
+	ActorID        uint   `gorm:"not null"` // Кто выполнил действие
+	UserID         *uint  // Исполнитель, чей статус изменился
+	FromStatus     string
+	ToStatus       string
+	TaskStatus     string // Общий статус задачи после события
+	CompletionText string
+	Attachment     string
+	Comment        string // Комментарий проверяющего
+	Changes        string // Изменённые поля в JSON: [{"field": ..., "old": ..., "new": ...}]
+	Actor          User   `gorm:"foreignKey:ActorID"`
+	User           *User  `gorm:"foreignKey:UserID"`
+	CreatedAt      time.Time
+}
+
+// TaskComment — комментарий к задаче. Ответ на другой комментарий ссылается на него через ParentID.
+type TaskComment struct {
+	ID          uint   `gorm:"primaryKey"`
+	TaskID      uint   `gorm:"not null;index"`
+	ParentID    *uint  `gorm:"index"` // Комментарий, на который это ответ
+	AuthorID    uint   `gorm:"not null"`
+	Text        string `gorm:"not null"`
+	Mentions    string // Telegram ID упомянутых участников через пробел
+	EditedAt    *time.Time
+	Author      User         `gorm:"foreignKey:AuthorID"`
+	Attachments []Attachment `gorm:"foreignKey:CommentID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
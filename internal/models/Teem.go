@@ -9,20 +9,80 @@ import (
 // Team представляет команду, созданную руководителем.
 type Team struct {
 	gorm.Model
-	Name        string `gorm:"not null"`
-	Description string
-	ManagerID   uint         `gorm:"not null;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"` // ID пользователя-руководителя
-	InviteLinks []InviteLink `gorm:"foreignKey:TeamID"`
-	Members     []User       `gorm:"foreignKey:TeamID"` // Участники команды
-	Tasks       []Task       `gorm:"foreignKey:TeamID"` // Задачи, связанные с командой
-	Meetings    []Meeting    `gorm:"foreignKey:TeamID"` // Встречи команды
+	Name             string `gorm:"not null"`
+	Description      string
+	ManagerID        uint             `gorm:"not null;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"` // ID владельца команды
+	RequiresApproval bool             `gorm:"not null;default:false"`                                 // Вступление по приглашению требует одобрения руководителя
+	ArchivedAt       *time.Time       // Команда в архиве: задачи и встречи доступны только для просмотра
+	ArchivedBy       *uint            // ID пользователя, отправившего команду в архив
+	DepartmentID     *uint            `gorm:"index"` // Отдел организации, в который входит команда
+	InviteLinks      []InviteLink     `gorm:"foreignKey:TeamID"`
+	Members          []User           `gorm:"foreignKey:TeamID"` // Пользователи, у которых команда выбрана текущей
+	Memberships      []TeamMembership `gorm:"foreignKey:TeamID"` // Участники команды и их роли
+	Tasks            []Task           `gorm:"foreignKey:TeamID"` // Задачи, связанные с командой
+	Meetings         []Meeting        `gorm:"foreignKey:TeamID"` // Встречи команды
+}
+
+// TeamMembership — участие пользователя в команде и его роль в ней.
+type TeamMembership struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_membership_user_team"`
+	TeamID    uint   `gorm:"not null;uniqueIndex:idx_membership_user_team;index;constraint:OnDelete:CASCADE;"`
+	Role      string `gorm:"not null;default:'member'"` // owner, manager, member или observer
+	User      User   `gorm:"foreignKey:UserID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type InviteLink struct {
 	ID        uint      `gorm:"primaryKey"`
 	Code      string    `gorm:"uniqueIndex;not null"`
 	TeamID    uint      `gorm:"not null;index;constraint:OnDelete:CASCADE;"`
+	Role      string    `gorm:"not null;default:'member'"` // Роль, которую получает вступивший по ссылке
+	MaxUses   int       `gorm:"not null;default:0"`        // 0 — без ограничения, 1 — одноразовая ссылка
+	Uses      int       `gorm:"not null;default:0"`        // Сколько раз по ссылке вступили
+	CreatedBy uint      // ID пользователя, создавшего ссылку
 	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// InviteUse — запись о вступлении пользователя в команду по ссылке-приглашению.
+type InviteUse struct {
+	ID           uint   `gorm:"primaryKey"`
+	InviteLinkID uint   `gorm:"not null;index"`
+	TeamID       uint   `gorm:"not null;index"`
+	UserID       uint   `gorm:"not null"`
+	Role         string `gorm:"not null"`
+	User         User   `gorm:"foreignKey:UserID"`
+	CreatedAt    time.Time
+}
+
+// OwnershipTransfer — предложение передать права владельца команды другому участнику.
+// Права переходят только после того, как новый владелец примет предложение.
+type OwnershipTransfer struct {
+	ID         uint      `gorm:"primaryKey"`
+	TeamID     uint      `gorm:"not null;index"`
+	FromUserID uint      `gorm:"not null"`                   // Текущий владелец
+	ToUserID   uint      `gorm:"not null;index"`             // Будущий владелец
+	Status     string    `gorm:"not null;default:'pending'"` // pending, accepted, declined, cancelled
+	ExpiresAt  time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// JoinRequest — заявка на вступление в команду, которая требует одобрения руководителя.
+type JoinRequest struct {
+	ID           uint   `gorm:"primaryKey"`
+	TeamID       uint   `gorm:"not null;index"`
+	UserID       uint   `gorm:"not null;index"`
+	InviteLinkID uint   `gorm:"not null"`
+	Role         string `gorm:"not null"`                   // Роль из приглашения, которую получит участник
+	Status       string `gorm:"not null;default:'pending'"` // pending, approved или rejected
+	DecidedBy    *uint  // ID руководителя, принявшего решение
+	DecidedAt    *time.Time
+	User         User `gorm:"foreignKey:UserID"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken хранит хеш refresh-токена, выданного пользователю.
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"uniqueIndex;not null"` // SHA-256 от токена, сам токен не хранится
+	ExpiresAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time // Время отзыва (logout или ротация)
+}
+
+// RevokedToken — отозванный access-токен. Запись живёт до истечения срока действия токена.
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	JTI       string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
@@ -4,11 +4,10 @@ import (
 	"gorm.io/gorm"
 )
 
-// User представляет зарегистрированного пользователя (руководитель или участник).
+// User представляет зарегистрированного пользователя. Роль пользователя задаётся отдельно в каждой команде (TeamMembership).
 type User struct {
 	gorm.Model
 	TelegramID string `gorm:"uniqueIndex;not null"` // Уникальный идентификатор Telegram
 	Name       string `gorm:"not null"`
-	Role       string `gorm:"not null"` // "manager" или "member"
-	TeamID     *uint  // Для участников — ID команды, к которой они принадлежат
+	TeamID     *uint  // Активная команда пользователя, все его команды — в TeamMembership
 }
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Workflow — процесс работы над задачами команды: статусы и разрешённые переходы между ними.
+// Команда без своего процесса использует стандартный (см. пакет workflow).
+type Workflow struct {
+	ID          uint                 `gorm:"primaryKey"`
+	TeamID      uint                 `gorm:"not null;uniqueIndex"`
+	Statuses    []WorkflowStatus     `gorm:"foreignKey:WorkflowID;constraint:OnDelete:CASCADE;"`
+	Transitions []WorkflowTransition `gorm:"foreignKey:WorkflowID;constraint:OnDelete:CASCADE;"`
+	UpdatedBy   uint
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WorkflowStatus — статус задачи в процессе команды. Первый по Position статус назначается новым задачам.
+type WorkflowStatus struct {
+	ID         uint   `gorm:"primaryKey"`
+	WorkflowID uint   `gorm:"not null;index"`
+	Key        string `gorm:"not null"` // Значение поля Task.Status
+	Title      string `gorm:"not null"`
+	Position   int    `gorm:"not null"`
+	Terminal   bool   // Работа по задаче завершена
+	Review     bool   // Работа ждёт проверки автором задачи
+}
+
+// WorkflowTransition — разрешённый переход между статусами.
+type WorkflowTransition struct {
+	ID         uint   `gorm:"primaryKey"`
+	WorkflowID uint   `gorm:"not null;index"`
+	FromStatus string `gorm:"not null"`
+	ToStatus   string `gorm:"not null"`
+	Roles      string // Роли через пробел, например "manager member"; пусто — все, кто может работать с задачами
+}
@@ -0,0 +1,559 @@
+package org
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreateOrganizationInput struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+func toOrganizationResponse(org models.Organization) response.OrganizationResponse {
+	resp := response.OrganizationResponse{
+		ID:          org.ID,
+		Name:        org.Name,
+		Description: org.Description,
+		CreatedAt:   org.CreatedAt,
+	}
+
+	var owner models.User
+	if err := storage.DB.First(&owner, org.OwnerID).Error; err == nil {
+		resp.OwnerTelegramID = owner.TelegramID
+	}
+	return resp
+}
+
+// CreateOrganizationHandler создаёт организацию
+// @Summary Создание организации
+// @Description Создаёт организацию, пользователь становится её владельцем. Владелец видит все отделы и команды организации.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body CreateOrganizationInput true "Данные организации"
+// @Success 200 {object} response.OrganizationResponse "Созданная организация"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании организации"
+// @Router /org [post]
+func CreateOrganizationHandler(c *gin.Context) {
+	var input CreateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	org := models.Organization{Name: input.Name, Description: input.Description, OwnerID: user.ID}
+	if err := storage.DB.Create(&org).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании организации"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrganizationResponse(org))
+}
+
+// GetMyOrganizationsHandler возвращает организации пользователя
+// @Summary Мои организации
+// @Description Возвращает организации, которыми пользователь владеет или в которых руководит отделом.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} response.OrganizationResponse "Список организаций"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении организаций"
+// @Router /org/my [get]
+func GetMyOrganizationsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var orgs []models.Organization
+	if err := storage.DB.
+		Where("owner_id = ? OR id IN (?)", user.ID,
+			storage.DB.Model(&models.Department{}).Select("organization_id").Where("head_id = ?", user.ID)).
+		Order("name").Find(&orgs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении организаций"})
+		return
+	}
+
+	resp := make([]response.OrganizationResponse, 0, len(orgs))
+	for _, org := range orgs {
+		resp = append(resp, toOrganizationResponse(org))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetOrganizationHandler возвращает структуру организации
+// @Summary Структура организации
+// @Description Возвращает дерево отделов организации с командами. Владелец организации видит всё дерево, руководитель отдела — свои отделы вместе с вложенными.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID организации"
+// @Success 200 {object} response.OrganizationTreeResponse "Структура организации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Нет доступа к организации Code: NOT_IN_ORGANIZATION"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Организация не найдена Code: ORGANIZATION_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении структуры организации"
+// @Router /org/{id} [get]
+func GetOrganizationHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var org models.Organization
+	if err := storage.DB.First(&org, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Организация не найдена", "code": "ORGANIZATION_NOT_FOUND"})
+		return
+	}
+
+	var departments []models.Department
+	if err := storage.DB.Preload("Head").Preload("Teams").
+		Where("organization_id = ?", org.ID).Order("name").Find(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении структуры организации"})
+		return
+	}
+
+	children := make(map[uint][]models.Department)
+	var roots []models.Department
+	for _, d := range departments {
+		switch {
+		case org.OwnerID == user.ID && d.ParentID == nil:
+			roots = append(roots, d)
+		case org.OwnerID != user.ID && d.HeadID != nil && *d.HeadID == user.ID:
+			// Руководитель видит отделы, которыми руководит, вместе с вложенными
+			roots = append(roots, d)
+		}
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d)
+		}
+	}
+
+	if org.OwnerID != user.ID && len(roots) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Нет доступа к организации", "code": "NOT_IN_ORGANIZATION"})
+		return
+	}
+
+	resp := response.OrganizationTreeResponse{
+		OrganizationResponse: toOrganizationResponse(org),
+		Departments:          make([]response.DepartmentResponse, 0, len(roots)),
+	}
+	for _, d := range roots {
+		resp.Departments = append(resp.Departments, buildDepartmentTree(d, children, map[uint]bool{}))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildDepartmentTree собирает отдел с вложенными отделами. visited защищает от цикла в данных.
+func buildDepartmentTree(d models.Department, children map[uint][]models.Department, visited map[uint]bool) response.DepartmentResponse {
+	visited[d.ID] = true
+	resp := toDepartmentResponse(d)
+	for _, child := range children[d.ID] {
+		if !visited[child.ID] {
+			resp.Children = append(resp.Children, buildDepartmentTree(child, children, visited))
+		}
+	}
+	return resp
+}
+
+func toDepartmentResponse(d models.Department) response.DepartmentResponse {
+	resp := response.DepartmentResponse{
+		ID:             d.ID,
+		OrganizationID: d.OrganizationID,
+		ParentID:       d.ParentID,
+		Name:           d.Name,
+		Teams:          make([]response.DepartmentTeamResponse, 0, len(d.Teams)),
+		Children:       []response.DepartmentResponse{},
+	}
+	if d.Head != nil {
+		resp.HeadTelegramID = d.Head.TelegramID
+		resp.HeadName = d.Head.Name
+	}
+	for _, team := range d.Teams {
+		resp.Teams = append(resp.Teams, response.DepartmentTeamResponse{
+			ID:       team.ID,
+			Name:     team.Name,
+			Archived: team.ArchivedAt != nil,
+		})
+	}
+	return resp
+}
+
+// findUserByTelegramID ищет пользователя для назначения руководителем отдела. При ошибке пишет ответ.
+func findUserByTelegramID(c *gin.Context, telegramID string) (*models.User, bool) {
+	var user models.User
+	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден", "code": "USER_NOT_FOUND"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// requireStructureAccess проверяет право менять положение отдела в структуре и его руководителя:
+// это может владелец организации или руководитель одного из родительских отделов.
+// При отказе пишет ответ и возвращает false.
+func requireStructureAccess(c *gin.Context, user *models.User, organizationID uint, parentID *uint) bool {
+	if parentID != nil {
+		_, ok := access.RequireDepartment(c, user, *parentID)
+		return ok
+	}
+
+	var org models.Organization
+	if err := storage.DB.First(&org, organizationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Организация не найдена", "code": "ORGANIZATION_NOT_FOUND"})
+		return false
+	}
+	if org.OwnerID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Отделы верхнего уровня может менять только владелец организации",
+			"code":  "NOT_ORGANIZATION_OWNER",
+		})
+		return false
+	}
+	return true
+}
+
+type CreateDepartmentInput struct {
+	Name           string `json:"name" binding:"required"`
+	ParentID       *uint  `json:"parent_id"`        // Родительский отдел, если не указан — отдел верхнего уровня
+	HeadTelegramID string `json:"head_telegram_id"` // Руководитель отдела
+}
+
+// CreateDepartmentHandler создаёт отдел организации
+// @Summary Создание отдела
+// @Description Создаёт отдел в организации. Отдел верхнего уровня создаёт владелец организации, вложенный — владелец или руководитель родительского отдела.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID организации"
+// @Param input body CreateDepartmentInput true "Данные отдела"
+// @Success 200 {object} response.DepartmentResponse "Созданный отдел"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации или родительский отдел из другой организации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER, Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Организация не найдена Code: ORGANIZATION_NOT_FOUND, Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND, Error: Пользователь не найден Code: USER_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании отдела"
+// @Router /org/{id}/departments [post]
+func CreateDepartmentHandler(c *gin.Context) {
+	var input CreateDepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Организация не найдена", "code": "ORGANIZATION_NOT_FOUND"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	if !requireStructureAccess(c, user, uint(orgID), input.ParentID) {
+		return
+	}
+
+	department := models.Department{OrganizationID: uint(orgID), ParentID: input.ParentID, Name: input.Name}
+	if input.ParentID != nil {
+		var parent models.Department
+		if err := storage.DB.First(&parent, *input.ParentID).Error; err != nil || parent.OrganizationID != department.OrganizationID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Родительский отдел относится к другой организации"})
+			return
+		}
+	}
+	if input.HeadTelegramID != "" {
+		head, ok := findUserByTelegramID(c, input.HeadTelegramID)
+		if !ok {
+			return
+		}
+		department.HeadID = &head.ID
+		department.Head = head
+	}
+
+	if err := storage.DB.Create(&department).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании отдела"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toDepartmentResponse(department))
+}
+
+type UpdateDepartmentInput struct {
+	Name           string  `json:"name"`             // Новое название, если пусто — не меняется
+	ParentID       *uint   `json:"parent_id"`        // Новый родительский отдел; 0 — сделать отдел верхнего уровня
+	HeadTelegramID *string `json:"head_telegram_id"` // Новый руководитель; пустая строка снимает руководителя
+}
+
+// UpdateDepartmentHandler изменяет отдел
+// @Summary Изменение отдела
+// @Description Переименовывает отдел (доступно и его руководителю), меняет руководителя или переносит отдел в другой родительский. Руководителя и положение в структуре меняет владелец организации или руководитель родительского отдела. Отдел нельзя перенести внутрь самого себя.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID отдела"
+// @Param input body UpdateDepartmentInput true "Изменения"
+// @Success 200 {object} response.DepartmentResponse "Изменённый отдел"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Отдел нельзя перенести внутрь самого себя Code: DEPARTMENT_CYCLE"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND, Error: Пользователь не найден Code: USER_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении отдела"
+// @Router /org/departments/{id} [put]
+func UpdateDepartmentHandler(c *gin.Context) {
+	var input UpdateDepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отдел не найден", "code": "DEPARTMENT_NOT_FOUND"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	department, ok := access.RequireDepartment(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	if input.Name != "" {
+		department.Name = input.Name
+	}
+
+	if input.HeadTelegramID != nil || input.ParentID != nil {
+		// Руководитель отдела не может сам себя сменить или перенести свой отдел
+		if !requireStructureAccess(c, user, department.OrganizationID, department.ParentID) {
+			return
+		}
+	}
+
+	if input.HeadTelegramID != nil {
+		department.HeadID = nil
+		if *input.HeadTelegramID != "" {
+			head, ok := findUserByTelegramID(c, *input.HeadTelegramID)
+			if !ok {
+				return
+			}
+			department.HeadID = &head.ID
+		}
+	}
+
+	if input.ParentID != nil {
+		department.ParentID = nil
+		if *input.ParentID != 0 {
+			subtree, err := access.DepartmentSubtree(department)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении отдела"})
+				return
+			}
+			for _, child := range subtree {
+				if child == *input.ParentID {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Отдел нельзя перенести внутрь самого себя", "code": "DEPARTMENT_CYCLE"})
+					return
+				}
+			}
+
+			parent, ok := access.RequireDepartment(c, user, *input.ParentID)
+			if !ok {
+				return
+			}
+			if parent.OrganizationID != department.OrganizationID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Родительский отдел относится к другой организации"})
+				return
+			}
+			department.ParentID = &parent.ID
+		} else if !requireStructureAccess(c, user, department.OrganizationID, nil) {
+			return
+		}
+	}
+
+	if err := storage.DB.Model(department).Updates(map[string]interface{}{
+		"name":      department.Name,
+		"head_id":   department.HeadID,
+		"parent_id": department.ParentID,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении отдела"})
+		return
+	}
+
+	if err := storage.DB.Preload("Head").Preload("Teams").First(department, department.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении отдела"})
+		return
+	}
+	c.JSON(http.StatusOK, toDepartmentResponse(*department))
+}
+
+// DeleteDepartmentHandler удаляет пустой отдел
+// @Summary Удаление отдела
+// @Description Удаляет отдел без вложенных отделов и команд. Доступно владельцу организации и руководителю родительского отдела.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID отдела"
+// @Success 200 {object} response.SuccessResponse "Отдел удалён"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Отделы верхнего уровня может менять только владелец организации Code: NOT_ORGANIZATION_OWNER"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: В отделе есть вложенные отделы или команды Code: DEPARTMENT_NOT_EMPTY"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении отдела"
+// @Router /org/departments/{id} [delete]
+func DeleteDepartmentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var department models.Department
+	if err := storage.DB.First(&department, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отдел не найден", "code": "DEPARTMENT_NOT_FOUND"})
+		return
+	}
+	if !requireStructureAccess(c, user, department.OrganizationID, department.ParentID) {
+		return
+	}
+
+	var children, teams int64
+	storage.DB.Model(&models.Department{}).Where("parent_id = ?", department.ID).Count(&children)
+	storage.DB.Model(&models.Team{}).Where("department_id = ?", department.ID).Count(&teams)
+	if children > 0 || teams > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "В отделе есть вложенные отделы или команды", "code": "DEPARTMENT_NOT_EMPTY"})
+		return
+	}
+
+	if err := storage.DB.Delete(&department).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении отдела"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Отдел удалён"})
+}
+
+type AttachTeamInput struct {
+	TeamID uint `json:"team_id" binding:"required"`
+}
+
+// AttachTeamHandler добавляет команду в отдел
+// @Summary Добавление команды в отдел
+// @Description Делает команду частью отдела. Нужны права на отдел (руководитель отдела, одного из родительских или владелец организации) и права руководителя в самой команде. Команда может входить только в один отдел, прежняя привязка заменяется.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID отдела"
+// @Param input body AttachTeamInput true "Команда"
+// @Success 200 {object} response.SuccessResponse "Команда добавлена в отдел"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD, Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при добавлении команды"
+// @Router /org/departments/{id}/teams [post]
+func AttachTeamHandler(c *gin.Context) {
+	var input AttachTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отдел не найден", "code": "DEPARTMENT_NOT_FOUND"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	department, ok := access.RequireDepartment(c, user, uint(id))
+	if !ok {
+		return
+	}
+	if _, ok := access.Require(c, user, input.TeamID, access.ManageTeam); !ok {
+		return
+	}
+
+	if err := storage.DB.Model(&models.Team{}).Where("id = ?", input.TeamID).Update("department_id", department.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда добавлена в отдел"})
+}
+
+// DetachTeamHandler убирает команду из отдела
+// @Summary Исключение команды из отдела
+// @Description Убирает команду из отдела. Доступно руководителю команды и руководителю отдела.
+// @Tags org
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID отдела"
+// @Param team_id path string true "ID команды"
+// @Success 200 {object} response.SuccessResponse "Команда исключена из отдела"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Команда не входит в этот отдел Code: TEAM_NOT_IN_DEPARTMENT"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при исключении команды"
+// @Router /org/departments/{id}/teams/{team_id} [delete]
+func DetachTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var team models.Team
+	err := storage.DB.Where("id = ? AND department_id = ?", c.Param("team_id"), c.Param("id")).First(&team).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не входит в этот отдел", "code": "TEAM_NOT_IN_DEPARTMENT"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при исключении команды"})
+		return
+	}
+
+	membership, _ := access.GetMembership(user.ID, team.ID)
+	if membership == nil || !access.Can(membership.Role, access.ManageTeam) {
+		if _, ok := access.RequireDepartment(c, user, *team.DepartmentID); !ok {
+			return
+		}
+	}
+
+	if err := storage.DB.Model(&team).Update("department_id", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при исключении команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда исключена из отдела"})
+}
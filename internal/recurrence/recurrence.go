@@ -0,0 +1,255 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Частота повторения.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// maxPeriods ограничивает перебор периодов при поиске следующего повторения.
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule — правило повторения в духе RRULE (RFC 5545): FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (для WEEKLY),
+// BYMONTHDAY (для MONTHLY, -1 — последний день месяца), COUNT и UNTIL. Время повторений берётся из начала серии.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+	Count      int        // Сколько всего повторений, 0 — без ограничения
+	Until      *time.Time // Повторения не позже этого момента
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;BYDAY=MO,FR;INTERVAL=2. Префикс RRULE: допускается.
+func Parse(raw string) (*Rule, error) {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "RRULE:")
+	if raw == "" {
+		return nil, errors.New("правило повторения пустое")
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(raw, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("некорректная часть правила: %s", part)
+		}
+		key, value = strings.ToUpper(strings.TrimSpace(key)), strings.ToUpper(strings.TrimSpace(value))
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly {
+				return nil, fmt.Errorf("FREQ может быть DAILY, WEEKLY или MONTHLY")
+			}
+			r.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 365 {
+				return nil, fmt.Errorf("INTERVAL должен быть от 1 до 365")
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("некорректный день недели в BYDAY: %s", day)
+				}
+				if !slices.Contains(r.ByDay, wd) {
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n == 0 || n < -1 || n > 31 {
+				return nil, fmt.Errorf("BYMONTHDAY должен быть от 1 до 31 или -1")
+			}
+			r.ByMonthDay = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("COUNT должен быть положительным")
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("UNTIL указывается как 20250131T000000Z или 20250131")
+			}
+			r.Until = &t
+		default:
+			return nil, fmt.Errorf("параметр %s не поддерживается", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("в правиле нет FREQ")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return nil, errors.New("BYDAY поддерживается только для FREQ=WEEKLY")
+	}
+	if r.ByMonthDay != 0 && r.Freq != Monthly {
+		return nil, errors.New("BYMONTHDAY поддерживается только для FREQ=MONTHLY")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT и UNTIL нельзя указывать вместе")
+	}
+	slices.SortFunc(r.ByDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return t, err
+	}
+	// Дата без времени включает весь день
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// String возвращает правило в виде RRULE.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			for code, day := range weekdays {
+				if day == wd {
+					days = append(days, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое повторение серии, начатой в start, строго после after.
+// generated — сколько повторений уже создано, для COUNT. ok = false, если серия закончилась.
+func (r *Rule) Next(start, after time.Time, generated int) (next time.Time, ok bool) {
+	if r.Count > 0 && generated >= r.Count {
+		return time.Time{}, false
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.period(start, period) {
+			if candidate.Before(start) || !candidate.After(after) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// period возвращает повторения периода с номером n по возрастанию: n-й шаг по INTERVAL дней, недель или месяцев от start.
+func (r *Rule) period(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+	switch r.Freq {
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// Неделя начинается с понедельника
+		monday := start.AddDate(0, 0, -mondayIndex(start.Weekday())+7*step)
+		times := make([]time.Time, 0, len(days))
+		for _, wd := range days {
+			times = append(times, monday.AddDate(0, 0, mondayIndex(wd)))
+		}
+		return times
+	case Monthly:
+		day := r.ByMonthDay
+		if day == 0 {
+			day = start.Day()
+		}
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		if day == -1 || day > last {
+			day = last
+		}
+		return []time.Time{first.AddDate(0, 0, day-1)}
+	default:
+		return []time.Time{start.AddDate(0, 0, step)}
+	}
+}
+
+func mondayIndex(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+var weekdayTitles = map[time.Weekday]string{
+	time.Monday: "пн", time.Tuesday: "вт", time.Wednesday: "ср", time.Thursday: "чт",
+	time.Friday: "пт", time.Saturday: "сб", time.Sunday: "вс",
+}
+
+// Title описывает правило для уведомлений, например «каждые 2 недели: пн, пт».
+func (r *Rule) Title() string {
+	var title string
+	switch r.Freq {
+	case Weekly:
+		title = "каждую неделю"
+		if r.Interval > 1 {
+			title = fmt.Sprintf("каждые %d нед.", r.Interval)
+		}
+		if len(r.ByDay) > 0 {
+			days := make([]string, 0, len(r.ByDay))
+			for _, wd := range r.ByDay {
+				days = append(days, weekdayTitles[wd])
+			}
+			title += ": " + strings.Join(days, ", ")
+		}
+	case Monthly:
+		title = "каждый месяц"
+		if r.Interval > 1 {
+			title = fmt.Sprintf("каждые %d мес.", r.Interval)
+		}
+		if r.ByMonthDay == -1 {
+			title += ", в последний день"
+		} else if r.ByMonthDay > 0 {
+			title += fmt.Sprintf(", %d числа", r.ByMonthDay)
+		}
+	default:
+		title = "каждый день"
+		if r.Interval > 1 {
+			title = fmt.Sprintf("каждые %d дн.", r.Interval)
+		}
+	}
+	if r.Count > 0 {
+		title += fmt.Sprintf(", всего %d", r.Count)
+	}
+	if r.Until != nil {
+		title += ", до " + r.Until.Format("02.01.2006")
+	}
+	return title
+}
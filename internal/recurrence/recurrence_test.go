@@ -0,0 +1,126 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func at(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		start     time.Time
+		after     time.Time
+		generated int
+		want      time.Time
+		ok        bool
+	}{
+		// Среда 15.01.2025: понедельник первой недели раньше начала серии и пропускается
+		{"BYDAY до начала в первой неделе", "FREQ=WEEKLY;BYDAY=MO,FR", at(2025, 1, 15, 10), at(2025, 1, 15, 9), 0, at(2025, 1, 17, 10), true},
+		{"BYDAY после первой недели", "FREQ=WEEKLY;BYDAY=MO,FR", at(2025, 1, 15, 10), at(2025, 1, 17, 10), 1, at(2025, 1, 20, 10), true},
+		{"первое повторение в день начала", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", at(2025, 1, 15, 10), at(2025, 1, 15, 9), 0, at(2025, 1, 15, 10), true},
+		// Шаг INTERVAL отсчитывается от понедельника недели начала, а не от самого начала
+		{"INTERVAL=2 от понедельника недели", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", at(2025, 1, 15, 10), at(2025, 1, 15, 10), 1, at(2025, 1, 27, 10), true},
+		{"INTERVAL=2 вторая дата недели", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", at(2025, 1, 15, 10), at(2025, 1, 27, 10), 2, at(2025, 1, 29, 10), true},
+		{"INTERVAL=2 без BYDAY", "FREQ=WEEKLY;INTERVAL=2", at(2025, 1, 15, 10), at(2025, 1, 15, 10), 1, at(2025, 1, 29, 10), true},
+		{"BYMONTHDAY=31 в феврале", "FREQ=MONTHLY;BYMONTHDAY=31", at(2025, 1, 31, 9), at(2025, 1, 31, 9), 1, at(2025, 2, 28, 9), true},
+		{"BYMONTHDAY=31 в високосном феврале", "FREQ=MONTHLY;BYMONTHDAY=31", at(2024, 1, 31, 9), at(2024, 1, 31, 9), 1, at(2024, 2, 29, 9), true},
+		{"BYMONTHDAY=31 после февраля", "FREQ=MONTHLY;BYMONTHDAY=31", at(2025, 1, 31, 9), at(2025, 2, 28, 9), 2, at(2025, 3, 31, 9), true},
+		{"BYMONTHDAY=-1 в первом месяце", "FREQ=MONTHLY;BYMONTHDAY=-1", at(2025, 1, 10, 9), at(2025, 1, 10, 8), 0, at(2025, 1, 31, 9), true},
+		{"BYMONTHDAY=-1 в феврале", "FREQ=MONTHLY;BYMONTHDAY=-1", at(2025, 1, 10, 9), at(2025, 1, 31, 9), 1, at(2025, 2, 28, 9), true},
+		{"день начала 31 без BYMONTHDAY", "FREQ=MONTHLY", at(2025, 1, 31, 9), at(2025, 1, 31, 9), 1, at(2025, 2, 28, 9), true},
+		{"COUNT не исчерпан", "FREQ=DAILY;COUNT=3", at(2025, 1, 1, 9), at(2025, 1, 2, 9), 2, at(2025, 1, 3, 9), true},
+		{"COUNT исчерпан", "FREQ=DAILY;COUNT=3", at(2025, 1, 1, 9), at(2025, 1, 3, 9), 3, time.Time{}, false},
+		// UNTIL без времени включает весь день
+		{"UNTIL датой в последний день", "FREQ=DAILY;UNTIL=20250120", at(2025, 1, 18, 18), at(2025, 1, 19, 18), 2, at(2025, 1, 20, 18), true},
+		{"UNTIL датой после последнего дня", "FREQ=DAILY;UNTIL=20250120", at(2025, 1, 18, 18), at(2025, 1, 20, 18), 3, time.Time{}, false},
+		{"UNTIL со временем", "FREQ=DAILY;UNTIL=20250120T120000Z", at(2025, 1, 18, 18), at(2025, 1, 19, 18), 2, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got, ok := r.Next(tt.start, tt.after, tt.generated)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseUntilDate(t *testing.T) {
+	r, err := Parse("FREQ=DAILY;UNTIL=20250120")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 1, 20, 23, 59, 59, 0, time.UTC); !r.Until.Equal(want) {
+		t.Errorf("Until = %v; want %v", r.Until, want)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=fr,mo;interval=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"FREQ=WEEKLY;BYDAY=SU,MO,SU", "FREQ=WEEKLY;BYDAY=MO,SU"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=5", "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=5"},
+		{"FREQ=DAILY;INTERVAL=1;UNTIL=20250120", "FREQ=DAILY;UNTIL=20250120T235959Z"},
+		{"FREQ=MONTHLY;INTERVAL=3;UNTIL=20251231T090000Z", "FREQ=MONTHLY;INTERVAL=3;UNTIL=20251231T090000Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			r, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.raw, err)
+			}
+			got := r.String()
+			if got != tt.want {
+				t.Errorf("String() = %q; want %q", got, tt.want)
+			}
+			again, err := Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", got, err)
+			}
+			if again.String() != got {
+				t.Errorf("String() после повторного разбора = %q; want %q", again.String(), got)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=-2",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250120",
+		"FREQ=DAILY;UNTIL=2025-01-20",
+		"FREQ=DAILY;BYHOUR=9",
+	}
+
+	for _, raw := range tests {
+		t.Run(raw, func(t *testing.T) {
+			if _, err := Parse(raw); err == nil {
+				t.Errorf("Parse(%q) без ошибки", raw)
+			}
+		})
+	}
+}
@@ -1,61 +1,442 @@
-package response
-
-import "time"
-
-type SuccessResponse struct {
-	Message string `json:"message"`
-}
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-type ErrorCodeResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code"`
-}
-
-type TeamResponse struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	InviteLink  string `json:"invitelink"`
-}
-
-type UserResponse struct {
-	TelegramID string `json:"telegram_id"`
-	Name       string `json:"name"`
-}
-
-type UserInfoResponse struct {
-	Name     string `json:"name"`
-	Role     string `json:"role"`
-	TeamName string `json:"team_name"`
-}
-
-type MeetingResponse struct {
-	ID             uint      `json:"id"`
-	Title          string    `json:"title"`
-	MeetingType    string    `json:"meeting_type"`
-	Date           time.Time `json:"date"`
-	StartTime      time.Time `json:"start_time"`
-	EndTime        time.Time `json:"end_time"`
-	ConferenceLink string    `json:"conference_link"`
-	Room           string    `json:"room"`
-	TeamID         uint      `json:"team_id"`
-	CreatedBy      uint      `json:"created_by"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-}
-
-type TaskResponse struct {
-	ID          uint      `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline"`
-	Status      string    `json:"status"`
-	IsTeam      bool      `json:"is_team"`
-	AssignedTo  *string   `json:"assigned_to"`
-	CreatedBy   uint      `json:"created_by"`
-	TeamID      uint      `json:"team_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
+package response
+
+import "time"
+
+type SuccessResponse struct {
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+type ErrorCodeResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+type TeamResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InviteLink  string `json:"invitelink"`
+}
+
+type UserResponse struct {
+	TelegramID string `json:"telegram_id"`
+	Name       string `json:"name"`
+}
+
+type MyTeamResponse struct {
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Role             string     `json:"role"`              // Роль пользователя в команде
+	IsActive         bool       `json:"is_active"`         // Команда выбрана активной
+	RequiresApproval bool       `json:"requires_approval"` // Вступление требует одобрения руководителя
+	ArchivedAt       *time.Time `json:"archived_at"`       // Команда в архиве, если задано
+}
+
+// TeamPurgeResponse — количество записей, удаляемых вместе с командой.
+type TeamPurgeResponse struct {
+	TeamID             uint   `json:"team_id"`
+	TeamName           string `json:"team_name"`
+	Tasks              int64  `json:"tasks"`
+	Meetings           int64  `json:"meetings"`
+	InviteLinks        int64  `json:"invite_links"`
+	InviteUses         int64  `json:"invite_uses"`
+	Members            int64  `json:"members"`
+	JoinRequests       int64  `json:"join_requests"`
+	OwnershipTransfers int64  `json:"ownership_transfers"`
+}
+
+type InviteResponse struct {
+	Code      string     `json:"code"`
+	URL       string     `json:"url"`
+	Role      string     `json:"role"`
+	MaxUses   int        `json:"max_uses"` // 0 — без ограничения
+	Uses      int        `json:"uses"`
+	Active    bool       `json:"active"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type InviteUseResponse struct {
+	TelegramID string    `json:"telegram_id"`
+	Name       string    `json:"name"`
+	Role       string    `json:"role"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+type JoinRequestResponse struct {
+	ID                  uint       `json:"id"`
+	TeamID              uint       `json:"team_id"`
+	TelegramID          string     `json:"telegram_id"`
+	Name                string     `json:"name"`
+	Role                string     `json:"role"`
+	Status              string     `json:"status"` // pending, approved или rejected
+	CreatedAt           time.Time  `json:"created_at"`
+	DecidedAt           *time.Time `json:"decided_at"`
+	DecidedByTelegramID string     `json:"decided_by_telegram_id"`
+}
+
+type OrganizationResponse struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	OwnerTelegramID string    `json:"owner_telegram_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// OrganizationTreeResponse — организация со структурой отделов и команд.
+type OrganizationTreeResponse struct {
+	OrganizationResponse
+	Departments []DepartmentResponse `json:"departments"` // Отделы верхнего уровня
+}
+
+type DepartmentResponse struct {
+	ID             uint                     `json:"id"`
+	OrganizationID uint                     `json:"organization_id"`
+	ParentID       *uint                    `json:"parent_id"`
+	Name           string                   `json:"name"`
+	HeadTelegramID string                   `json:"head_telegram_id"`
+	HeadName       string                   `json:"head_name"`
+	Teams          []DepartmentTeamResponse `json:"teams"`
+	Children       []DepartmentResponse     `json:"children"` // Вложенные отделы
+}
+
+type DepartmentTeamResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+// WorkflowResponse — процесс работы над задачами команды.
+type WorkflowResponse struct {
+	TeamID      uint                         `json:"team_id"`
+	Custom      bool                         `json:"custom"` // false — команда использует стандартный процесс
+	Statuses    []WorkflowStatusResponse     `json:"statuses"`
+	Transitions []WorkflowTransitionResponse `json:"transitions"`
+}
+
+type WorkflowStatusResponse struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Terminal bool   `json:"terminal"` // Работа по задаче завершена
+	Review   bool   `json:"review"`   // Работа ждёт проверки автором задачи
+}
+
+type WorkflowTransitionResponse struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles"` // Пусто — все, кто может работать с задачами
+}
+
+type MemberResponse struct {
+	TelegramID string `json:"telegram_id"`
+	Name       string `json:"name"`
+	Role       string `json:"role"` // owner, manager, member или observer
+}
+
+type OwnershipTransferResponse struct {
+	ID             uint      `json:"id"`
+	TeamID         uint      `json:"team_id"`
+	TeamName       string    `json:"team_name"`
+	FromTelegramID string    `json:"from_telegram_id"`
+	ToTelegramID   string    `json:"to_telegram_id"`
+	Status         string    `json:"status"` // pending, accepted, declined или cancelled
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type UserInfoResponse struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	TeamName string `json:"team_name"`
+}
+
+type MeetingResponse struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	MeetingType    string    `json:"meeting_type"`
+	Date           time.Time `json:"date"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	ConferenceLink string    `json:"conference_link"`
+	Room           string    `json:"room"`
+	TeamID         uint      `json:"team_id"`
+	CreatedBy      uint      `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type TaskResponse struct {
+	ID             uint                 `json:"id"`
+	Title          string               `json:"title"`
+	Description    string               `json:"description"`
+	Deadline       time.Time            `json:"deadline"`
+	Status         string               `json:"status"`
+	IsTeam         bool                 `json:"is_team"`
+	AssignedTo     *string              `json:"assigned_to"`
+	CreatedBy      uint                 `json:"created_by"`
+	TeamID         uint                 `json:"team_id"`
+	DepartmentID   *uint                `json:"department_id"`   // Отдел, если задача поставлена руководителем отдела нескольким командам
+	CompletionRule string               `json:"completion_rule"` // all, any или quorum
+	QuorumPercent  int                  `json:"quorum_percent"`
+	Progress       int                  `json:"progress"`      // Процент выполнения: для командной задачи — доля участников, выполнивших свою часть
+	ParentID       *uint                `json:"parent_id"`     // Родительская задача, если это подзадача
+	SubtaskCount   int                  `json:"subtask_count"` // Количество подзадач; прогресс задачи с подзадачами — средний прогресс подзадач
+	SubtasksDone   int                  `json:"subtasks_done"` // Выполненные подзадачи
+	BlockedBy      int                  `json:"blocked_by"`    // Сколько незавершённых задач блокируют эту задачу
+	Priority       string               `json:"priority"`      // low, medium, high или urgent
+	StoryPoints    *int                 `json:"story_points"`
+	EstimateHours  *float64             `json:"estimate_hours"`
+	Labels         []LabelResponse      `json:"labels"`
+	SeriesID       *uint                `json:"series_id"` // Серия повторяющихся задач, по которой создана задача
+	ChecklistTotal int                  `json:"checklist_total"`
+	ChecklistDone  int                  `json:"checklist_done"`
+	CommentCount   int                  `json:"comment_count"`
+	Attachments    []AttachmentResponse `json:"attachments"` // Файлы задачи, кроме приложенных к комментариям
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// TaskPageResponse — страница списка задач.
+type TaskPageResponse struct {
+	Items      []TaskResponse `json:"items"`
+	Total      int64          `json:"total"`       // Сколько задач подходит под фильтры
+	NextCursor string         `json:"next_cursor"` // Пусто, если это последняя страница
+}
+
+// SearchResultResponse — найденная задача, комментарий или встреча.
+type SearchResultResponse struct {
+	Type      string    `json:"type"` // task, comment или meeting
+	ID        uint      `json:"id"`
+	TaskID    *uint     `json:"task_id"` // Задача, к которой относится комментарий
+	TeamID    uint      `json:"team_id"`
+	Title     string    `json:"title"`   // Заголовок задачи или название встречи
+	Snippet   string    `json:"snippet"` // Фрагмент текста, найденные слова выделены <b></b>
+	Rank      float64   `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskSeriesResponse — серия повторяющихся задач.
+type TaskSeriesResponse struct {
+	ID             uint            `json:"id"`
+	TeamID         uint            `json:"team_id"`
+	CreatedBy      uint            `json:"created_by"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	IsTeam         bool            `json:"is_team"`
+	AssignedTo     *string         `json:"assigned_to"`
+	CompletionRule string          `json:"completion_rule"`
+	QuorumPercent  int             `json:"quorum_percent"`
+	Priority       string          `json:"priority"`
+	StoryPoints    *int            `json:"story_points"`
+	EstimateHours  *float64        `json:"estimate_hours"`
+	Labels         []LabelResponse `json:"labels"`
+	Recurrence     string          `json:"recurrence"` // Правило повторения в формате RRULE
+	StartAt        time.Time       `json:"start_at"`   // Срок первого повторения
+	Generated      int             `json:"generated"`  // Сколько задач создано
+	LastAt         *time.Time      `json:"last_at"`    // Срок последней созданной задачи
+	NextAt         *time.Time      `json:"next_at"`    // Срок следующей задачи; null, если серия закончилась или остановлена
+	StoppedAt      *time.Time      `json:"stopped_at"`
+	LastTaskID     *uint           `json:"last_task_id"` // Последняя созданная задача серии
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// TaskTemplateResponse — шаблон задачи команды.
+type TaskTemplateResponse struct {
+	ID             uint                       `json:"id"`
+	TeamID         uint                       `json:"team_id"`
+	Name           string                     `json:"name"`
+	Title          string                     `json:"title"`
+	Description    string                     `json:"description"`
+	Deadline       string                     `json:"deadline"` // Срок относительно создания задачи, например "+3 days"
+	IsTeam         bool                       `json:"is_team"`
+	Assignees      []TemplateAssigneeResponse `json:"assignees"` // Исполнители персональных задач по умолчанию
+	CompletionRule string                     `json:"completion_rule"`
+	QuorumPercent  int                        `json:"quorum_percent"`
+	Priority       string                     `json:"priority"`
+	StoryPoints    *int                       `json:"story_points"`
+	EstimateHours  *float64                   `json:"estimate_hours"`
+	Labels         []LabelResponse            `json:"labels"`
+	Checklist      []string                   `json:"checklist"` // Пункты чек-листа по умолчанию
+	Variables      []string                   `json:"variables"` // Переменные {{name}}, которые нужно передать при создании задач
+	CreatedBy      uint                       `json:"created_by"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+}
+
+// TemplateAssigneeResponse — исполнитель по умолчанию из шаблона задачи.
+type TemplateAssigneeResponse struct {
+	TelegramID string `json:"telegram_id"`
+	Name       string `json:"name"`
+	InTeam     bool   `json:"in_team"` // false — пользователь вышел из команды, задачи ему по шаблону не создаются
+}
+
+// LabelResponse — метка команды.
+type LabelResponse struct {
+	ID        uint   `json:"id"`
+	TeamID    uint   `json:"team_id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"` // #RRGGBB
+	TaskCount int    `json:"task_count,omitempty"`
+}
+
+// TaskAssignmentResponse — статус командной задачи у участника.
+type TaskAssignmentResponse struct {
+	TelegramID     string     `json:"telegram_id"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	CompletionText string     `json:"completion_text"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TaskReviewResponse — работа, отправленная на проверку, и решение автора задачи.
+type TaskReviewResponse struct {
+	ID                 uint                 `json:"id"`
+	TaskID             uint                 `json:"task_id"`
+	TaskTitle          string               `json:"task_title"`
+	TelegramID         string               `json:"telegram_id"` // Исполнитель
+	Name               string               `json:"name"`
+	CompletionText     string               `json:"completion_text"`
+	Attachment         string               `json:"attachment"`
+	Status             string               `json:"status"` // pending, approved или rejected
+	ReviewerTelegramID string               `json:"reviewer_telegram_id"`
+	Comment            string               `json:"comment"`
+	Attachments        []AttachmentResponse `json:"attachments"` // Файлы к отчёту исполнителя
+	CreatedAt          time.Time            `json:"created_at"`
+	DecidedAt          *time.Time           `json:"decided_at"`
+}
+
+// TaskEventResponse — запись истории задачи.
+type TaskEventResponse struct {
+	ID              uint                 `json:"id"`
+	Type            string               `json:"type"` // created, updated, reassigned, status_changed, review_approved, review_rejected, deleted
+	ActorTelegramID string               `json:"actor_telegram_id"`
+	ActorName       string               `json:"actor_name"`
+	TelegramID      string               `json:"telegram_id"` // Исполнитель, чей статус изменился
+	Name            string               `json:"name"`
+	FromStatus      string               `json:"from_status"`
+	ToStatus        string               `json:"to_status"`
+	TaskStatus      string               `json:"task_status"` // Общий статус задачи после события
+	CompletionText  string               `json:"completion_text"`
+	Attachment      string               `json:"attachment"`
+	Comment         string               `json:"comment"`
+	Changes         []TaskChangeResponse `json:"changes"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// TaskCommentResponse — комментарий к задаче с ответами.
+type TaskCommentResponse struct {
+	ID               uint                  `json:"id"`
+	ParentID         *uint                 `json:"parent_id"`
+	AuthorTelegramID string                `json:"author_telegram_id"`
+	AuthorName       string                `json:"author_name"`
+	Text             string                `json:"text"`
+	Mentions         []string              `json:"mentions"` // Telegram ID упомянутых участников
+	Deleted          bool                  `json:"deleted"`  // Комментарий удалён, но на него есть ответы
+	EditedAt         *time.Time            `json:"edited_at"`
+	Attachments      []AttachmentResponse  `json:"attachments"`
+	CreatedAt        time.Time             `json:"created_at"`
+	Replies          []TaskCommentResponse `json:"replies"`
+}
+
+// TaskDependenciesResponse — зависимости задачи.
+type TaskDependenciesResponse struct {
+	BlockedBy []TaskDependencyResponse `json:"blocked_by"` // Задачи, без завершения которых эту нельзя начать
+	Blocks    []TaskDependencyResponse `json:"blocks"`     // Задачи, которые ждут эту
+}
+
+// TaskDependencyResponse — связанная задача в списке зависимостей.
+type TaskDependencyResponse struct {
+	TaskID   uint      `json:"task_id"`
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Done     bool      `json:"done"` // Задача в завершающем статусе
+	TeamID   uint      `json:"team_id"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// ChecklistItemResponse — пункт чек-листа задачи.
+type ChecklistItemResponse struct {
+	ID               uint       `json:"id"`
+	Text             string     `json:"text"`
+	Position         int        `json:"position"`
+	Done             bool       `json:"done"`
+	DoneByTelegramID string     `json:"done_by_telegram_id"`
+	DoneByName       string     `json:"done_by_name"`
+	DoneAt           *time.Time `json:"done_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// AttachmentResponse — описание вложения. Файл скачивается по URL с теми же заголовками авторизации.
+type AttachmentResponse struct {
+	ID                 uint      `json:"id"`
+	TaskID             uint      `json:"task_id"`
+	CommentID          *uint     `json:"comment_id"`
+	ReviewID           *uint     `json:"review_id"`
+	FileName           string    `json:"file_name"`
+	ContentType        string    `json:"content_type"`
+	Size               int64     `json:"size"` // Размер в байтах
+	UploaderTelegramID string    `json:"uploader_telegram_id"`
+	UploaderName       string    `json:"uploader_name"`
+	URL                string    `json:"url"` // Ссылка на скачивание, например /tasks/12/attachments/5
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// TaskUpdateResponse — задача после изменения и список изменённых полей.
+type TaskUpdateResponse struct {
+	Task    TaskResponse         `json:"task"`
+	Changes []TaskChangeResponse `json:"changes"`
+}
+
+type TaskChangeResponse struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// BulkTaskResponse — результат массовой операции над задачами.
+type BulkTaskResponse struct {
+	Action  string         `json:"action"`
+	Tasks   []TaskResponse `json:"tasks"`   // Задачи после изменения; для delete — пустой список
+	Deleted []uint         `json:"deleted"` // ID удалённых задач
+}
+
+// BulkTaskErrorResponse — массовая операция отменена целиком, потому что невозможна для части задач.
+type BulkTaskErrorResponse struct {
+	Error    string                    `json:"error"`
+	Code     string                    `json:"code"`
+	Failures []BulkTaskFailureResponse `json:"failures"`
+}
+
+// BulkTaskFailureResponse — почему операция невозможна для задачи.
+type BulkTaskFailureResponse struct {
+	TaskID uint   `json:"task_id"`
+	Status int    `json:"status"` // HTTP-статус, который вернул бы запрос для одной задачи
+	Error  string `json:"error"`
+	Code   string `json:"code,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	Scopes         []string   `json:"scopes"`
+	CanActOnBehalf bool       `json:"can_act_on_behalf"`
+	CreatedBy      uint       `json:"created_by"`
+	CreatedByKey   *uint      `json:"created_by_key"` // API-ключ, которым создан ключ
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// APIKeySecretResponse возвращается только при создании и ротации — это единственный момент, когда ключ виден целиком.
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,148 @@
+package search
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// Типы результатов поиска.
+const (
+	TypeTask    = "task"
+	TypeComment = "comment"
+	TypeMeeting = "meeting"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// headlineOptions — параметры ts_headline: найденные слова выделяются тегами <b></b>.
+const headlineOptions = `StartSel=<b>, StopSel=</b>, MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`
+
+// queries — выборка для каждого типа результата. q.query — поисковый запрос на русском и английском,
+// @teams — команды пользователя; задачи, поставленные самим пользователем (@user), находятся и вне его команд.
+var queries = map[string]string{
+	TypeTask: `SELECT 'task' AS type, t.id, t.id AS task_id, t.team_id, t.title,
+			ts_headline('russian', t.title || E'\n' || coalesce(t.description, ''), q.query, @options) AS snippet,
+			ts_rank(t.search_vector, q.query) AS rank, t.created_at
+		FROM tasks t, q
+		WHERE t.deleted_at IS NULL AND t.search_vector @@ q.query
+			AND (t.team_id IN @teams OR t.created_by = @user)`,
+	TypeComment: `SELECT 'comment' AS type, c.id, c.task_id, t.team_id, t.title,
+			ts_headline('russian', c.text, q.query, @options) AS snippet,
+			ts_rank(c.search_vector, q.query) AS rank, c.created_at
+		FROM task_comments c JOIN tasks t ON t.id = c.task_id AND t.deleted_at IS NULL, q
+		WHERE c.deleted_at IS NULL AND c.search_vector @@ q.query
+			AND (t.team_id IN @teams OR t.created_by = @user)`,
+	TypeMeeting: `SELECT 'meeting' AS type, m.id, NULL::bigint AS task_id, m.team_id, m.title,
+			ts_headline('russian', m.title, q.query, @options) AS snippet,
+			ts_rank(m.search_vector, q.query) AS rank, m.created_at
+		FROM meetings m, q
+		WHERE m.deleted_at IS NULL AND m.search_vector @@ q.query AND m.team_id IN @teams`,
+}
+
+var types = []string{TypeTask, TypeComment, TypeMeeting}
+
+// SearchHandler ищет по задачам, комментариям и встречам
+// @Summary Полнотекстовый поиск
+// @Description Ищет по заголовкам и описаниям задач, текстам комментариев и названиям встреч в командах пользователя. Запрос понимает словоформы на русском и английском, "точные фразы", OR и -исключения. Результаты упорядочены по релевантности, в snippet найденные слова выделены тегами <b></b>.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param q query string true "Поисковый запрос"
+// @Param types query string false "Типы через запятую: task, comment, meeting (по умолчанию все)"
+// @Param team_id query int false "Искать только в этой команде"
+// @Param limit query int false "Количество результатов, от 1 до 100 (по умолчанию 20)"
+// @Success 200 {array} response.SearchResultResponse "Результаты поиска"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Запрос должен содержать хотя бы 2 символа Code: QUERY_TOO_SHORT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Некорректный тип: task1 Code: INVALID_SEARCH_TYPE, Error: limit должен быть от 1 до 100 Code: INVALID_LIMIT"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка поиска"
+// @Router /search [get]
+func SearchHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	q := strings.TrimSpace(c.Query("q"))
+	if utf8.RuneCountInString(q) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Запрос должен содержать хотя бы 2 символа", "code": "QUERY_TOO_SHORT"})
+		return
+	}
+
+	wanted := types
+	if raw := c.Query("types"); raw != "" {
+		wanted = nil
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(types, t) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный тип: " + t, "code": "INVALID_SEARCH_TYPE"})
+				return
+			}
+			if !slices.Contains(wanted, t) {
+				wanted = append(wanted, t)
+			}
+		}
+	}
+
+	limit := defaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit должен быть от 1 до " + strconv.Itoa(maxLimit), "code": "INVALID_LIMIT"})
+			return
+		}
+		limit = n
+	}
+
+	var teams []uint
+	userID := user.ID
+	if c.Query("team_id") != "" {
+		membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+		if !ok {
+			return
+		}
+		teams = []uint{membership.TeamID}
+		// В одной команде ищем только по ней, без задач, поставленных в других командах
+		userID = 0
+	} else if err := storage.DB.Model(&models.TeamMembership{}).Where("user_id = ?", user.ID).Pluck("team_id", &teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+
+	parts := make([]string, 0, len(wanted))
+	for _, t := range wanted {
+		parts = append(parts, queries[t])
+	}
+	sql := `WITH q AS (SELECT websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q) AS query) ` +
+		strings.Join(parts, " UNION ALL ") +
+		` ORDER BY rank DESC, created_at DESC LIMIT @limit`
+
+	results := []response.SearchResultResponse{}
+	if err := storage.DB.Raw(sql, map[string]interface{}{
+		"q":       q,
+		"teams":   teams,
+		"user":    userID,
+		"options": headlineOptions,
+		"limit":   limit,
+	}).Scan(&results).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка поиска"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// document — таблица, по которой ищет GET /search, и выражение её поискового вектора.
+type document struct {
+	table  string
+	vector string
+}
+
+// documents описывают поисковые векторы: текст разбирается и русской, и английской конфигурацией,
+// чтобы находились словоформы на обоих языках. Заголовок задачи весит больше описания.
+var documents = []document{
+	{
+		table: "tasks",
+		vector: `setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B')`,
+	},
+	{
+		table:  "task_comments",
+		vector: `to_tsvector('russian', coalesce(text, '')) || to_tsvector('english', coalesce(text, ''))`,
+	},
+	{
+		table:  "meetings",
+		vector: `to_tsvector('russian', coalesce(title, '')) || to_tsvector('english', coalesce(title, ''))`,
+	},
+}
+
+// Migrate добавляет таблицам задач, комментариев и встреч вычисляемую колонку search_vector и GIN-индекс по ней.
+// PostgreSQL пересчитывает вектор сам при каждом изменении строки. Вызывается после AutoMigrate.
+func Migrate(db *gorm.DB) error {
+	for _, doc := range documents {
+		if err := db.Exec(fmt.Sprintf(
+			"ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED",
+			doc.table, doc.vector,
+		)).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)",
+			doc.table, doc.table,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package tasks
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Решения по работе, отправленной на проверку.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+var (
+	errWorkInReview = errors.New("работа на проверке")
+	errReviewStale  = errors.New("решение по работе уже принято")
+)
+
+// Правила, по которым командная задача считается выполненной.
+const (
+	CompletionAll    = "all"    // Все участники выполнили свою часть
+	CompletionAny    = "any"    // Достаточно одного участника
+	CompletionQuorum = "quorum" // Выполнили не меньше QuorumPercent участников
+)
+
+// createAssignments заводит статус командной задачи для каждого участника команды, который может работать с задачами.
+func createAssignments(tx *gorm.DB, task *models.Task, flow *workflow.Machine) error {
+	var memberships []models.TeamMembership
+	if err := tx.Where("team_id = ?", task.TeamID).Find(&memberships).Error; err != nil {
+		return err
+	}
+
+	var assignments []models.TaskAssignment
+	for _, m := range memberships {
+		if access.Can(m.Role, access.WorkOnTasks) {
+			assignments = append(assignments, models.TaskAssignment{TaskID: task.ID, UserID: m.UserID, Status: flow.Initial()})
+		}
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignments).Error
+}
+
+// currentAssignments возвращает статусы участников, которые всё ещё состоят в команде задачи.
+func currentAssignments(tx *gorm.DB, task *models.Task) ([]models.TaskAssignment, error) {
+	var assignments []models.TaskAssignment
+	err := tx.Preload("User").
+		Joins("JOIN team_memberships ON team_memberships.user_id = task_assignments.user_id AND team_memberships.team_id = ?", task.TeamID).
+		Where("task_assignments.task_id = ?", task.ID).
+		Order("task_assignments.created_at").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// ruleSatisfied проверяет правило выполнения командной задачи.
+func ruleSatisfied(task *models.Task, completed, total int) bool {
+	if total == 0 {
+		return false
+	}
+	switch task.CompletionRule {
+	case CompletionAny:
+		return completed > 0
+	case CompletionQuorum:
+		return completed*100 >= task.QuorumPercent*total
+	default:
+		return completed == total
+	}
+}
+
+// aggregateStatus вычисляет общий статус командной задачи по статусам участников:
+// завершающий, если выполнено правило, статус проверки, если чья-то работа ждёт решения,
+// иначе наименее продвинутый рабочий статус участников.
+func aggregateStatus(task *models.Task, assignments []models.TaskAssignment, flow *workflow.Machine) string {
+	completed, started := 0, 0
+	review, working := "", ""
+	for _, a := range assignments {
+		switch {
+		case flow.IsTerminal(a.Status):
+			completed++
+		case flow.IsReview(a.Status):
+			if review == "" {
+				review = a.Status
+			}
+		case a.Status != flow.Initial():
+			started++
+			if working == "" || flow.Position(a.Status) < flow.Position(working) {
+				working = a.Status
+			}
+		}
+	}
+
+	switch {
+	case ruleSatisfied(task, completed, len(assignments)):
+		return flow.Done()
+	case review != "":
+		return review
+	case working != "":
+		return working
+	case completed > 0:
+		return flow.Working()
+	default:
+		return flow.Initial()
+	}
+}
+
+// workStatus возвращает статус работы исполнителя: у персональной задачи — статус самой задачи, у командной — статус участника.
+func workStatus(tx *gorm.DB, task *models.Task, userID uint, flow *workflow.Machine) (string, error) {
+	if !task.IsTeam {
+		return task.Status, nil
+	}
+	var assignment models.TaskAssignment
+	err := tx.Where("task_id = ? AND user_id = ?", task.ID, userID).First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return flow.Initial(), nil
+	}
+	return assignment.Status, err
+}
+
+// setWorkStatus меняет статус работы исполнителя и пересчитывает общий статус командной задачи.
+// Допустимость перехода проверяется до вызова через workflow.Machine.Check.
+func setWorkStatus(tx *gorm.DB, task *models.Task, flow *workflow.Machine, userID uint, status, completionText string) error {
+	if !task.IsTeam {
+		task.Status = status
+		return tx.Save(task).Error
+	}
+
+	assignment := models.TaskAssignment{TaskID: task.ID, UserID: userID}
+	if err := tx.Where(&assignment).Attrs(models.TaskAssignment{Status: flow.Initial()}).FirstOrCreate(&assignment).Error; err != nil {
+		return err
+	}
+	assignment.Status = status
+	assignment.CompletionText = completionText
+	assignment.CompletedAt = nil
+	if flow.IsTerminal(status) {
+		now := time.Now()
+		assignment.CompletedAt = &now
+	}
+	if err := tx.Save(&assignment).Error; err != nil {
+		return err
+	}
+
+	assignments, err := currentAssignments(tx, task)
+	if err != nil {
+		return err
+	}
+	task.Status = aggregateStatus(task, assignments, flow)
+	return tx.Save(task).Error
+}
+
+type assignmentStats struct {
+	TaskID uint
+	TeamID uint
+	Status string
+	Count  int
+}
+
+// teamFlows загружает процессы команд; при ошибке для команды используется стандартный процесс.
+func teamFlows(tasks []models.Task) map[uint]*workflow.Machine {
+	flows := make(map[uint]*workflow.Machine)
+	for _, t := range tasks {
+		if _, ok := flows[t.TeamID]; ok {
+			continue
+		}
+		flow, err := workflow.ForTeam(storage.DB, t.TeamID)
+		if err != nil {
+			flow = workflow.Default()
+		}
+		flows[t.TeamID] = flow
+	}
+	return flows
+}
+
+// progressByTask возвращает процент выполнения задач: для командной — долю текущих участников
+// в завершающих статусах процесса команды, для персональной — 100, если задача завершена.
+func progressByTask(tasks []models.Task) map[uint]int {
+	flows := teamFlows(tasks)
+	progress := make(map[uint]int, len(tasks))
+
+	var ids []uint
+	for _, t := range tasks {
+		if t.IsTeam {
+			ids = append(ids, t.ID)
+		} else if flows[t.TeamID].IsTerminal(t.Status) {
+			progress[t.ID] = 100
+		}
+	}
+	if len(ids) == 0 {
+		return progress
+	}
+
+	var stats []assignmentStats
+	if err := storage.DB.Model(&models.TaskAssignment{}).
+		Select("task_assignments.task_id, tasks.team_id, task_assignments.status, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = task_assignments.task_id").
+		Joins("JOIN team_memberships ON team_memberships.user_id = task_assignments.user_id AND team_memberships.team_id = tasks.team_id").
+		Where("task_assignments.task_id IN ?", ids).
+		Group("task_assignments.task_id, tasks.team_id, task_assignments.status").
+		Scan(&stats).Error; err != nil {
+		return progress
+	}
+
+	total := make(map[uint]int)
+	completed := make(map[uint]int)
+	for _, s := range stats {
+		total[s.TaskID] += s.Count
+		if flows[s.TeamID].IsTerminal(s.Status) {
+			completed[s.TaskID] += s.Count
+		}
+	}
+	for id, n := range total {
+		progress[id] = completed[id] * 100 / n
+	}
+	return progress
+}
+
+// GetTaskAssignmentsHandler возвращает статусы командной задачи по участникам
+// @Summary Статусы участников командной задачи
+// @Description Возвращает статус выполнения командной задачи у каждого текущего участника команды. Доступно участникам команды и руководителю отдела, в который входит команда.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.TaskAssignmentResponse "Статусы участников"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Задача не командная Code: NOT_TEAM_TASK"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении статусов участников"
+// @Router /tasks/{id}/assignments [get]
+func GetTaskAssignmentsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	if !task.IsTeam {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Задача не командная", "code": "NOT_TEAM_TASK"})
+		return
+	}
+
+	assignments, err := currentAssignments(storage.DB, &task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении статусов участников"})
+		return
+	}
+
+	resp := make([]response.TaskAssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		resp = append(resp, response.TaskAssignmentResponse{
+			TelegramID:     a.User.TelegramID,
+			Name:           a.User.Name,
+			Status:         a.Status,
+			CompletionText: a.CompletionText,
+			CompletedAt:    a.CompletedAt,
+			UpdatedAt:      a.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
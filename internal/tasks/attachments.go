@@ -0,0 +1,445 @@
+package tasks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/blob"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errAttachmentNotFound возвращается, если среди переданных attachment_ids есть чужие, уже привязанные или несуществующие файлы.
+var errAttachmentNotFound = errors.New("attachment not found")
+
+// defaultAttachmentTypes — типы файлов, которые можно загружать, если ATTACHMENT_ALLOWED_TYPES не задан.
+// Документы Office (docx, xlsx, pptx) определяются как application/zip.
+var defaultAttachmentTypes = []string{
+	"image/jpeg", "image/png", "image/gif", "image/webp",
+	"application/pdf", "application/zip", "text/plain",
+}
+
+const defaultAttachmentSizeMB = 20
+
+// maxAttachmentSize возвращает наибольший размер файла в байтах из ATTACHMENT_MAX_SIZE_MB.
+func maxAttachmentSize() int64 {
+	if raw := os.Getenv("ATTACHMENT_MAX_SIZE_MB"); raw != "" {
+		if mb, err := strconv.ParseInt(raw, 10, 64); err == nil && mb > 0 {
+			return mb << 20
+		}
+	}
+	return defaultAttachmentSizeMB << 20
+}
+
+// allowedAttachmentTypes возвращает разрешённые MIME-типы из ATTACHMENT_ALLOWED_TYPES (через запятую).
+func allowedAttachmentTypes() []string {
+	raw := os.Getenv("ATTACHMENT_ALLOWED_TYPES")
+	if raw == "" {
+		return defaultAttachmentTypes
+	}
+	var types []string
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(strings.ToLower(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// attachmentTypeAllowed проверяет тип файла по списку; запись вида image/* разрешает все картинки.
+func attachmentTypeAllowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range allowedAttachmentTypes() {
+		if allowed == mediaType || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// newStorageKey возвращает случайный ключ объекта в хранилище, не зависящий от имени файла.
+func newStorageKey(taskID uint) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(buf)), nil
+}
+
+// claimAttachments привязывает загруженные пользователем файлы задачи к комментарию или работе на проверке.
+// column — comment_id или review_id. Привязать можно только свои ещё не привязанные файлы.
+func claimAttachments(tx *gorm.DB, taskID, userID uint, ids []uint, column string, value uint) error {
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(ids) == 0 {
+		return nil
+	}
+	result := tx.Model(&models.Attachment{}).
+		Where("id IN ? AND task_id = ? AND uploader_id = ? AND comment_id IS NULL AND review_id IS NULL", ids, taskID, userID).
+		Update(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(ids)) {
+		return errAttachmentNotFound
+	}
+	return nil
+}
+
+// removeBlobs удаляет файлы из хранилища после удаления их описаний из базы. Ошибки только логируются:
+// оставшийся в хранилище файл недоступен через API.
+func removeBlobs(keys []string) {
+	for _, key := range keys {
+		if err := blob.Files.Delete(context.Background(), key); err != nil {
+			fmt.Printf("Ошибка удаления файла %s из хранилища: %v\n", key, err)
+		}
+	}
+}
+
+// deleteAttachments удаляет описания файлов по условию и возвращает их ключи в хранилище.
+func deleteAttachments(tx *gorm.DB, query string, args ...interface{}) ([]string, error) {
+	var keys []string
+	if err := tx.Model(&models.Attachment{}).Where(query, args...).Pluck("storage_key", &keys).Error; err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if err := tx.Where(query, args...).Delete(&models.Attachment{}).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func toAttachmentResponse(a models.Attachment) response.AttachmentResponse {
+	return response.AttachmentResponse{
+		ID:                 a.ID,
+		TaskID:             a.TaskID,
+		CommentID:          a.CommentID,
+		ReviewID:           a.ReviewID,
+		FileName:           a.FileName,
+		ContentType:        a.ContentType,
+		Size:               a.Size,
+		UploaderTelegramID: a.Uploader.TelegramID,
+		UploaderName:       a.Uploader.Name,
+		URL:                fmt.Sprintf("/tasks/%d/attachments/%d", a.TaskID, a.ID),
+		CreatedAt:          a.CreatedAt,
+	}
+}
+
+func toAttachmentResponses(attachments []models.Attachment) []response.AttachmentResponse {
+	resp := make([]response.AttachmentResponse, 0, len(attachments))
+	for _, a := range attachments {
+		resp = append(resp, toAttachmentResponse(a))
+	}
+	return resp
+}
+
+// attachmentsByTask возвращает файлы задач, кроме приложенных к комментариям.
+func attachmentsByTask(tasks []models.Task) map[uint][]response.AttachmentResponse {
+	files := make(map[uint][]response.AttachmentResponse, len(tasks))
+	if len(tasks) == 0 {
+		return files
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var attachments []models.Attachment
+	if err := storage.DB.Preload("Uploader").
+		Where("task_id IN ? AND comment_id IS NULL", ids).
+		Order("created_at, id").
+		Find(&attachments).Error; err != nil {
+		return files
+	}
+	for _, a := range attachments {
+		files[a.TaskID] = append(files[a.TaskID], toAttachmentResponse(a))
+	}
+	return files
+}
+
+// attachmentNames перечисляет имена файлов для уведомления.
+func attachmentNames(attachments []models.Attachment) string {
+	names := make([]string, 0, len(attachments))
+	for _, a := range attachments {
+		names = append(names, a.FileName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// UploadTaskAttachmentHandler загружает файл к задаче
+// @Summary Загрузка файла к задаче
+// @Description Загружает файл (multipart/form-data, поле file) к задаче. Тип файла определяется по содержимому и должен входить в ATTACHMENT_ALLOWED_TYPES (по умолчанию картинки, PDF, текст и zip, в том числе документы Office), размер — не больше ATTACHMENT_MAX_SIZE_MB (по умолчанию 20 МБ).
+// @Description Загруженный файл виден в задаче; чтобы приложить его к комментарию или к работе на проверку, передайте его ID в attachment_ids. Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept mpfd
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param file formData file true "Файл"
+// @Success 201 {object} response.AttachmentResponse "Файл загружен"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Файл не передан Code: FILE_REQUIRED"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 413 {object} response.ErrorCodeResponse "Error: Файл больше 20 МБ Code: FILE_TOO_LARGE"
+// @Failure 415 {object} response.ErrorCodeResponse "Error: Файлы типа application/x-msdownload загружать нельзя Code: UNSUPPORTED_FILE_TYPE"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении файла"
+// @Router /tasks/{id}/attachments [post]
+func UploadTaskAttachmentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, true) {
+		return
+	}
+
+	maxSize := maxAttachmentSize()
+	tooLarge := gin.H{"error": fmt.Sprintf("Файл больше %d МБ", maxSize>>20), "code": "FILE_TOO_LARGE"}
+	// Запас на заголовки multipart сверх размера самого файла.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл не передан", "code": "FILE_REQUIRED"})
+		return
+	}
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+	defer file.Close()
+
+	// Тип определяется по первым 512 байтам содержимого, а не по имени или заголовку клиента.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !attachmentTypeAllowed(contentType) {
+		mediaType, _, _ := strings.Cut(contentType, ";")
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("Файлы типа %s загружать нельзя", mediaType), "code": "UNSUPPORTED_FILE_TYPE"})
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+
+	key, err := newStorageKey(task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+	if err := blob.Files.Put(c.Request.Context(), key, file, header.Size, contentType); err != nil {
+		fmt.Printf("Ошибка записи файла %s в хранилище: %v\n", key, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+
+	name := filepath.Base(strings.ReplaceAll(header.Filename, "\\", "/"))
+	if name == "." || name == "/" {
+		name = "file"
+	}
+	if len([]rune(name)) > 255 {
+		name = string([]rune(name)[:255])
+	}
+	attachment := models.Attachment{
+		TaskID:      task.ID,
+		UploaderID:  user.ID,
+		FileName:    name,
+		ContentType: contentType,
+		Size:        header.Size,
+		StorageKey:  key,
+	}
+	if err := storage.DB.Create(&attachment).Error; err != nil {
+		removeBlobs([]string{key})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении файла"})
+		return
+	}
+	attachment.Uploader = *user
+
+	c.JSON(http.StatusCreated, toAttachmentResponse(attachment))
+}
+
+// GetTaskAttachmentsHandler возвращает файлы задачи
+// @Summary Файлы задачи
+// @Description Возвращает все файлы задачи, в том числе приложенные к комментариям и к работам на проверке. Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.AttachmentResponse "Файлы задачи"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении файлов"
+// @Router /tasks/{id}/attachments [get]
+func GetTaskAttachmentsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	var attachments []models.Attachment
+	if err := storage.DB.Preload("Uploader").
+		Where("task_id = ?", task.ID).
+		Order("created_at, id").
+		Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении файлов"})
+		return
+	}
+	c.JSON(http.StatusOK, toAttachmentResponses(attachments))
+}
+
+// findTaskAttachment ищет файл задачи из параметров :id и :attachment_id. При ошибке пишет ответ и возвращает false.
+func findTaskAttachment(c *gin.Context, task *models.Task, attachment *models.Attachment) bool {
+	if err := storage.DB.First(task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return false
+	}
+	if err := storage.DB.Where("task_id = ?", task.ID).First(attachment, c.Param("attachment_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Файл не найден", "code": "ATTACHMENT_NOT_FOUND"})
+		return false
+	}
+	return true
+}
+
+// DownloadTaskAttachmentHandler отдаёт содержимое файла
+// @Summary Скачивание файла задачи
+// @Description Отдаёт файл задачи с исходным именем. Ссылка на скачивание приходит в поле url описания файла; доступ проверяется так же, как к самой задаче.
+// @Tags tasks
+// @Produce octet-stream
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param attachment_id path string true "ID файла"
+// @Success 200 {file} file "Содержимое файла"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Файл не найден Code: ATTACHMENT_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при чтении файла"
+// @Router /tasks/{id}/attachments/{attachment_id} [get]
+func DownloadTaskAttachmentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var attachment models.Attachment
+	if !findTaskAttachment(c, &task, &attachment) {
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	content, err := blob.Files.Get(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Файл не найден", "code": "ATTACHMENT_NOT_FOUND"})
+		return
+	}
+	if err != nil {
+		fmt.Printf("Ошибка чтения файла %s из хранилища: %v\n", attachment.StorageKey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при чтении файла"})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteTaskAttachmentHandler удаляет файл задачи
+// @Summary Удаление файла задачи
+// @Description Удаляет файл из задачи и из хранилища. Доступно загрузившему файл и автору задачи.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param attachment_id path string true "ID файла"
+// @Success 200 {object} response.SuccessResponse "Файл удалён"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Удалить файл может только загрузивший его или автор задачи Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Файл не найден Code: ATTACHMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении файла"
+// @Router /tasks/{id}/attachments/{attachment_id} [delete]
+func DeleteTaskAttachmentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var attachment models.Attachment
+	if !findTaskAttachment(c, &task, &attachment) {
+		return
+	}
+	if attachment.UploaderID != user.ID && task.CreatedBy != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Удалить файл может только загрузивший его или автор задачи", "code": "FORBIDDEN"})
+		return
+	}
+	if !access.RequireActive(c, task.TeamID) {
+		return
+	}
+
+	if err := storage.DB.Delete(&attachment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении файла"})
+		return
+	}
+	removeBlobs([]string{attachment.StorageKey})
+	c.JSON(http.StatusOK, gin.H{"message": "Файл удалён"})
+}
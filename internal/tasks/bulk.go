@@ -0,0 +1,394 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Массовые операции над задачами.
+const (
+	BulkStatus   = "status"   // Смена статуса работы, как PUT /tasks/{id}/status
+	BulkAssign   = "assign"   // Новый исполнитель персональных задач
+	BulkDeadline = "deadline" // Новый срок
+	BulkDelete   = "delete"   // Удаление, как DELETE /tasks/{id}
+)
+
+// bulkDigestLimit — сколько задач перечисляется в одном уведомлении о массовой операции, остальные только считаются.
+const bulkDigestLimit = 30
+
+// errBulkRejected откатывает массовую операцию, если она невозможна хотя бы для одной задачи.
+var errBulkRejected = errors.New("bulk operation rejected")
+
+// bulkFailures собирает отказы по задачам массовой операции.
+type bulkFailures []response.BulkTaskFailureResponse
+
+func (f *bulkFailures) add(taskID uint, denial *access.Denial) {
+	*f = append(*f, response.BulkTaskFailureResponse{
+		TaskID: taskID,
+		Status: denial.Status,
+		Error:  denial.Message,
+		Code:   denial.Code,
+	})
+}
+
+// bulkDigest собирает строки об изменённых задачах по получателям, чтобы после массовой операции
+// каждый получил одно уведомление, а не по уведомлению на задачу.
+type bulkDigest struct {
+	actor   *models.User
+	lines   map[string][]string
+	order   []string
+	reviews map[string]bool // Получатели, которым отправлена работа на проверку
+}
+
+func newBulkDigest(actor *models.User) *bulkDigest {
+	return &bulkDigest{actor: actor, lines: make(map[string][]string), reviews: make(map[string]bool)}
+}
+
+// add добавляет строку каждому получателю, кроме автора изменения. Повторные Telegram ID пропускаются.
+func (d *bulkDigest) add(line string, recipients ...string) {
+	seen := make(map[string]bool, len(recipients))
+	for _, chatID := range recipients {
+		if chatID == "" || chatID == d.actor.TelegramID || seen[chatID] {
+			continue
+		}
+		seen[chatID] = true
+		if _, ok := d.lines[chatID]; !ok {
+			d.order = append(d.order, chatID)
+		}
+		d.lines[chatID] = append(d.lines[chatID], line)
+	}
+}
+
+// addReview добавляет строку о работе на проверке автору задачи; его уведомление получит кнопку перехода к проверке.
+func (d *bulkDigest) addReview(line, authorChatID string) {
+	if authorChatID == "" || authorChatID == d.actor.TelegramID {
+		return
+	}
+	d.reviews[authorChatID] = true
+	d.add(line, authorChatID)
+}
+
+// send отправляет каждому получателю одно уведомление с заголовком title.
+func (d *bulkDigest) send(title string) {
+	for _, chatID := range d.order {
+		lines := d.lines[chatID]
+		text := title + "\n\n"
+		if len(lines) > bulkDigestLimit {
+			text += strings.Join(lines[:bulkDigestLimit], "\n") + fmt.Sprintf("\n…и ещё %d", len(lines)-bulkDigestLimit)
+		} else {
+			text += strings.Join(lines, "\n")
+		}
+		text += fmt.Sprintf("\n\n✍️ Изменил(а): %s", d.actor.Name)
+
+		var buttons []notification.InlineButton
+		if d.reviews[chatID] {
+			buttons = []notification.InlineButton{{Text: "🔎 На проверке", CallbackData: "task_reviews"}}
+		}
+		go func(chatID string) {
+			if err := notification.SendTelegramNotificationWithButtons(chatID, text, buttons); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}(chatID)
+	}
+}
+
+// bulkDigestTitle — заголовок уведомления о массовой операции.
+func bulkDigestTitle(action string) string {
+	switch action {
+	case BulkStatus:
+		return "🔄 *Изменён статус задач*"
+	case BulkAssign:
+		return "👤 *Задачи переназначены*"
+	case BulkDeadline:
+		return "⏰ *Изменены сроки задач*"
+	default:
+		return "🚀 *Задачи отменены*"
+	}
+}
+
+type BulkTaskInput struct {
+	TaskIDs        []uint     `json:"task_ids" binding:"required,min=1,max=100"`
+	Action         string     `json:"action" binding:"required,oneof=status assign deadline delete"`
+	Status         string     `json:"status"`          // Для status: статус из процесса команды
+	CompletionText string     `json:"completion_text"` // Для status: отчёт по выполнению (опционально)
+	AssignedTo     string     `json:"assigned_to"`     // Для assign: Telegram ID нового исполнителя
+	Deadline       *time.Time `json:"deadline"`        // Для deadline: новый срок, RFC 3339
+}
+
+// BulkTaskHandler выполняет одну операцию над несколькими задачами
+// @Summary Массовые операции над задачами
+// @Description Меняет статус (status), исполнителя (assign), срок (deadline) или удаляет (delete) до 100 задач за один запрос в одной транзакции.
+// @Description Права и ограничения проверяются для каждой задачи так же, как в PUT /tasks/{id}/status (для status) и DELETE /tasks/{id} (для остальных операций): если операция невозможна хотя бы для одной задачи, не меняется ни одна, а в ответе перечисляются причины по задачам.
+// @Description Исполнителя можно назначить только персональным задачам. Подзадачи удаляемых задач можно удалить в том же запросе, а срок подзадачи проверяется по новому сроку родителя, если он тоже в запросе.
+// @Description Вместо уведомления на каждую задачу каждый получатель получает одно уведомление со списком изменённых задач: при смене статуса — авторы задач, при остальных операциях — исполнители.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body BulkTaskInput true "Задачи и операция"
+// @Success 200 {object} response.BulkTaskResponse "Результат операции"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Для операции assign укажите assigned_to Code: BULK_VALUE_REQUIRED"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Задачи не найдены: 3, 7 Code: TASK_NOT_FOUND"
+// @Failure 409 {object} response.BulkTaskErrorResponse "Error: Операция невозможна для 2 из 5 задач, ничего не изменено Code: BULK_REJECTED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка массового изменения задач"
+// @Router /tasks/bulk [post]
+func BulkTaskHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var input BulkTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var missingValue string
+	switch {
+	case input.Action == BulkStatus && input.Status == "":
+		missingValue = "status"
+	case input.Action == BulkAssign && input.AssignedTo == "":
+		missingValue = "assigned_to"
+	case input.Action == BulkDeadline && input.Deadline == nil:
+		missingValue = "deadline"
+	}
+	if missingValue != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Для операции %s укажите %s", input.Action, missingValue),
+			"code":  "BULK_VALUE_REQUIRED",
+		})
+		return
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(input.TaskIDs)))
+	var tasks []models.Task
+	if err := storage.DB.Where("id IN ?", ids).Order("id").Find(&tasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка массового изменения задач"})
+		return
+	}
+	selected := make(map[uint]bool, len(tasks))
+	for _, t := range tasks {
+		selected[t.ID] = true
+	}
+	if len(tasks) != len(ids) {
+		var missing []string
+		for _, id := range ids {
+			if !selected[id] {
+				missing = append(missing, fmt.Sprint(id))
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задачи не найдены: " + strings.Join(missing, ", "), "code": "TASK_NOT_FOUND"})
+		return
+	}
+
+	flows := make(map[uint]*workflow.Machine)
+	authors := make(map[uint]string)
+	for _, t := range tasks {
+		if _, ok := flows[t.TeamID]; ok {
+			continue
+		}
+		flow, err := workflow.ForTeam(storage.DB, t.TeamID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении процесса команды"})
+			return
+		}
+		flows[t.TeamID] = flow
+	}
+	if input.Action == BulkStatus {
+		authorIDs := make([]uint, 0, len(tasks))
+		for _, t := range tasks {
+			authorIDs = append(authorIDs, t.CreatedBy)
+		}
+		var users []models.User
+		if err := storage.DB.Where("id IN ?", authorIDs).Find(&users).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка массового изменения задач"})
+			return
+		}
+		for _, u := range users {
+			authors[u.ID] = u.TelegramID
+		}
+	}
+
+	digest := newBulkDigest(user)
+	var failures bulkFailures
+	previous := make(map[uint]string, len(tasks))
+	assigneeTeams := make(map[uint]bool) // Команды, в которых состоит новый исполнитель
+	unblocked := make(map[uint][]string) // Зависимая задача → удалённые блокирующие задачи
+	var dependents []models.Task
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range tasks {
+			task := &tasks[i]
+			flow := flows[task.TeamID]
+			previous[task.ID] = task.Status
+
+			if input.Action == BulkStatus {
+				role, denial := statusWorkerRole(user, task)
+				if denial != nil {
+					failures.add(task.ID, denial)
+					continue
+				}
+				current, status, review, err := changeTaskStatus(tx, task, flow, user, role, UpdateTaskStatusInput{
+					Status:         input.Status,
+					CompletionText: input.CompletionText,
+				})
+				if denial := statusDenial(err, flow, current, status); denial != nil {
+					failures.add(task.ID, denial)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				line := fmt.Sprintf("▫️ *%s:* %s → %s", task.Title, flow.Title(current), flow.Title(status))
+				if review != nil {
+					digest.addReview(line, authors[task.CreatedBy])
+				} else {
+					digest.add(line, authors[task.CreatedBy])
+				}
+				continue
+			}
+
+			if denial := checkTaskAuthor(user, task); denial != nil {
+				failures.add(task.ID, denial)
+				continue
+			}
+			before := *task
+			switch input.Action {
+			case BulkAssign:
+				if task.IsTeam {
+					failures.add(task.ID, &access.Denial{Status: http.StatusConflict, Message: "Задача командная, исполнителя назначить нельзя", Code: "TEAM_TASK"})
+					continue
+				}
+				member, checked := assigneeTeams[task.TeamID]
+				if !checked {
+					_, err := teamAssignees(tx, task.TeamID, []string{input.AssignedTo})
+					if err != nil && !errors.Is(err, errAssigneeNotInTeam) {
+						return err
+					}
+					member = err == nil
+					assigneeTeams[task.TeamID] = member
+				}
+				if !member {
+					failures.add(task.ID, &access.Denial{Status: http.StatusBadRequest, Message: "Исполнитель не состоит в команде", Code: "ASSIGNEE_NOT_IN_TEAM"})
+					continue
+				}
+				task.AssignedTo = &input.AssignedTo
+			case BulkDeadline:
+				task.Deadline = *input.Deadline
+				// Родитель из того же запроса получит тот же срок, поэтому проверяются только остальные
+				if task.ParentID != nil && !selected[*task.ParentID] {
+					_, err := checkParent(tx, task, *task.ParentID)
+					if denial := parentDenial(err); denial != nil {
+						failures.add(task.ID, denial)
+						continue
+					}
+					if err != nil {
+						return err
+					}
+				}
+				if task.Deadline.Before(before.Deadline) {
+					err := checkSubtaskDeadlines(tx.Where("id NOT IN ?", ids), task)
+					if denial := parentDenial(err); denial != nil {
+						failures.add(task.ID, denial)
+						continue
+					}
+					if err != nil {
+						return err
+					}
+				}
+			case BulkDelete:
+				var subtasks int64
+				if err := tx.Model(&models.Task{}).Where("parent_id = ? AND id NOT IN ?", task.ID, ids).Count(&subtasks).Error; err != nil {
+					return err
+				}
+				if subtasks > 0 {
+					failures.add(task.ID, errTaskHasSubtasks)
+					continue
+				}
+				blocked, err := dependentTasks(task.ID)
+				if err != nil {
+					return err
+				}
+				for _, d := range blocked {
+					if selected[d.ID] {
+						continue
+					}
+					if _, ok := unblocked[d.ID]; !ok {
+						dependents = append(dependents, d)
+					}
+					unblocked[d.ID] = append(unblocked[d.ID], task.Title)
+				}
+				if err := deleteTask(tx, task, user.ID); err != nil {
+					return err
+				}
+				digest.add(fmt.Sprintf("▫️ %s", task.Title), taskAudience(*task)...)
+				continue
+			}
+
+			changes := diffTask(before, *task)
+			if len(changes) == 0 {
+				continue
+			}
+			if err := tx.Save(task).Error; err != nil {
+				return err
+			}
+			if err := recordChanges(tx, task, user.ID, changes); err != nil {
+				return err
+			}
+			digest.add(
+				fmt.Sprintf("▫️ *%s:* %s → %s", task.Title, changes[0].old, changes[0].new),
+				append(taskAudience(before), taskAudience(*task)...)...,
+			)
+		}
+		if len(failures) > 0 {
+			return errBulkRejected
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkRejected) {
+		c.JSON(http.StatusConflict, response.BulkTaskErrorResponse{
+			Error:    fmt.Sprintf("Операция невозможна для %d из %d задач, ничего не изменено", len(failures), len(tasks)),
+			Code:     "BULK_REJECTED",
+			Failures: failures,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка массового изменения задач"})
+		return
+	}
+
+	resp := response.BulkTaskResponse{Action: input.Action, Tasks: []response.TaskResponse{}, Deleted: []uint{}}
+	if input.Action == BulkDelete {
+		resp.Deleted = ids
+		for _, d := range dependents {
+			reason := "Удалена последняя блокирующая задача: " + unblocked[d.ID][0]
+			if len(unblocked[d.ID]) > 1 {
+				reason = "Удалены блокирующие задачи: " + strings.Join(unblocked[d.ID], ", ")
+			}
+			notifyUnblocked([]models.Task{d}, reason)
+		}
+	} else {
+		for _, t := range tasks {
+			taskStatusChanged(previous[t.ID], t, flows[t.TeamID])
+		}
+		resp.Tasks = toTaskResponses(tasks)
+	}
+	digest.send(bulkDigestTitle(input.Action))
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,468 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// mentionPattern находит упоминания вида @Иван, @ivan_petrov или @123456789 (Telegram ID).
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_]+)`)
+
+// mentionKey приводит имя к виду, в котором его пишут в упоминании: нижний регистр, пробелы заменены на _.
+func mentionKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "_"))
+}
+
+// resolveMentions находит участников команды, упомянутых в тексте или переданных явно по Telegram ID.
+// Упоминание совпадает с Telegram ID, полным именем (пробелы — через _) или именем без фамилии,
+// если такое имя в команде одно. Автор комментария не упоминается.
+func resolveMentions(text string, explicit []string, teamID uint, authorID uint) ([]models.User, error) {
+	members, err := access.TeamUsers(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	firstNames := make(map[string]int)
+	for _, m := range members {
+		if fields := strings.Fields(m.Name); len(fields) > 0 {
+			firstNames[strings.ToLower(fields[0])]++
+		}
+	}
+
+	tokens := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		tokens[strings.ToLower(match[1])] = true
+	}
+	for _, id := range explicit {
+		tokens[strings.ToLower(strings.TrimPrefix(id, "@"))] = true
+	}
+
+	var mentioned []models.User
+	for _, m := range members {
+		if m.ID == authorID {
+			continue
+		}
+		fields := strings.Fields(m.Name)
+		matched := tokens[m.TelegramID] || tokens[mentionKey(m.Name)]
+		if !matched && len(fields) > 0 {
+			first := strings.ToLower(fields[0])
+			matched = tokens[first] && firstNames[first] == 1
+		}
+		if matched {
+			mentioned = append(mentioned, m)
+		}
+	}
+	return mentioned, nil
+}
+
+func mentionIDs(users []models.User) string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.TelegramID)
+	}
+	return strings.Join(ids, " ")
+}
+
+// commentCounts возвращает количество комментариев по задачам.
+func commentCounts(tasks []models.Task) map[uint]int {
+	counts := make(map[uint]int, len(tasks))
+	if len(tasks) == 0 {
+		return counts
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		TaskID uint
+		Count  int
+	}
+	if err := storage.DB.Model(&models.TaskComment{}).
+		Select("task_id, COUNT(*) AS count").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&rows).Error; err != nil {
+		return counts
+	}
+	for _, r := range rows {
+		counts[r.TaskID] = r.Count
+	}
+	return counts
+}
+
+func toTaskCommentResponse(comment models.TaskComment) response.TaskCommentResponse {
+	resp := response.TaskCommentResponse{
+		ID:               comment.ID,
+		ParentID:         comment.ParentID,
+		AuthorTelegramID: comment.Author.TelegramID,
+		AuthorName:       comment.Author.Name,
+		Text:             comment.Text,
+		Mentions:         strings.Fields(comment.Mentions),
+		EditedAt:         comment.EditedAt,
+		Attachments:      toAttachmentResponses(comment.Attachments),
+		CreatedAt:        comment.CreatedAt,
+		Replies:          []response.TaskCommentResponse{},
+	}
+	if comment.DeletedAt.Valid {
+		resp.Deleted = true
+		resp.Text = ""
+		resp.Mentions = []string{}
+		resp.Attachments = []response.AttachmentResponse{}
+	}
+	return resp
+}
+
+// buildCommentTree собирает комментарии в дерево ответов. Удалённый комментарий остаётся
+// в дереве без текста, только если на него есть ответы.
+func buildCommentTree(comments []models.TaskComment) []response.TaskCommentResponse {
+	children := make(map[uint][]models.TaskComment)
+	var roots []models.TaskComment
+	for _, c := range comments {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var build func(list []models.TaskComment) []response.TaskCommentResponse
+	build = func(list []models.TaskComment) []response.TaskCommentResponse {
+		result := make([]response.TaskCommentResponse, 0, len(list))
+		for _, c := range list {
+			resp := toTaskCommentResponse(c)
+			resp.Replies = build(children[c.ID])
+			if resp.Deleted && len(resp.Replies) == 0 {
+				continue
+			}
+			result = append(result, resp)
+		}
+		return result
+	}
+	return build(roots)
+}
+
+// notifyComment сообщает об упоминании в комментарии и об ответе автору родительского комментария.
+func notifyComment(task models.Task, comment models.TaskComment, author *models.User, mentioned []models.User, parentAuthor *models.User) {
+	base := fmt.Sprintf("▫️ *Задача:* %s\n▫️ *Автор:* %s\n\n%s", task.Title, author.Name, comment.Text)
+
+	var recipients []string
+	send := func(chatID, header string) {
+		if chatID == "" || chatID == author.TelegramID || slices.Contains(recipients, chatID) {
+			return
+		}
+		recipients = append(recipients, chatID)
+		text := header + "\n\n" + base
+		go func() {
+			if err := notification.SendTelegramNotification(chatID, text); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}()
+	}
+
+	for _, u := range mentioned {
+		send(u.TelegramID, "💬 *Вас упомянули в комментарии*")
+	}
+	if parentAuthor != nil {
+		send(parentAuthor.TelegramID, "💬 *Ответ на ваш комментарий*")
+	}
+}
+
+type CommentInput struct {
+	Text          string   `json:"text" binding:"required,max=4000"`
+	ParentID      *uint    `json:"parent_id"`      // Комментарий, на который это ответ
+	Mentions      []string `json:"mentions"`       // Telegram ID упомянутых участников, дополнительно к @упоминаниям в тексте
+	AttachmentIDs []uint   `json:"attachment_ids"` // Файлы, загруженные к задаче через POST /tasks/{id}/attachments
+}
+
+type UpdateCommentInput struct {
+	Text     string   `json:"text" binding:"required,max=4000"`
+	Mentions []string `json:"mentions"`
+}
+
+// findTaskComment ищет комментарий задачи из параметров :id и :comment_id. При ошибке пишет ответ и возвращает false.
+func findTaskComment(c *gin.Context, task *models.Task, comment *models.TaskComment) bool {
+	if err := storage.DB.First(task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return false
+	}
+	if err := storage.DB.Preload("Author").Preload("Attachments.Uploader").Where("task_id = ?", task.ID).First(comment, c.Param("comment_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Комментарий не найден", "code": "COMMENT_NOT_FOUND"})
+		return false
+	}
+	return true
+}
+
+// CreateTaskCommentHandler добавляет комментарий к задаче
+// @Summary Комментарий к задаче
+// @Description Добавляет комментарий или ответ на комментарий (parent_id). Участники команды, упомянутые через @Имя, @Имя_Фамилия, @TelegramID или в списке mentions, и автор комментария, на который дан ответ, получают уведомление в Telegram. Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param input body CommentInput true "Текст комментария"
+// @Success 201 {object} response.TaskCommentResponse "Комментарий добавлен"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Комментарий, на который вы отвечаете, не найден Code: PARENT_COMMENT_NOT_FOUND"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Файл не найден среди ваших вложений к задаче Code: ATTACHMENT_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при добавлении комментария"
+// @Router /tasks/{id}/comments [post]
+func CreateTaskCommentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, true) {
+		return
+	}
+
+	var input CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Комментарий не может быть пустым"})
+		return
+	}
+
+	var parent models.TaskComment
+	if input.ParentID != nil {
+		if err := storage.DB.Preload("Author").Where("task_id = ?", task.ID).First(&parent, *input.ParentID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Комментарий, на который вы отвечаете, не найден", "code": "PARENT_COMMENT_NOT_FOUND"})
+			return
+		}
+	}
+
+	mentioned, err := resolveMentions(input.Text, input.Mentions, task.TeamID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении комментария"})
+		return
+	}
+
+	comment := models.TaskComment{
+		TaskID:   task.ID,
+		ParentID: input.ParentID,
+		AuthorID: user.ID,
+		Text:     input.Text,
+		Mentions: mentionIDs(mentioned),
+	}
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if err := claimAttachments(tx, task.ID, user.ID, input.AttachmentIDs, "comment_id", comment.ID); err != nil {
+			return err
+		}
+		return tx.Preload("Uploader").Where("comment_id = ?", comment.ID).Order("id").Find(&comment.Attachments).Error
+	})
+	if errors.Is(err, errAttachmentNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл не найден среди ваших вложений к задаче", "code": "ATTACHMENT_NOT_FOUND"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении комментария"})
+		return
+	}
+	comment.Author = *user
+
+	var parentAuthor *models.User
+	if input.ParentID != nil {
+		parentAuthor = &parent.Author
+	}
+	notifyComment(task, comment, user, mentioned, parentAuthor)
+
+	c.JSON(http.StatusCreated, toTaskCommentResponse(comment))
+}
+
+// GetTaskCommentsHandler возвращает обсуждение задачи
+// @Summary Комментарии к задаче
+// @Description Возвращает комментарии задачи деревом: у каждого комментария — ответы в replies. Удалённый комментарий, на который есть ответы, возвращается без текста с deleted=true.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.TaskCommentResponse "Комментарии"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении комментариев"
+// @Router /tasks/{id}/comments [get]
+func GetTaskCommentsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	var comments []models.TaskComment
+	if err := storage.DB.Unscoped().Preload("Author").Preload("Attachments.Uploader").
+		Where("task_id = ?", task.ID).
+		Order("created_at, id").
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении комментариев"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildCommentTree(comments))
+}
+
+// UpdateTaskCommentHandler изменяет свой комментарий
+// @Summary Изменение комментария
+// @Description Изменяет текст собственного комментария. Уведомление получают только участники, которые упомянуты впервые. Автор, вышедший из команды, свои комментарии изменять не может.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param comment_id path string true "ID комментария"
+// @Param input body UpdateCommentInput true "Новый текст"
+// @Success 200 {object} response.TaskCommentResponse "Комментарий изменён"
+// @Failure 400 {object} response.ErrorResponse "Комментарий не может быть пустым"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Можно изменять только свои комментарии Code: NOT_COMMENT_AUTHOR, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Комментарий не найден Code: COMMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении комментария"
+// @Router /tasks/{id}/comments/{comment_id} [put]
+func UpdateTaskCommentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var comment models.TaskComment
+	if !findTaskComment(c, &task, &comment) {
+		return
+	}
+	if comment.AuthorID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Можно изменять только свои комментарии", "code": "NOT_COMMENT_AUTHOR"})
+		return
+	}
+	// Вышедший из команды не может править и удалять свои старые комментарии
+	if !requireTaskViewer(c, user, &task, true) {
+		return
+	}
+
+	var input UpdateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Комментарий не может быть пустым"})
+		return
+	}
+
+	mentioned, err := resolveMentions(input.Text, input.Mentions, task.TeamID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении комментария"})
+		return
+	}
+	previous := strings.Fields(comment.Mentions)
+	var added []models.User
+	for _, u := range mentioned {
+		if !slices.Contains(previous, u.TelegramID) {
+			added = append(added, u)
+		}
+	}
+
+	now := time.Now()
+	comment.Text = input.Text
+	comment.Mentions = mentionIDs(mentioned)
+	comment.EditedAt = &now
+	if err := storage.DB.Save(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении комментария"})
+		return
+	}
+
+	notifyComment(task, comment, user, added, nil)
+	c.JSON(http.StatusOK, toTaskCommentResponse(comment))
+}
+
+// DeleteTaskCommentHandler удаляет свой комментарий
+// @Summary Удаление комментария
+// @Description Удаляет собственный комментарий вместе с приложенными к нему файлами. Ответы на него остаются в обсуждении. Автор, вышедший из команды, свои комментарии удалять не может.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param comment_id path string true "ID комментария"
+// @Success 200 {object} response.SuccessResponse "Комментарий удалён"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Можно удалять только свои комментарии Code: NOT_COMMENT_AUTHOR, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Комментарий не найден Code: COMMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении комментария"
+// @Router /tasks/{id}/comments/{comment_id} [delete]
+func DeleteTaskCommentHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var comment models.TaskComment
+	if !findTaskComment(c, &task, &comment) {
+		return
+	}
+	if comment.AuthorID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Можно удалять только свои комментарии", "code": "NOT_COMMENT_AUTHOR"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, true) {
+		return
+	}
+
+	var keys []string
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if keys, err = deleteAttachments(tx, "comment_id = ?", comment.ID); err != nil {
+			return err
+		}
+		return tx.Delete(&comment).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении комментария"})
+		return
+	}
+	removeBlobs(keys)
+	c.JSON(http.StatusOK, gin.H{"message": "Комментарий удалён"})
+}
@@ -0,0 +1,366 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errDependencyCycle возвращается, если новая зависимость замкнёт цепочку блокирующих задач.
+var errDependencyCycle = errors.New("dependency cycle")
+
+// errTaskBlocked возвращается при попытке начать задачу, у которой есть незавершённые блокирующие задачи.
+type errTaskBlocked struct {
+	blockers []models.Task
+}
+
+func (e errTaskBlocked) Error() string {
+	titles := make([]string, 0, len(e.blockers))
+	for _, b := range e.blockers {
+		titles = append(titles, b.Title)
+	}
+	return "Задачу нельзя начать, пока не завершены: " + strings.Join(titles, ", ")
+}
+
+// openBlockers возвращает незавершённые задачи, которые блокируют задачу taskID.
+// Удалённые блокирующие задачи не учитываются.
+func openBlockers(db *gorm.DB, taskID uint) ([]models.Task, error) {
+	var blockers []models.Task
+	if err := db.Joins("JOIN task_dependencies ON task_dependencies.blocker_id = tasks.id").
+		Where("task_dependencies.task_id = ?", taskID).
+		Order("tasks.deadline").
+		Find(&blockers).Error; err != nil {
+		return nil, err
+	}
+
+	flows := teamFlows(blockers)
+	open := blockers[:0]
+	for _, b := range blockers {
+		if !flows[b.TeamID].IsTerminal(b.Status) {
+			open = append(open, b)
+		}
+	}
+	return open, nil
+}
+
+// checkDependencyCycle проверяет, что blockerID не зависит (в том числе через другие задачи) от taskID.
+func checkDependencyCycle(db *gorm.DB, taskID, blockerID uint) error {
+	seen := map[uint]bool{blockerID: true}
+	level := []uint{blockerID}
+	for len(level) > 0 {
+		var next []uint
+		if err := db.Model(&models.TaskDependency{}).Where("task_id IN ?", level).Pluck("blocker_id", &next).Error; err != nil {
+			return err
+		}
+		level = level[:0]
+		for _, id := range next {
+			if id == taskID {
+				return errDependencyCycle
+			}
+			if !seen[id] {
+				seen[id] = true
+				level = append(level, id)
+			}
+		}
+	}
+	return nil
+}
+
+// blockerCounts возвращает количество незавершённых блокирующих задач по задачам.
+func blockerCounts(tasks []models.Task) map[uint]int {
+	counts := make(map[uint]int, len(tasks))
+	if len(tasks) == 0 {
+		return counts
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var deps []models.TaskDependency
+	if err := storage.DB.Joins("Blocker").Where("task_dependencies.task_id IN ?", ids).Find(&deps).Error; err != nil {
+		return counts
+	}
+	blockers := make([]models.Task, 0, len(deps))
+	for _, d := range deps {
+		blockers = append(blockers, d.Blocker)
+	}
+	flows := teamFlows(blockers)
+	for _, d := range deps {
+		if d.Blocker.ID != 0 && !flows[d.Blocker.TeamID].IsTerminal(d.Blocker.Status) {
+			counts[d.TaskID]++
+		}
+	}
+	return counts
+}
+
+func toTaskDependencyResponses(tasks []models.Task) []response.TaskDependencyResponse {
+	flows := teamFlows(tasks)
+	resp := make([]response.TaskDependencyResponse, 0, len(tasks))
+	for _, t := range tasks {
+		resp = append(resp, response.TaskDependencyResponse{
+			TaskID:   t.ID,
+			Title:    t.Title,
+			Status:   t.Status,
+			Done:     flows[t.TeamID].IsTerminal(t.Status),
+			TeamID:   t.TeamID,
+			Deadline: t.Deadline,
+		})
+	}
+	return resp
+}
+
+// dependentTasks возвращает задачи, которые ждут завершения задачи taskID.
+func dependentTasks(taskID uint) ([]models.Task, error) {
+	var dependents []models.Task
+	err := storage.DB.Joins("JOIN task_dependencies ON task_dependencies.task_id = tasks.id").
+		Where("task_dependencies.blocker_id = ?", taskID).
+		Find(&dependents).Error
+	return dependents, err
+}
+
+// writeDependencies отвечает списками блокирующих и зависимых задач.
+func writeDependencies(c *gin.Context, taskID uint, code int) {
+	var blockedBy []models.Task
+	if err := storage.DB.Joins("JOIN task_dependencies ON task_dependencies.blocker_id = tasks.id").
+		Where("task_dependencies.task_id = ?", taskID).
+		Order("tasks.deadline").
+		Find(&blockedBy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении зависимостей"})
+		return
+	}
+	blocks, err := dependentTasks(taskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении зависимостей"})
+		return
+	}
+
+	c.JSON(code, response.TaskDependenciesResponse{
+		BlockedBy: toTaskDependencyResponses(blockedBy),
+		Blocks:    toTaskDependencyResponses(blocks),
+	})
+}
+
+// releaseDependents вызывается после изменения общего статуса задачи: если задача только что завершилась,
+// исполнители зависящих от неё задач, у которых не осталось незавершённых блокирующих задач,
+// получают уведомление, что можно начинать.
+func releaseDependents(previous string, task models.Task, flow *workflow.Machine) {
+	if flow.IsTerminal(previous) || !flow.IsTerminal(task.Status) {
+		return
+	}
+	dependents, err := dependentTasks(task.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения зависимых задач: %v\n", err)
+		return
+	}
+	notifyUnblocked(dependents, "Завершена последняя блокирующая задача: "+task.Title)
+}
+
+// notifyUnblocked уведомляет исполнителей незавершённых задач, у которых не осталось незавершённых
+// блокирующих задач. reason объясняет, что произошло с последней из них.
+func notifyUnblocked(dependents []models.Task, reason string) {
+	flows := teamFlows(dependents)
+	for _, dependent := range dependents {
+		if flows[dependent.TeamID].IsTerminal(dependent.Status) {
+			continue
+		}
+		open, err := openBlockers(storage.DB, dependent.ID)
+		if err != nil || len(open) > 0 {
+			continue
+		}
+		text := fmt.Sprintf(
+			"🔓 *Задачу можно начинать*\n\n▫️ *Заголовок:* %s\n▫️ *Дедлайн:* %s\n\n%s",
+			dependent.Title,
+			notification.FormatDeadline(dependent.Deadline),
+			reason,
+		)
+		for _, chatID := range taskAudience(dependent) {
+			if chatID == "" {
+				continue
+			}
+			go func(chatID string) {
+				if err := notification.SendTelegramNotification(chatID, text); err != nil {
+					fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+				}
+			}(chatID)
+		}
+	}
+}
+
+// GetTaskDependenciesHandler возвращает зависимости задачи
+// @Summary Зависимости задачи
+// @Description Возвращает задачи, которые блокируют эту (blocked_by), и задачи, которые ждут её завершения (blocks). Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {object} response.TaskDependenciesResponse "Зависимости"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении зависимостей"
+// @Router /tasks/{id}/dependencies [get]
+func GetTaskDependenciesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	writeDependencies(c, task.ID, http.StatusOK)
+}
+
+type DependencyInput struct {
+	BlockerID uint `json:"blocker_id" binding:"required"` // Задача, которую нужно завершить раньше
+}
+
+// AddTaskDependencyHandler добавляет блокирующую задачу
+// @Summary Блокирующая задача
+// @Description Указывает, что задачу нельзя начать, пока не завершена задача blocker_id. Блокирующая задача должна быть доступна пользователю; зависимость, замыкающая цепочку блокировок в цикл, отклоняется. Доступно автору задачи и владельцу команды.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param input body DependencyInput true "Блокирующая задача"
+// @Success 201 {object} response.TaskDependenciesResponse "Зависимости задачи"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Задача не может блокировать саму себя Code: SELF_DEPENDENCY"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Блокирующая задача не найдена Code: BLOCKER_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Зависимость создаёт цикл: блокирующая задача сама ждёт эту Code: DEPENDENCY_CYCLE"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Зависимость уже добавлена Code: DEPENDENCY_EXISTS"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при добавлении зависимости"
+// @Router /tasks/{id}/dependencies [post]
+func AddTaskDependencyHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskAuthor(c, user, &task) {
+		return
+	}
+
+	var input DependencyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.BlockerID == task.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Задача не может блокировать саму себя", "code": "SELF_DEPENDENCY"})
+		return
+	}
+
+	var blocker models.Task
+	if err := storage.DB.First(&blocker, input.BlockerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Блокирующая задача не найдена", "code": "BLOCKER_NOT_FOUND"})
+		return
+	}
+	if !requireTaskViewer(c, user, &blocker, false) {
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkDependencyCycle(tx, task.ID, blocker.ID); err != nil {
+			return err
+		}
+		var exists int64
+		if err := tx.Model(&models.TaskDependency{}).Where("task_id = ? AND blocker_id = ?", task.ID, blocker.ID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return gorm.ErrDuplicatedKey
+		}
+		return tx.Create(&models.TaskDependency{TaskID: task.ID, BlockerID: blocker.ID, CreatedBy: user.ID}).Error
+	})
+	if errors.Is(err, errDependencyCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Зависимость создаёт цикл: блокирующая задача сама ждёт эту", "code": "DEPENDENCY_CYCLE"})
+		return
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Зависимость уже добавлена", "code": "DEPENDENCY_EXISTS"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении зависимости"})
+		return
+	}
+
+	writeDependencies(c, task.ID, http.StatusCreated)
+}
+
+// RemoveTaskDependencyHandler убирает блокирующую задачу
+// @Summary Удаление зависимости
+// @Description Убирает блокирующую задачу. Если у задачи больше не осталось незавершённых блокирующих задач, исполнители получают уведомление. Доступно автору задачи и владельцу команды.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param blocker_id path string true "ID блокирующей задачи"
+// @Success 200 {object} response.TaskDependenciesResponse "Зависимости задачи"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Зависимость не найдена Code: DEPENDENCY_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении зависимости"
+// @Router /tasks/{id}/dependencies/{blocker_id} [delete]
+func RemoveTaskDependencyHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskAuthor(c, user, &task) {
+		return
+	}
+
+	wasBlocked, err := openBlockers(storage.DB, task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении зависимости"})
+		return
+	}
+	result := storage.DB.Where("task_id = ? AND blocker_id = ?", task.ID, c.Param("blocker_id")).Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении зависимости"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Зависимость не найдена", "code": "DEPENDENCY_NOT_FOUND"})
+		return
+	}
+	if len(wasBlocked) > 0 {
+		notifyUnblocked([]models.Task{task}, "Автор задачи убрал последнюю блокирующую задачу")
+	}
+
+	writeDependencies(c, task.ID, http.StatusOK)
+}
@@ -1,507 +1,1753 @@
-package tasks
-
-import (
-	"fmt"
-	"net/http"
-	"time"
-
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
-	"github.com/gin-gonic/gin"
-)
-
-type TaskInput struct {
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description" binding:"required"`
-	Deadline    time.Time `json:"deadline" binding:"required"` //RFC 3339
-	IsTeam      bool      `json:"is_team"`
-	AssignedTo  *string   `json:"assigned_to"`
-}
-
-// CreateTaskHandlres создает новую задачу
-// @Summary Создание задачи
-// @Description Создание задачи для команды и индивидуально
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Telegram ID управляющегоr"
-// @Param task body TaskInput true "Информация задачи"
-// @Success 200 {object} response.SuccessResponse "Задача успешно создана"
-// @Failure 400 {object} response.ErrorResponse "telegram_id is required"
-// @Failure 400 {object} response.ErrorResponse "У пользователя нет привязанной команды"
-// @Failure 400 {object} response.ErrorResponse "assigned_to обязателен для персональных задач"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Только менеджер может создавать задачу"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при создании задачи"
-// @Router /tasks [post]
-func CreateTaskHandlres(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может создавать задачу"})
-		return
-	}
-	if user.TeamID == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "У пользователя нет привязанной команды"})
-		return
-	}
-
-	var input TaskInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if !input.IsTeam && input.AssignedTo == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "assigned_to обязателен для персональных задач"})
-		return
-	}
-
-	var task = models.Task{
-		Title:       input.Title,
-		Description: input.Description,
-		Deadline:    input.Deadline,
-		IsTeam:      input.IsTeam,
-		AssignedTo:  input.AssignedTo,
-		TeamID:      *user.TeamID,
-		CreatedBy:   user.ID,
-	}
-
-	if err := storage.DB.Create(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
-		return
-	}
-
-	var notificationText string
-	if input.IsTeam {
-		notificationText = fmt.Sprintf(
-			"🚀 *Новая командная задача!*\n\n"+
-				"▫️ *Заголовок:* %s\n"+
-				"▫️ *Описание:* \n_%s_\n"+
-				"▫️ *Дедлайн:* %s\n"+
-				"▫️ *Тип:* Общая задача команды\n\n"+
-				"🕑 Создано: %s",
-			(task.Title),
-			(task.Description),
-			notification.FormatDeadline(task.Deadline),
-			time.Now().Format("02.01.2006 15:04"),
-		)
-
-		var teamUsers []models.User
-		if err := storage.DB.Where("team_id = ?", user.TeamID).Find(&teamUsers).Error; err != nil {
-			fmt.Printf("Ошибка получения участников команды: %v\n", err)
-		}
-
-		for _, u := range teamUsers {
-			if u.TelegramID != "" {
-				go func(chatID string) {
-					if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
-						fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
-					}
-				}(u.TelegramID)
-			}
-		}
-	} else {
-		notificationText = fmt.Sprintf(
-			"📌 *Новая персональная задача!*\n\n"+
-				"▫️ *Заголовок:* %s\n"+
-				"▫️ *Описание:* \n_%s_\n"+
-				"▫️ *Дедлайн:* %s\n"+
-				"▫️ *Назначена:* Вам лично\n\n"+
-				"🕑 Создано: %s",
-			task.Title,
-			task.Description,
-			notification.FormatDeadline(task.Deadline),
-			time.Now().Format("02.01.2006 15:04"),
-		)
-
-		var assignedUser models.User
-		if err := storage.DB.Where("telegram_id = ?", input.AssignedTo).First(&assignedUser).Error; err != nil {
-			fmt.Printf("Пользователь не найден: %v\n", err)
-			return
-		}
-
-		if assignedUser.TelegramID != "" {
-			if err := notification.SendTelegramNotification(assignedUser.TelegramID, notificationText); err != nil {
-				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", assignedUser.TelegramID, err)
-			}
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно создана"})
-}
-
-// GetTasksHandlres получает список задач для пользователя
-// @Summary Получение списка задач
-// @Description Получение списка задач для пользователя
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Telegram ID пользователя"
-// @Success 200 {object} []response.TaskResponse "Список задач"
-// @Failure 400 {object} response.ErrorResponse "telegram_id is required"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при получении задач"
-// @Router /tasks [get]
-func GetTasksHandlres(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	var tasks []models.Task
-	if err := storage.DB.Where("team_id = ?", user.TeamID).Find(&tasks).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
-		return
-	}
-	if err := storage.DB.Where("assigned_to = ?", user.TelegramID).Find(&tasks).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
-		return
-	}
-
-	var responseTasks []response.TaskResponse
-	for _, task := range tasks {
-		responseTasks = append(responseTasks, response.TaskResponse{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			Deadline:    task.Deadline,
-			Status:      task.Status,
-			IsTeam:      task.IsTeam,
-			AssignedTo:  task.AssignedTo,
-			CreatedBy:   task.CreatedBy,
-			TeamID:      task.TeamID,
-		})
-	}
-
-	c.JSON(http.StatusOK, responseTasks)
-}
-
-// DeleteTaskHandler удаляет задачу
-// @Summary Удаление задачи
-// @Description Удаление задачи менеджером команды
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Telegram ID менеджера"
-// @Param id path string true "ID задачи"
-// @Success 200 {object} response.SuccessResponse "Задача успешно удалена"
-// @Failure 400 {object} response.ErrorResponse "Error: telegram_id is required CODE: NOT_TG_ID"
-// @Failure 400 {object} response.ErrorResponse "Error: task_id is required CODE: NOT_TASK_ID"
-// @Failure 400 {object} response.ErrorResponse "Error: У пользователя нет привязанной команды CODE: NOT_TEAM"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Только менеджер может удалять задачу"
-// @Failure 403 {object} response.ErrorResponse "Задачу создали не вы"
-// @Failure 500 {object} response.ErrorResponse "Задача не найдена"
-// @Router /tasks/{id} [delete]
-func DeleteTaskHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	taskID := c.Param("id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required", "code": "NOT_TG_ID"})
-		return
-	}
-
-	if taskID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id is required", "code": "NOT_TASK_ID"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может удалить задачу"})
-		return
-	}
-	if user.TeamID == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "У пользователя нет привязанной команды", "code": "NOT_TEAM"})
-		return
-	}
-
-	var task models.Task
-	if err := storage.DB.First(&task, taskID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Задача не найдена"})
-		return
-	}
-
-	if task.CreatedBy != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Задачу создали не вы"})
-		return
-	}
-
-	var notificationText string
-	if task.IsTeam {
-		notificationText = fmt.Sprintf(
-			"🚀 *Командная задача отменена!*\n\n"+
-				"▫️ *Заголовок:* %s\n"+
-				"▫️ *Описание:* \n_%s_\n",
-			task.Title,
-			task.Description,
-		)
-		var teamUsers []models.User
-		if err := storage.DB.Where("team_id = ?", task.TeamID).Find(&teamUsers).Error; err != nil {
-			// Логирование ошибки, но можно продолжать отправку уведомлений тому, кого удалось найти
-			fmt.Printf("Ошибка получения участников команды: %v\n", err)
-		}
-		for _, u := range teamUsers {
-			if u.TelegramID != "" {
-				go func(chatID string) {
-					if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
-						fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
-					}
-				}(u.TelegramID)
-			}
-		}
-	} else {
-		notificationText = fmt.Sprintf(
-			"🚀 *Вашу задача отменили!*\n\n"+
-				"▫️ *Заголовок:* %s\n"+
-				"▫️ *Описание:* \n_%s_\n"+
-				(task.Title),
-			(task.Description),
-		)
-		var assignedUser models.User
-		if err := storage.DB.Where("telegram_id = ?", task.AssignedTo).First(&assignedUser).Error; err != nil {
-			fmt.Printf("Пользователь не найден: %v\n", err)
-			return
-		}
-
-		if assignedUser.TelegramID != "" {
-			if err := notification.SendTelegramNotification(assignedUser.TelegramID, notificationText); err != nil {
-				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", assignedUser.TelegramID, err)
-			}
-		}
-	}
-
-	if err := storage.DB.Delete(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления задачи"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно удалена"})
-}
-
-type UpdateTaskStatusInput struct {
-	Status         string `json:"status" binding:"required"` // Ожидаемые значения: "in_progress" или "completed"
-	CompletionText string `json:"completion_text"`           // Отчёт по выполнению (опционально)
-	Attachment     string `json:"attachment"`                // Ссылка на файл или описание вложения (опционально)
-}
-
-// UpdateTaskStatusHandler обновляет статус задачи.
-// Если статус становится "completed", отправляется уведомление менеджеру команды с данными отчёта и информацией о том, кто выполнил задачу.
-// UpdateTaskStatusHandler обновляет статус задачи
-// @Summary Обновление статуса задачи
-// @Description Обновление статуса задачи участником команды или исполнителем персональной задачи
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Telegram ID пользователя"
-// @Param id path string true "ID задачи"
-// @Param task body UpdateTaskStatusInput true "Данные для обновления статуса"
-// @Success 200 {object} response.SuccessResponse "Статус задачи успешно обновлен"
-// @Failure 400 {object} response.ErrorCodeResponse "Error: telegram_id is required CODE: NOT_TG_ID"
-// @Failure 400 {object} response.ErrorCodeResponse "Error: task_id is required CODE: NOT_TASK_ID"
-// @Failure 400 {object} response.ErrorCodeResponse "Error: У пользователя нет привязанной команды CODE: NOT_TEAM"
-// @Failure 400 {object} response.ErrorResponse "Неверное значение статуса"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "У вас нет прав для изменения статуса этой задачи"
-// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении статуса задачи"
-// @Router /tasks/{id}/status [put]
-func UpdateTaskStatusHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	taskID := c.Param("id")
-	if taskID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id is required"})
-		return
-	}
-
-	// Поиск пользователя по TelegramID
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	// Поиск задачи по ID
-	var task models.Task
-	if err := storage.DB.First(&task, taskID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
-		return
-	}
-
-	// Проверка прав: если задача персональная, то изменять статус может только назначенный участник.
-	if !task.IsTeam {
-		if task.AssignedTo == nil || *task.AssignedTo != user.TelegramID {
-			c.JSON(http.StatusForbidden, gin.H{"error": "У вас нет прав для изменения статуса этой задачи"})
-			return
-		}
-	} else {
-		// Для командной задачи проверяем, что пользователь принадлежит команде.
-		if user.TeamID == nil || *user.TeamID != task.TeamID {
-			c.JSON(http.StatusForbidden, gin.H{"error": "У вас нет прав для изменения статуса этой задачи"})
-			return
-		}
-	}
-
-	// Считываем данные из запроса
-	var input UpdateTaskStatusInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Проверяем, что статус имеет корректное значение.
-	if input.Status != "in_progress" && input.Status != "completed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверное значение статуса. Допустимые значения: in_progress, completed"})
-		return
-	}
-
-	// Обновляем статус задачи в БД
-	task.Status = input.Status
-	if err := storage.DB.Save(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении статуса задачи"})
-		return
-	}
-
-	// Если статус изменён на "completed", отправляем уведомление менеджеру.
-	if input.Status == "completed" {
-		// Предполагаем, что поле CreatedBy в задаче содержит ID менеджера, создавшего задачу.
-		var manager models.User
-		if err := storage.DB.First(&manager, task.CreatedBy).Error; err != nil {
-			c.JSON(http.StatusOK, gin.H{"message": "Статус задачи обновлен, но уведомление менеджеру не отправлено"})
-			return
-		}
-
-		// Формируем текст уведомления с данными отчёта и информацией, кто выполнил задачу.
-		notificationText := fmt.Sprintf(
-			"✅ *Задача выполнена!*\n\n▫️ *Заголовок:* %s\n▫️ *Описание:* %s\n▫️ *Статус:* выполнено\n▫️ *Выполнил:* %s\n\n*Отчет участника:*\n%s",
-			task.Title,
-			task.Description,
-			user.Name, // Добавляем имя пользователя, который завершил задачу
-			input.CompletionText,
-		)
-		if input.Attachment != "" {
-			notificationText += fmt.Sprintf("\n▫️ *Вложение:* %s", input.Attachment)
-		}
-
-		// Отправляем уведомление менеджеру через Telegram (асинхронно).
-		if manager.TelegramID != "" {
-			go func(chatID string) {
-				if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
-					fmt.Printf("Ошибка отправки уведомления менеджеру %s: %v\n", chatID, err)
-				}
-			}(manager.TelegramID)
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Статус задачи успешно обновлен"})
-}
-
-// @Summary Получить выданные задачи
-// @Description Возвращает список задач, созданных менеджером. Отправляет уведомление в Telegram с списком задач или сообщением об их отсутствии.
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Telegram ID менеджера"
-// @Success 200 {array} response.TaskResponse "Список выданных задач"
-// @Failure 400 {object} response.ErrorResponse "telegram_id is required"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Доступно только для руководителя"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при получении задач"
-// @Router /tasks/issued [get]
-func IssuedTaskHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required", "code": "NOT_TG_ID"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступно только для руководителя"})
-		return
-	}
-
-	var tasks []models.Task
-	if err := storage.DB.Where("created_by = ?", user.ID).Find(&tasks).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
-		return
-	}
-
-	// Формирование уведомления для Telegram
-	// var notificationText string
-	// if len(tasks) == 0 {
-	// 	notificationText = "ℹ️ *Нет выданных задач*"
-	// } else {
-	// 	notificationText = "📋 *Список выданных задач:*\n\n"
-	// 	for i, task := range tasks {
-	// 		notificationText += fmt.Sprintf(
-	// 			"%d. *%s*\n▫️ Описание: _%s_\n▫️ Дедлайн: %s\n▫️ Статус: %s\n\n",
-	// 			i+1,
-	// 			task.Title,
-	// 			task.Description,
-	// 			notification.FormatDeadline(task.Deadline),
-	// 			task.Status,
-	// 		)
-	// 	}
-	// }
-
-	// // Асинхронная отправка уведомления
-	// if user.TelegramID != "" {
-	// 	go func() {
-	// 		if err := notification.SendTelegramNotification(user.TelegramID, notificationText); err != nil {
-	// 			fmt.Printf("Ошибка отправки уведомления: %v\n", err)
-	// 		}
-	// 	}()
-	// }
-
-	// Формирование ответа API
-	var responseTasks []response.TaskResponse
-	for _, task := range tasks {
-		responseTasks = append(responseTasks, response.TaskResponse{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			Deadline:    task.Deadline,
-			Status:      task.Status,
-			IsTeam:      task.IsTeam,
-			AssignedTo:  task.AssignedTo,
-			CreatedBy:   task.CreatedBy,
-			TeamID:      task.TeamID,
-		})
-	}
-
-	c.JSON(http.StatusOK, responseTasks)
-}
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/recurrence"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TaskInput struct {
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description" binding:"required"`
+	Deadline    time.Time `json:"deadline" binding:"required"` //RFC 3339
+	IsTeam      bool      `json:"is_team"`
+	AssignedTo  *string   `json:"assigned_to"`
+	// Когда командная задача считается выполненной: all (по умолчанию), any или quorum
+	CompletionRule string `json:"completion_rule" binding:"omitempty,oneof=all any quorum"`
+	QuorumPercent  int    `json:"quorum_percent" binding:"min=0,max=100"` // Для правила quorum, по умолчанию 50
+	ParentID       *uint  `json:"parent_id"`                              // Родительская задача той же команды; срок подзадачи не может быть позже её срока
+	// Приоритет: low, medium (по умолчанию), high или urgent
+	Priority      string   `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	StoryPoints   *int     `json:"story_points" binding:"omitempty,min=0"`
+	EstimateHours *float64 `json:"estimate_hours" binding:"omitempty,min=0"`
+	LabelIDs      []uint   `json:"label_ids"` // Метки команды
+	// Правило повторения в формате RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL.
+	// Срок задачи — первое повторение серии
+	Recurrence string `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=FR"`
+}
+
+// taskPriority возвращает приоритет со значением по умолчанию.
+func taskPriority(priority string) string {
+	if priority == "" {
+		return PriorityMedium
+	}
+	return priority
+}
+
+// completionRule возвращает правило выполнения и кворум со значениями по умолчанию.
+func completionRule(rule string, quorum int) (string, int) {
+	if rule == "" {
+		rule = CompletionAll
+	}
+	if quorum == 0 {
+		quorum = 50
+	}
+	return rule, quorum
+}
+
+// CreateTaskHandlres создает новую задачу
+// @Summary Создание задачи
+// @Description Создание задачи для команды и индивидуально. Командная задача заводится у каждого участника команды, который может работать с задачами; правило completion_rule определяет, когда она считается выполненной.
+// @Description С parent_id задача создаётся подзадачей другой задачи команды: её срок должен быть не позже срока родительской, а прогресс родительской задачи складывается из прогресса подзадач.
+// @Description Задаче можно указать приоритет, оценку в story points и часах и метки команды (label_ids, см. GET /team/labels).
+// @Description С recurrence задача становится первой в серии повторяющихся задач: следующая создаётся автоматически, когда выполнена предыдущая или наступил её срок (см. GET /tasks/series).
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param task body TaskInput true "Информация задачи"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Задача успешно создана"
+// @Failure 400 {object} response.ErrorCodeResponse "assigned_to обязателен для персональных задач, Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Родительская задача не найдена в команде Code: PARENT_TASK_NOT_FOUND"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Срок подзадачи не может быть позже срока родительской задачи (25.03.2025 15:00) Code: DEADLINE_AFTER_PARENT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Метка не найдена в команде Code: LABEL_NOT_FOUND"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: в правиле нет FREQ Code: INVALID_RECURRENCE"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании задачи"
+// @Router /tasks [post]
+func CreateTaskHandlres(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var input TaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.IsTeam {
+		input.AssignedTo = nil
+	} else {
+		if input.AssignedTo == nil || *input.AssignedTo == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "assigned_to обязателен для персональных задач"})
+			return
+		}
+		var assignee models.User
+		if err := storage.DB.Where("telegram_id = ?", *input.AssignedTo).First(&assignee).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+			return
+		}
+		if _, err := access.GetMembership(assignee.ID, membership.TeamID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+			return
+		}
+	}
+
+	var recurrenceRule *recurrence.Rule
+	if input.Recurrence != "" {
+		var err error
+		if recurrenceRule, err = recurrence.Parse(input.Recurrence); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_RECURRENCE"})
+			return
+		}
+		if input.ParentID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Повторяющаяся задача не может быть подзадачей", "code": "INVALID_RECURRENCE"})
+			return
+		}
+	}
+
+	flow, err := workflow.ForTeam(storage.DB, membership.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+		return
+	}
+
+	rule, quorum := completionRule(input.CompletionRule, input.QuorumPercent)
+	var task = models.Task{
+		Title:          input.Title,
+		Description:    input.Description,
+		Deadline:       input.Deadline,
+		Status:         flow.Initial(),
+		IsTeam:         input.IsTeam,
+		AssignedTo:     input.AssignedTo,
+		TeamID:         membership.TeamID,
+		CreatedBy:      user.ID,
+		CompletionRule: rule,
+		QuorumPercent:  quorum,
+		ParentID:       input.ParentID,
+		Priority:       taskPriority(input.Priority),
+		StoryPoints:    input.StoryPoints,
+		EstimateHours:  input.EstimateHours,
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if task.ParentID != nil {
+			if _, err := checkParent(tx, &task, *task.ParentID); err != nil {
+				return err
+			}
+		}
+		labels, err := teamLabels(tx, task.TeamID, input.LabelIDs)
+		if err != nil {
+			return err
+		}
+		if recurrenceRule != nil {
+			if err := newSeries(tx, &task, recurrenceRule, labels); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if err := setTaskLabels(tx, task.ID, labels); err != nil {
+			return err
+		}
+		if err := recordEvent(tx, models.TaskEvent{TaskID: task.ID, Type: EventCreated, ActorID: user.ID, ToStatus: task.Status, TaskStatus: task.Status}); err != nil {
+			return err
+		}
+		if task.IsTeam {
+			return createAssignments(tx, &task, flow)
+		}
+		return nil
+	})
+	if writeParentError(c, err) {
+		return
+	}
+	if errors.Is(err, errLabelNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Метка не найдена в команде", "code": "LABEL_NOT_FOUND"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+		return
+	}
+
+	notifyTaskCreated(task)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно создана"})
+}
+
+// notifyTaskCreated уведомляет о новой задаче участников команды или исполнителя персональной задачи.
+func notifyTaskCreated(task models.Task) {
+	var notificationText string
+	if task.IsTeam {
+		notificationText = fmt.Sprintf(
+			"🚀 *Новая командная задача!*\n\n"+
+				"▫️ *Заголовок:* %s\n"+
+				"▫️ *Описание:* \n_%s_\n"+
+				"▫️ *Дедлайн:* %s\n"+
+				"▫️ *Приоритет:* %s\n"+
+				"▫️ *Тип:* Общая задача команды\n\n"+
+				"🕑 Создано: %s",
+			task.Title,
+			task.Description,
+			notification.FormatDeadline(task.Deadline),
+			priorityTitle(task.Priority),
+			time.Now().Format("02.01.2006 15:04"),
+		)
+	} else {
+		notificationText = fmt.Sprintf(
+			"📌 *Новая персональная задача!*\n\n"+
+				"▫️ *Заголовок:* %s\n"+
+				"▫️ *Описание:* \n_%s_\n"+
+				"▫️ *Дедлайн:* %s\n"+
+				"▫️ *Приоритет:* %s\n"+
+				"▫️ *Назначена:* Вам лично\n\n"+
+				"🕑 Создано: %s",
+			task.Title,
+			task.Description,
+			notification.FormatDeadline(task.Deadline),
+			priorityTitle(task.Priority),
+			time.Now().Format("02.01.2006 15:04"),
+		)
+	}
+
+	for _, chatID := range taskAudience(task) {
+		if chatID == "" {
+			continue
+		}
+		go func(chatID string) {
+			if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}(chatID)
+	}
+}
+
+// GetTasksHandlres получает список задач для пользователя
+// @Summary Получение списка задач
+// @Description Возвращает одним списком задачи команды и персональные задачи пользователя из всех команд. Список можно отфильтровать по статусу, приоритету, меткам, исполнителю, сроку, просрочке и отсутствию оценки и отсортировать по сроку, дате создания, приоритету или оценке.
+// @Description Список выдаётся страницами по limit задач: чтобы получить следующую, передайте next_cursor из ответа в cursor с теми же фильтрами и сортировкой. total — количество задач, подходящих под фильтры.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param department_id query int false "ID отдела: задачи всех команд отдела и вложенных отделов (для руководителя отдела и владельца организации)"
+// @Param status query string false "Статусы через запятую"
+// @Param priority query string false "Приоритеты через запятую: low, medium, high, urgent"
+// @Param label query string false "ID меток через запятую: задачи хотя бы с одной из них"
+// @Param assignee query string false "Telegram ID исполнителя или me: его персональные задачи и командные задачи, над которыми он работает"
+// @Param deadline_from query string false "Срок не раньше (RFC 3339)"
+// @Param deadline_to query string false "Срок не позже (RFC 3339)"
+// @Param overdue query bool false "Только просроченные незавершённые задачи"
+// @Param unestimated query bool false "Только задачи без оценки"
+// @Param sort query string false "Сортировка: deadline (по умолчанию), created_at, priority, story_points, estimate_hours"
+// @Param order query string false "Порядок: asc (по умолчанию) или desc"
+// @Param limit query int false "Размер страницы, от 1 до 200 (по умолчанию 50)"
+// @Param cursor query string false "next_cursor предыдущей страницы"
+// @Success 200 {object} response.TaskPageResponse "Страница задач"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Некорректный department_id Code: INVALID_DEPARTMENT_ID"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Некорректный приоритет: low1 Code: INVALID_PRIORITY, Error: Некорректная сортировка: title Code: INVALID_SORT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: deadline_from указывается в формате RFC 3339 Code: INVALID_DATE, Error: limit должен быть от 1 до 200 Code: INVALID_LIMIT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Курсор не подходит к запросу Code: INVALID_CURSOR"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении задач"
+// @Router /tasks [get]
+func GetTasksHandlres(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	query := storage.DB.Model(&models.Task{})
+	if c.Query("department_id") != "" {
+		teamIDs, ok := access.RequireDepartmentTeams(c, user)
+		if !ok {
+			return
+		}
+		query = query.Where("tasks.team_id IN ?", teamIDs)
+	} else {
+		teamID := user.TeamID
+		if c.Query("team_id") != "" {
+			membership, ok := access.RequireTeam(c, user, access.ViewTeam)
+			if !ok {
+				return
+			}
+			teamID = &membership.TeamID
+		}
+		// Задачи команды и персональные задачи пользователя в других командах
+		if teamID != nil {
+			query = query.Where("(tasks.team_id = ? OR tasks.assigned_to = ?)", *teamID, user.TelegramID)
+		} else {
+			query = query.Where("tasks.assigned_to = ?", user.TelegramID)
+		}
+	}
+
+	query, ok := filterTasks(c, user, query)
+	if !ok {
+		return
+	}
+	sort, ok := parseTaskSort(c)
+	if !ok {
+		return
+	}
+	limit, ok := pageSize(c)
+	if !ok {
+		return
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
+		return
+	}
+
+	page := sort.apply(query)
+	if cursor := c.Query("cursor"); cursor != "" {
+		var err error
+		if page, err = sort.after(page, cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Курсор не подходит к запросу", "code": "INVALID_CURSOR"})
+			return
+		}
+	}
+	var tasks []models.Task
+	if err := page.Limit(limit + 1).Find(&tasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
+		return
+	}
+
+	resp := response.TaskPageResponse{Total: total}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		resp.NextCursor = sort.cursor(tasks[len(tasks)-1])
+	}
+	resp.Items = toTaskResponses(tasks)
+	if resp.Items == nil {
+		resp.Items = []response.TaskResponse{}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toTaskResponses(tasks []models.Task) []response.TaskResponse {
+	progress := progressByTask(tasks)
+	subtasks := rollupProgress(tasks, progress)
+	checklists := checklistCounts(tasks)
+	blockers := blockerCounts(tasks)
+	labels := labelsByTask(tasks)
+	comments := commentCounts(tasks)
+	files := attachmentsByTask(tasks)
+	var responseTasks []response.TaskResponse
+	for _, task := range tasks {
+		responseTasks = append(responseTasks, response.TaskResponse{
+			ID:             task.ID,
+			Title:          task.Title,
+			Description:    task.Description,
+			Deadline:       task.Deadline,
+			Status:         task.Status,
+			IsTeam:         task.IsTeam,
+			AssignedTo:     task.AssignedTo,
+			CreatedBy:      task.CreatedBy,
+			TeamID:         task.TeamID,
+			DepartmentID:   task.DepartmentID,
+			CompletionRule: task.CompletionRule,
+			QuorumPercent:  task.QuorumPercent,
+			Progress:       progress[task.ID],
+			ParentID:       task.ParentID,
+			SubtaskCount:   subtasks[task.ID].total,
+			SubtasksDone:   subtasks[task.ID].done,
+			BlockedBy:      blockers[task.ID],
+			Priority:       task.Priority,
+			StoryPoints:    task.StoryPoints,
+			EstimateHours:  task.EstimateHours,
+			Labels:         labels[task.ID],
+			SeriesID:       task.SeriesID,
+			ChecklistTotal: checklists[task.ID].total,
+			ChecklistDone:  checklists[task.ID].done,
+			CommentCount:   comments[task.ID],
+			Attachments:    files[task.ID],
+		})
+	}
+	return responseTasks
+}
+
+type DepartmentTaskInput struct {
+	Title        string    `json:"title" binding:"required"`
+	Description  string    `json:"description" binding:"required"`
+	Deadline     time.Time `json:"deadline" binding:"required"` //RFC 3339
+	DepartmentID uint      `json:"department_id" binding:"required"`
+	TeamIDs      []uint    `json:"team_ids"` // Команды отдела; если не указаны — все активные команды отдела и вложенных отделов
+	// Когда задача команды считается выполненной: all (по умолчанию), any или quorum
+	CompletionRule string `json:"completion_rule" binding:"omitempty,oneof=all any quorum"`
+	QuorumPercent  int    `json:"quorum_percent" binding:"min=0,max=100"`
+	// Приоритет: low, medium (по умолчанию), high или urgent
+	Priority      string   `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	StoryPoints   *int     `json:"story_points" binding:"omitempty,min=0"`
+	EstimateHours *float64 `json:"estimate_hours" binding:"omitempty,min=0"`
+}
+
+// CreateDepartmentTaskHandler ставит задачу сразу нескольким командам отдела
+// @Summary Создание межкомандной задачи
+// @Description Руководитель отдела или владелец организации ставит командную задачу выбранным командам отдела и вложенных отделов. Каждая команда получает свою копию задачи с отметкой отдела, участники команд получают уведомление. Архивные команды пропускаются, если не указаны явно.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param task body DepartmentTaskInput true "Информация задачи"
+// @Success 200 {array} response.TaskResponse "Созданные задачи"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Команда не входит в отдел Code: TEAM_NOT_IN_DEPARTMENT, Error: В отделе нет команд Code: DEPARTMENT_HAS_NO_TEAMS"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель отдела или владелец организации может выполнить это действие Code: NOT_DEPARTMENT_HEAD"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отдел не найден Code: DEPARTMENT_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании задачи"
+// @Router /tasks/department [post]
+func CreateDepartmentTaskHandler(c *gin.Context) {
+	var input DepartmentTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	department, ok := access.RequireDepartment(c, user, input.DepartmentID)
+	if !ok {
+		return
+	}
+
+	departmentTeams, err := access.DepartmentTeamIDs(department)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+		return
+	}
+
+	var teams []models.Team
+	query := storage.DB.Where("id IN ?", departmentTeams)
+	if len(input.TeamIDs) > 0 {
+		for _, id := range input.TeamIDs {
+			if !slices.Contains(departmentTeams, id) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Команда %d не входит в отдел", id), "code": "TEAM_NOT_IN_DEPARTMENT"})
+				return
+			}
+		}
+		query = query.Where("id IN ?", input.TeamIDs)
+	} else {
+		query = query.Where("archived_at IS NULL")
+	}
+	if err := query.Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+		return
+	}
+	if len(teams) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "В отделе нет команд", "code": "DEPARTMENT_HAS_NO_TEAMS"})
+		return
+	}
+	for _, team := range teams {
+		if team.ArchivedAt != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Команда %s в архиве, доступен только просмотр", team.Name), "code": "TEAM_ARCHIVED"})
+			return
+		}
+	}
+
+	rule, quorum := completionRule(input.CompletionRule, input.QuorumPercent)
+	tasks := make([]models.Task, 0, len(teams))
+	flows := make(map[uint]*workflow.Machine, len(teams))
+	for _, team := range teams {
+		flow, err := workflow.ForTeam(storage.DB, team.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+			return
+		}
+		flows[team.ID] = flow
+		tasks = append(tasks, models.Task{
+			Title:          input.Title,
+			Description:    input.Description,
+			Deadline:       input.Deadline,
+			Status:         flow.Initial(),
+			IsTeam:         true,
+			TeamID:         team.ID,
+			CreatedBy:      user.ID,
+			DepartmentID:   &department.ID,
+			CompletionRule: rule,
+			QuorumPercent:  quorum,
+			Priority:       taskPriority(input.Priority),
+			StoryPoints:    input.StoryPoints,
+			EstimateHours:  input.EstimateHours,
+		})
+	}
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tasks).Error; err != nil {
+			return err
+		}
+		for i := range tasks {
+			if err := recordEvent(tx, models.TaskEvent{TaskID: tasks[i].ID, Type: EventCreated, ActorID: user.ID, ToStatus: tasks[i].Status, TaskStatus: tasks[i].Status}); err != nil {
+				return err
+			}
+			if err := createAssignments(tx, &tasks[i], flows[tasks[i].TeamID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи"})
+		return
+	}
+
+	notificationText := fmt.Sprintf(
+		"🚀 *Новая задача отдела %s!*\n\n"+
+			"▫️ *Заголовок:* %s\n"+
+			"▫️ *Описание:* \n_%s_\n"+
+			"▫️ *Дедлайн:* %s\n"+
+			"▫️ *Приоритет:* %s\n"+
+			"▫️ *Тип:* Общая задача нескольких команд\n\n"+
+			"🕑 Создано: %s",
+		department.Name,
+		input.Title,
+		input.Description,
+		notification.FormatDeadline(input.Deadline),
+		priorityTitle(taskPriority(input.Priority)),
+		time.Now().Format("02.01.2006 15:04"),
+	)
+	for _, task := range tasks {
+		teamUsers, err := access.TeamUsers(task.TeamID)
+		if err != nil {
+			fmt.Printf("Ошибка получения участников команды: %v\n", err)
+		}
+		for _, u := range teamUsers {
+			if u.TelegramID != "" {
+				go func(chatID string) {
+					if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+						fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+					}
+				}(u.TelegramID)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, toTaskResponses(tasks))
+}
+
+// requireTaskViewer проверяет, что пользователь видит задачу: это её автор, руководитель отдела команды
+// или участник команды. С write дополнительно проверяется, что команда не в архиве.
+// При отказе пишет ответ и возвращает false.
+func requireTaskViewer(c *gin.Context, user *models.User, task *models.Task, write bool) bool {
+	if task.CreatedBy != user.ID && !access.OverseesTeam(user.ID, task.TeamID) {
+		if _, ok := access.RequireRead(c, user, task.TeamID, access.ViewTeam); !ok {
+			return false
+		}
+	}
+	return !write || access.RequireActive(c, task.TeamID)
+}
+
+// requireTaskAuthor проверяет, что пользователь может изменять и удалять задачу: это её автор,
+// оставшийся руководителем команды, или владелец команды. При отказе пишет ответ и возвращает false.
+func requireTaskAuthor(c *gin.Context, user *models.User, task *models.Task) bool {
+	if denial := checkTaskAuthor(user, task); denial != nil {
+		denial.Write(c)
+		return false
+	}
+	return true
+}
+
+// checkTaskAuthor выполняет проверку requireTaskAuthor, не записывая ответ.
+func checkTaskAuthor(user *models.User, task *models.Task) *access.Denial {
+	// Межкомандную задачу может менять поставивший её руководитель отдела, даже не состоя в команде
+	if task.DepartmentID != nil && task.CreatedBy == user.ID && access.OverseesTeam(user.ID, task.TeamID) {
+		return access.CheckActive(task.TeamID)
+	}
+
+	membership, denial := access.Check(user, task.TeamID, access.ManageTasks)
+	if denial != nil {
+		return denial
+	}
+
+	if task.CreatedBy != user.ID && membership.Role != access.RoleOwner {
+		return &access.Denial{Status: http.StatusForbidden, Message: "Задачу создали не вы"}
+	}
+	return nil
+}
+
+type UpdateTaskInput struct {
+	Title          *string    `json:"title"`
+	Description    *string    `json:"description"`
+	Deadline       *time.Time `json:"deadline"` //RFC 3339
+	IsTeam         *bool      `json:"is_team"`
+	AssignedTo     *string    `json:"assigned_to"` // Telegram ID исполнителя персональной задачи
+	CompletionRule *string    `json:"completion_rule" binding:"omitempty,oneof=all any quorum"`
+	QuorumPercent  *int       `json:"quorum_percent" binding:"omitempty,min=1,max=100"`
+	ParentID       *uint      `json:"parent_id"` // Родительская задача; 0 — сделать задачу самостоятельной
+	Priority       *string    `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	StoryPoints    *int       `json:"story_points" binding:"omitempty,min=-1"`   // -1 — убрать оценку
+	EstimateHours  *float64   `json:"estimate_hours" binding:"omitempty,min=-1"` // -1 — убрать оценку
+	LabelIDs       *[]uint    `json:"label_ids"`                                 // Новый набор меток; пустой список убирает все метки
+}
+
+// taskChange — изменение одного поля задачи в читаемом виде.
+type taskChange struct {
+	field, old, new string
+}
+
+// assigneeTitle возвращает имя исполнителя задачи для уведомления.
+func assigneeTitle(isTeam bool, assignedTo *string) string {
+	if isTeam || assignedTo == nil {
+		return "вся команда"
+	}
+	var u models.User
+	if err := storage.DB.Where("telegram_id = ?", *assignedTo).First(&u).Error; err != nil {
+		return *assignedTo
+	}
+	return u.Name
+}
+
+// diffTask сравнивает задачу до и после изменения.
+func diffTask(before, after models.Task) []taskChange {
+	var changes []taskChange
+	if before.Title != after.Title {
+		changes = append(changes, taskChange{"Заголовок", before.Title, after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, taskChange{"Описание", before.Description, after.Description})
+	}
+	if !before.Deadline.Equal(after.Deadline) {
+		changes = append(changes, taskChange{
+			"Дедлайн",
+			before.Deadline.Format("02.01.2006 15:04"),
+			after.Deadline.Format("02.01.2006 15:04"),
+		})
+	}
+	if before.IsTeam && after.IsTeam && (before.CompletionRule != after.CompletionRule || before.QuorumPercent != after.QuorumPercent) {
+		changes = append(changes, taskChange{"Правило выполнения", ruleTitle(before), ruleTitle(after)})
+	}
+	if !equalIDs(before.ParentID, after.ParentID) {
+		changes = append(changes, taskChange{"Родительская задача", parentTitle(before.ParentID), parentTitle(after.ParentID)})
+	}
+	if before.Priority != after.Priority {
+		changes = append(changes, taskChange{"Приоритет", priorityTitle(before.Priority), priorityTitle(after.Priority)})
+	}
+	if beforeEstimate, afterEstimate := estimateTitle(before.StoryPoints, before.EstimateHours), estimateTitle(after.StoryPoints, after.EstimateHours); beforeEstimate != afterEstimate {
+		changes = append(changes, taskChange{"Оценка", beforeEstimate, afterEstimate})
+	}
+	beforeAssignee := assigneeTitle(before.IsTeam, before.AssignedTo)
+	afterAssignee := assigneeTitle(after.IsTeam, after.AssignedTo)
+	if beforeAssignee != afterAssignee {
+		changes = append(changes, taskChange{"Исполнитель", beforeAssignee, afterAssignee})
+	}
+	return changes
+}
+
+func equalIDs(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// parentTitle возвращает заголовок родительской задачи для списка изменений.
+func parentTitle(id *uint) string {
+	if id == nil {
+		return "нет"
+	}
+	var parent models.Task
+	if err := storage.DB.Unscoped().First(&parent, *id).Error; err != nil {
+		return fmt.Sprintf("#%d", *id)
+	}
+	return parent.Title
+}
+
+// ruleTitle описывает правило выполнения командной задачи.
+func ruleTitle(task models.Task) string {
+	switch task.CompletionRule {
+	case CompletionAny:
+		return "любой участник"
+	case CompletionQuorum:
+		return fmt.Sprintf("%d%% участников", task.QuorumPercent)
+	default:
+		return "все участники"
+	}
+}
+
+// taskAudience возвращает Telegram ID пользователей, которых касается задача.
+func taskAudience(task models.Task) []string {
+	if !task.IsTeam {
+		if task.AssignedTo == nil {
+			return nil
+		}
+		return []string{*task.AssignedTo}
+	}
+
+	teamUsers, err := access.TeamUsers(task.TeamID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участников команды: %v\n", err)
+	}
+	ids := make([]string, 0, len(teamUsers))
+	for _, u := range teamUsers {
+		ids = append(ids, u.TelegramID)
+	}
+	return ids
+}
+
+// UpdateTaskHandler изменяет задачу
+// @Summary Изменение задачи
+// @Description Изменяет заголовок, описание, дедлайн, исполнителя, родительскую задачу, приоритет, оценку, метки или переключает задачу между командной и персональной. Передаются только изменяемые поля. Срок подзадачи не может быть позже срока родительской задачи, а срок задачи — раньше сроков её подзадач. Участники, которых касалась задача до или после изменения, получают одно уведомление со списком изменений. Доступно автору задачи и владельцу команды.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param task body UpdateTaskInput true "Изменяемые поля задачи"
+// @Success 200 {object} response.TaskUpdateResponse "Задача и список изменений"
+// @Failure 400 {object} response.ErrorCodeResponse "assigned_to обязателен для персональных задач, Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Задача не может быть подзадачей самой себя или своей подзадачи Code: PARENT_TASK_CYCLE"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Срок задачи не может быть раньше срока её подзадач (25.03.2025 15:00) Code: DEADLINE_BEFORE_SUBTASKS"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Метка не найдена в команде Code: LABEL_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении задачи"
+// @Router /tasks/{id} [put]
+func UpdateTaskHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	if !requireTaskAuthor(c, user, &task) {
+		return
+	}
+
+	var input UpdateTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := task
+	if input.Title != nil {
+		if *input.Title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Заголовок не может быть пустым"})
+			return
+		}
+		task.Title = *input.Title
+	}
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+	if input.Deadline != nil {
+		task.Deadline = *input.Deadline
+	}
+	if input.IsTeam != nil {
+		task.IsTeam = *input.IsTeam
+	}
+	if input.AssignedTo != nil {
+		task.AssignedTo = input.AssignedTo
+	}
+	if input.CompletionRule != nil {
+		task.CompletionRule = *input.CompletionRule
+	}
+	if input.QuorumPercent != nil {
+		task.QuorumPercent = *input.QuorumPercent
+	}
+	if input.ParentID != nil {
+		if *input.ParentID == 0 {
+			task.ParentID = nil
+		} else {
+			task.ParentID = input.ParentID
+		}
+	}
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+	if input.StoryPoints != nil {
+		if *input.StoryPoints < 0 {
+			task.StoryPoints = nil
+		} else {
+			task.StoryPoints = input.StoryPoints
+		}
+	}
+	if input.EstimateHours != nil {
+		if *input.EstimateHours < 0 {
+			task.EstimateHours = nil
+		} else {
+			task.EstimateHours = input.EstimateHours
+		}
+	}
+
+	if task.IsTeam {
+		task.AssignedTo = nil
+	} else {
+		if task.AssignedTo == nil || *task.AssignedTo == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "assigned_to обязателен для персональных задач"})
+			return
+		}
+		if before.AssignedTo == nil || *before.AssignedTo != *task.AssignedTo {
+			var assignee models.User
+			if err := storage.DB.Where("telegram_id = ?", *task.AssignedTo).First(&assignee).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+				return
+			}
+			if _, err := access.GetMembership(assignee.ID, task.TeamID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+				return
+			}
+		}
+	}
+
+	// Срок подзадачи должен укладываться в срок родителя, а срок задачи — вмещать сроки подзадач
+	if task.ParentID != nil && (!equalIDs(before.ParentID, task.ParentID) || !before.Deadline.Equal(task.Deadline)) {
+		_, err := checkParent(storage.DB, &task, *task.ParentID)
+		if writeParentError(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+			return
+		}
+	}
+	if task.Deadline.Before(before.Deadline) {
+		err := checkSubtaskDeadlines(storage.DB, &task)
+		if writeParentError(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+			return
+		}
+	}
+
+	changes := diffTask(before, task)
+	var labels []models.Label
+	if input.LabelIDs != nil {
+		var err error
+		if labels, err = teamLabels(storage.DB, task.TeamID, *input.LabelIDs); err != nil {
+			if errors.Is(err, errLabelNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Метка не найдена в команде", "code": "LABEL_NOT_FOUND"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+			return
+		}
+		var current []models.Label
+		if err := storage.DB.Joins("JOIN task_labels ON task_labels.label_id = labels.id").
+			Where("task_labels.task_id = ?", task.ID).
+			Order("labels.name").
+			Find(&current).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+			return
+		}
+		if beforeLabels, afterLabels := labelNames(current), labelNames(labels); beforeLabels != afterLabels {
+			changes = append(changes, taskChange{"Метки", beforeLabels, afterLabels})
+		}
+	}
+	resp := response.TaskUpdateResponse{Changes: make([]response.TaskChangeResponse, 0, len(changes))}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, response.TaskChangeResponse{Field: change.field, Old: change.old, New: change.new})
+	}
+	if len(changes) == 0 {
+		resp.Task = toTaskResponses([]models.Task{task})[0]
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	flow, err := workflow.ForTeam(storage.DB, task.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+		return
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		switch {
+		case task.IsTeam && !before.IsTeam:
+			if err := createAssignments(tx, &task, flow); err != nil {
+				return err
+			}
+		case !task.IsTeam && before.IsTeam:
+			if err := tx.Where("task_id = ?", task.ID).Delete(&models.TaskAssignment{}).Error; err != nil {
+				return err
+			}
+		}
+		if task.IsTeam {
+			// Правило выполнения могло измениться — пересчитываем общий статус
+			assignments, err := currentAssignments(tx, &task)
+			if err != nil {
+				return err
+			}
+			task.Status = aggregateStatus(&task, assignments, flow)
+		}
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if input.LabelIDs != nil {
+			if err := setTaskLabels(tx, task.ID, labels); err != nil {
+				return err
+			}
+		}
+		return recordChanges(tx, &task, user.ID, changes)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении задачи"})
+		return
+	}
+	resp.Task = toTaskResponses([]models.Task{task})[0]
+	taskStatusChanged(before.Status, task, flow)
+
+	notificationText := fmt.Sprintf("✏️ *Задача изменена*\n\n▫️ *Заголовок:* %s\n\n*Что изменилось:*\n", task.Title)
+	for _, change := range changes {
+		notificationText += fmt.Sprintf("▫️ *%s:* %s → %s\n", change.field, change.old, change.new)
+	}
+	notificationText += fmt.Sprintf("\n✍️ Изменил(а): %s", user.Name)
+
+	// Одно уведомление каждому, кого задача касалась до или после изменения
+	recipients := make(map[string]bool)
+	for _, id := range append(taskAudience(before), taskAudience(task)...) {
+		if id != "" && id != user.TelegramID {
+			recipients[id] = true
+		}
+	}
+	for chatID := range recipients {
+		go func(chatID string) {
+			if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}(chatID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteTaskHandler удаляет задачу
+// @Summary Удаление задачи
+// @Description Удаление задачи её автором или владельцем команды. Автор должен оставаться руководителем команды. Задачу с подзадачами удалить нельзя — сначала удалите подзадачи или отвяжите их.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {object} response.SuccessResponse "Задача успешно удалена"
+// @Failure 400 {object} response.ErrorResponse "Error: task_id is required CODE: NOT_TASK_ID"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 403 {object} response.ErrorResponse "Задачу создали не вы"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: У задачи есть подзадачи Code: TASK_HAS_SUBTASKS"
+// @Failure 500 {object} response.ErrorResponse "Задача не найдена"
+// @Router /tasks/{id} [delete]
+func DeleteTaskHandler(c *gin.Context) {
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id is required", "code": "NOT_TASK_ID"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, taskID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	if !requireTaskAuthor(c, user, &task) {
+		return
+	}
+
+	var subtasks int64
+	if err := storage.DB.Model(&models.Task{}).Where("parent_id = ?", task.ID).Count(&subtasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления задачи"})
+		return
+	}
+	if subtasks > 0 {
+		errTaskHasSubtasks.Write(c)
+		return
+	}
+	dependents, err := dependentTasks(task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления задачи"})
+		return
+	}
+
+	var notificationText string
+	if task.IsTeam {
+		notificationText = fmt.Sprintf(
+			"🚀 *Командная задача отменена!*\n\n"+
+				"▫️ *Заголовок:* %s\n"+
+				"▫️ *Описание:* \n_%s_\n",
+			task.Title,
+			task.Description,
+		)
+		teamUsers, err := access.TeamUsers(task.TeamID)
+		if err != nil {
+			// Логирование ошибки, но можно продолжать отправку уведомлений тому, кого удалось найти
+			fmt.Printf("Ошибка получения участников команды: %v\n", err)
+		}
+		for _, u := range teamUsers {
+			if u.TelegramID != "" {
+				go func(chatID string) {
+					if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+						fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+					}
+				}(u.TelegramID)
+			}
+		}
+	} else {
+		notificationText = fmt.Sprintf(
+			"🚀 *Вашу задача отменили!*\n\n"+
+				"▫️ *Заголовок:* %s\n"+
+				"▫️ *Описание:* \n_%s_\n"+
+				(task.Title),
+			(task.Description),
+		)
+		// Задачу удаляем, даже если исполнителя уже нет: уведомлять просто некого
+		var assignedUser models.User
+		if err := storage.DB.Where("telegram_id = ?", task.AssignedTo).First(&assignedUser).Error; err != nil {
+			fmt.Printf("Пользователь не найден: %v\n", err)
+		}
+
+		if assignedUser.TelegramID != "" {
+			if err := notification.SendTelegramNotification(assignedUser.TelegramID, notificationText); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", assignedUser.TelegramID, err)
+			}
+		}
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		return deleteTask(tx, &task, user.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления задачи"})
+		return
+	}
+	notifyUnblocked(dependents, "Удалена последняя блокирующая задача: "+task.Title)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно удалена"})
+}
+
+// errTaskHasSubtasks — задачу с подзадачами удалить нельзя.
+var errTaskHasSubtasks = &access.Denial{Status: http.StatusConflict, Message: "У задачи есть подзадачи", Code: "TASK_HAS_SUBTASKS"}
+
+// deleteTask удаляет задачу вместе с её зависимостями и метками и записывает удаление в историю.
+func deleteTask(tx *gorm.DB, task *models.Task, actorID uint) error {
+	if err := recordEvent(tx, models.TaskEvent{TaskID: task.ID, Type: EventDeleted, ActorID: actorID, FromStatus: task.Status, TaskStatus: task.Status}); err != nil {
+		return err
+	}
+	if err := tx.Where("task_id = ? OR blocker_id = ?", task.ID, task.ID).Delete(&models.TaskDependency{}).Error; err != nil {
+		return err
+	}
+	if err := setTaskLabels(tx, task.ID, nil); err != nil {
+		return err
+	}
+	return tx.Delete(task).Error
+}
+
+type UpdateTaskStatusInput struct {
+	Status         string `json:"status" binding:"required"` // Статус из процесса команды, например "in_progress" или "completed" (для исполнителя — отправка на проверку)
+	CompletionText string `json:"completion_text"`           // Отчёт по выполнению (опционально)
+	Attachment     string `json:"attachment"`                // Ссылка на файл или описание вложения (опционально)
+	AttachmentIDs  []uint `json:"attachment_ids"`            // Файлы, загруженные к задаче через POST /tasks/{id}/attachments, — прикладываются к работе на проверке
+}
+
+// UpdateTaskStatusHandler обновляет статус задачи.
+// Переход проверяется процессом команды; работа в статусе проверки отправляется автору задачи.
+// UpdateTaskStatusHandler обновляет статус задачи
+// @Summary Обновление статуса задачи
+// @Description Обновление статуса задачи участником команды или исполнителем персональной задачи.
+// @Description Допустимые статусы и переходы задаёт процесс команды (GET /team/workflow): переход должен быть в процессе и разрешён роли пользователя.
+// @Description Завершающий статус (completed в стандартном процессе) отправляет работу на проверку, если из текущего статуса есть переход в статус проверки: автор получает уведомление и принимает или отклоняет работу через /tasks/reviews/{id}/approve и /tasks/reviews/{id}/reject. Автор собственной задачи завершает её сразу.
+// @Description У командной задачи меняется статус текущего участника, а общий статус задачи пересчитывается по правилу completion_rule: all — выполнена, когда выполнили все участники, any — любой, quorum — не меньше quorum_percent участников.
+// @Description Задачу с незавершёнными блокирующими задачами (GET /tasks/{id}/dependencies) нельзя вывести из начального статуса. Когда завершается последняя блокирующая задача, исполнители зависимой задачи получают уведомление.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param task body UpdateTaskStatusInput true "Данные для обновления статуса"
+// @Success 200 {object} response.SuccessResponse "Статус задачи успешно обновлен"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: task_id is required CODE: NOT_TASK_ID"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Статус done не входит в процесс команды Code: UNKNOWN_STATUS"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Файл не найден среди ваших вложений к задаче Code: ATTACHMENT_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorResponse "У вас нет прав для изменения статуса этой задачи"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Работа уже на проверке у автора задачи Code: TASK_IN_REVIEW"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Задачу нельзя начать, пока не завершены: Макет Code: TASK_BLOCKED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Работа по задаче уже принята Code: TASK_ALREADY_COMPLETED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении статуса задачи"
+// @Router /tasks/{id}/status [put]
+func UpdateTaskStatusHandler(c *gin.Context) {
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id is required"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+
+	// Поиск задачи по ID
+	var task models.Task
+	if err := storage.DB.First(&task, taskID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	role, denial := statusWorkerRole(user, &task)
+	if denial != nil {
+		denial.Write(c)
+		return
+	}
+
+	// Считываем данные из запроса
+	var input UpdateTaskStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flow, err := workflow.ForTeam(storage.DB, task.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении процесса команды"})
+		return
+	}
+
+	// Обновляем статус в БД. У командной задачи меняется статус участника,
+	// а общий статус пересчитывается по правилу выполнения.
+	previous := task.Status
+	var current, status string
+	var review *models.TaskReview
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		current, status, review, err = changeTaskStatus(tx, &task, flow, user, role, input)
+		return err
+	})
+	if denial := statusDenial(err, flow, current, status); denial != nil {
+		denial.Write(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении статуса задачи"})
+		return
+	}
+	taskStatusChanged(previous, task, flow)
+
+	// Работа отправлена на проверку — уведомляем автора задачи.
+	if review != nil {
+		review.User = *user
+		notifyReviewSubmitted(task, *review, flow)
+		c.JSON(http.StatusOK, gin.H{"message": "Работа отправлена на проверку автору задачи"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Статус задачи успешно обновлен"})
+}
+
+// statusWorkerRole проверяет, что пользователь может менять статус задачи: у персональной задачи это
+// назначенный исполнитель, у командной — участник, роль которого позволяет работать с задачами.
+// Возвращает роль пользователя в команде.
+func statusWorkerRole(user *models.User, task *models.Task) (string, *access.Denial) {
+	if !task.IsTeam {
+		if task.AssignedTo == nil || *task.AssignedTo != user.TelegramID {
+			return "", &access.Denial{Status: http.StatusForbidden, Message: "У вас нет прав для изменения статуса этой задачи"}
+		}
+		if denial := access.CheckActive(task.TeamID); denial != nil {
+			return "", denial
+		}
+		return access.RoleIn(user.ID, &task.TeamID), nil
+	}
+
+	membership, denial := access.Check(user, task.TeamID, access.WorkOnTasks)
+	if denial != nil {
+		return "", denial
+	}
+	return membership.Role, nil
+}
+
+// changeTaskStatus меняет статус работы пользователя по задаче и записывает событие в историю.
+// У командной задачи меняется статус участника, а общий статус пересчитывается по правилу выполнения.
+// Возвращает прежний и новый статус работы, а если работа отправлена на проверку — созданную проверку.
+func changeTaskStatus(tx *gorm.DB, task *models.Task, flow *workflow.Machine, user *models.User, role string, input UpdateTaskStatusInput) (current, status string, review *models.TaskReview, err error) {
+	status = input.Status
+	if current, err = workStatus(tx, task, user.ID, flow); err != nil {
+		return current, status, nil, err
+	}
+	if flow.IsReview(current) {
+		return current, status, nil, errWorkInReview
+	}
+	// Пока блокирующие задачи не завершены, задачу нельзя начать
+	if current == flow.Initial() && status != current {
+		blockers, err := openBlockers(tx, task.ID)
+		if err != nil {
+			return current, status, nil, err
+		}
+		if len(blockers) > 0 {
+			return current, status, nil, errTaskBlocked{blockers: blockers}
+		}
+	}
+
+	// Исполнитель не завершает задачу сам, а отправляет работу на проверку автору.
+	// Автор, выполняющий собственную задачу, завершает её без проверки.
+	if flow.IsTerminal(status) && task.CreatedBy != user.ID {
+		if target := flow.ReviewTarget(current, role); target != "" {
+			status = target
+		}
+	}
+	if err := flow.Check(current, status, role); err != nil {
+		return current, status, nil, err
+	}
+
+	if err := setWorkStatus(tx, task, flow, user.ID, status, input.CompletionText); err != nil {
+		return current, status, nil, err
+	}
+	if err := recordEvent(tx, models.TaskEvent{
+		TaskID:         task.ID,
+		Type:           EventStatusChanged,
+		ActorID:        user.ID,
+		UserID:         &user.ID,
+		FromStatus:     current,
+		ToStatus:       status,
+		TaskStatus:     task.Status,
+		CompletionText: input.CompletionText,
+		Attachment:     input.Attachment,
+	}); err != nil {
+		return current, status, nil, err
+	}
+	if !flow.IsReview(status) {
+		return current, status, nil, nil
+	}
+	review = &models.TaskReview{
+		TaskID:         task.ID,
+		UserID:         user.ID,
+		CompletionText: input.CompletionText,
+		Attachment:     input.Attachment,
+		Status:         ReviewPending,
+	}
+	if err := tx.Create(review).Error; err != nil {
+		return current, status, nil, err
+	}
+	if err := claimAttachments(tx, task.ID, user.ID, input.AttachmentIDs, "review_id", review.ID); err != nil {
+		return current, status, nil, err
+	}
+	return current, status, review, nil
+}
+
+// statusDenial переводит ошибку смены статуса в отказ для ответа API. Возвращает nil, если ошибка другая.
+func statusDenial(err error, flow *workflow.Machine, from, to string) *access.Denial {
+	var blocked errTaskBlocked
+	switch {
+	case errors.Is(err, errWorkInReview):
+		return &access.Denial{Status: http.StatusConflict, Message: "Работа уже на проверке у автора задачи", Code: "TASK_IN_REVIEW"}
+	case errors.Is(err, errAttachmentNotFound):
+		return &access.Denial{Status: http.StatusBadRequest, Message: "Файл не найден среди ваших вложений к задаче", Code: "ATTACHMENT_NOT_FOUND"}
+	case errors.As(err, &blocked):
+		return &access.Denial{Status: http.StatusConflict, Message: blocked.Error(), Code: "TASK_BLOCKED"}
+	}
+	return transitionDenial(err, flow, from, to)
+}
+
+// writeTransitionError отвечает на ошибку проверки перехода процесса команды. Возвращает false, если ошибка другая.
+func writeTransitionError(c *gin.Context, err error, flow *workflow.Machine, from, to string) bool {
+	denial := transitionDenial(err, flow, from, to)
+	if denial == nil {
+		return false
+	}
+	denial.Write(c)
+	return true
+}
+
+// transitionDenial переводит ошибку проверки перехода процесса команды в отказ. Возвращает nil, если ошибка другая.
+func transitionDenial(err error, flow *workflow.Machine, from, to string) *access.Denial {
+	switch {
+	case errors.Is(err, workflow.ErrUnknownStatus):
+		return &access.Denial{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("Статус %s не входит в процесс команды. Допустимые значения: %s", to, strings.Join(flow.Keys(), ", ")),
+			Code:    "UNKNOWN_STATUS",
+		}
+	case errors.Is(err, workflow.ErrTransitionNotAllowed) && flow.IsTerminal(from):
+		return &access.Denial{Status: http.StatusConflict, Message: "Работа по задаче уже принята", Code: "TASK_ALREADY_COMPLETED"}
+	case errors.Is(err, workflow.ErrTransitionNotAllowed):
+		return &access.Denial{
+			Status:  http.StatusConflict,
+			Message: fmt.Sprintf("Переход «%s» → «%s» не предусмотрен процессом команды", flow.Title(from), flow.Title(to)),
+			Code:    "TRANSITION_NOT_ALLOWED",
+		}
+	case errors.Is(err, workflow.ErrRoleNotAllowed):
+		return &access.Denial{
+			Status:  http.StatusForbidden,
+			Message: fmt.Sprintf("Ваша роль не позволяет перевести задачу в статус «%s»", flow.Title(to)),
+			Code:    "TRANSITION_FORBIDDEN",
+		}
+	}
+	return nil
+}
+
+// toTaskReviewResponse собирает ответ по работе на проверке; Task, User, Reviewer и Attachments должны быть загружены.
+func toTaskReviewResponse(review models.TaskReview) response.TaskReviewResponse {
+	resp := response.TaskReviewResponse{
+		ID:             review.ID,
+		TaskID:         review.TaskID,
+		TaskTitle:      review.Task.Title,
+		TelegramID:     review.User.TelegramID,
+		Name:           review.User.Name,
+		CompletionText: review.CompletionText,
+		Attachment:     review.Attachment,
+		Status:         review.Status,
+		Comment:        review.Comment,
+		Attachments:    toAttachmentResponses(review.Attachments),
+		CreatedAt:      review.CreatedAt,
+		DecidedAt:      review.DecidedAt,
+	}
+	if review.Reviewer != nil {
+		resp.ReviewerTelegramID = review.Reviewer.TelegramID
+	}
+	return resp
+}
+
+// reviewHistoryLimit — сколько последних событий истории показывать проверяющему.
+const reviewHistoryLimit = 5
+
+// notifyReviewSubmitted отправляет автору задачи работу на проверку с кнопками решения.
+func notifyReviewSubmitted(task models.Task, review models.TaskReview, flow *workflow.Machine) {
+	var author models.User
+	if err := storage.DB.First(&author, task.CreatedBy).Error; err != nil || author.TelegramID == "" {
+		return
+	}
+
+	text := fmt.Sprintf(
+		"📨 *Работа отправлена на проверку*\n\n▫️ *Заголовок:* %s\n▫️ *Описание:* %s\n▫️ *Исполнитель:* %s\n\n*Отчет участника:*\n%s",
+		task.Title,
+		task.Description,
+		review.User.Name,
+		review.CompletionText,
+	)
+	if review.Attachment != "" {
+		text += fmt.Sprintf("\n▫️ *Вложение:* %s", review.Attachment)
+	}
+	var files []models.Attachment
+	if err := storage.DB.Where("review_id = ?", review.ID).Order("id").Find(&files).Error; err == nil && len(files) > 0 {
+		text += fmt.Sprintf("\n▫️ *Файлы:* %s", attachmentNames(files))
+	}
+	if task.IsTeam {
+		text += fmt.Sprintf("\n\n📊 *Прогресс команды:* %d%%", progressByTask([]models.Task{task})[task.ID])
+	}
+	if history := historySummary(task.ID, flow, reviewHistoryLimit); history != "" {
+		text += "\n\n🕘 *История задачи:*\n" + history
+	}
+
+	buttons := []notification.InlineButton{
+		{Text: "✅ Принять", CallbackData: fmt.Sprintf("approve_review_%d", review.ID)},
+		{Text: "↩️ Вернуть", CallbackData: fmt.Sprintf("reject_review_%d", review.ID)},
+	}
+	go func(chatID string) {
+		if err := notification.SendTelegramNotificationWithButtons(chatID, text, buttons); err != nil {
+			fmt.Printf("Ошибка отправки уведомления автору задачи %s: %v\n", chatID, err)
+		}
+	}(author.TelegramID)
+}
+
+// notifyReviewDecision сообщает исполнителю, принята ли его работа.
+func notifyReviewDecision(task models.Task, review models.TaskReview, reviewer *models.User, flow *workflow.Machine) {
+	if review.User.TelegramID == "" {
+		return
+	}
+
+	var text string
+	if review.Status == ReviewApproved {
+		text = fmt.Sprintf("✅ *Работа принята*\n\n▫️ *Задача:* %s\n▫️ *Проверил:* %s", task.Title, reviewer.Name)
+		if task.IsTeam && flow.IsTerminal(task.Status) {
+			text += "\n🏁 Задача выполнена командой"
+		}
+	} else {
+		text = fmt.Sprintf("↩️ *Работа возвращена на доработку*\n\n▫️ *Задача:* %s\n▫️ *Проверил:* %s", task.Title, reviewer.Name)
+	}
+	if review.Comment != "" {
+		text += fmt.Sprintf("\n\n*Комментарий:*\n%s", review.Comment)
+	}
+
+	go func(chatID string) {
+		if err := notification.SendTelegramNotification(chatID, text); err != nil {
+			fmt.Printf("Ошибка отправки уведомления исполнителю %s: %v\n", chatID, err)
+		}
+	}(review.User.TelegramID)
+}
+
+// GetTaskReviewsHandler возвращает работы по задачам пользователя, отправленные на проверку
+// @Summary Работы на проверке
+// @Description Возвращает работы исполнителей по задачам, которые создал текущий пользователь. По умолчанию — только ожидающие решения.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param status query string false "pending (по умолчанию), approved, rejected или all"
+// @Success 200 {array} response.TaskReviewResponse "Работы на проверке"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Некорректный статус Code: INVALID_STATUS"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении работ на проверке"
+// @Router /tasks/reviews [get]
+func GetTaskReviewsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	status := c.DefaultQuery("status", ReviewPending)
+	if !slices.Contains([]string{ReviewPending, ReviewApproved, ReviewRejected, "all"}, status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный статус", "code": "INVALID_STATUS"})
+		return
+	}
+
+	query := storage.DB.Preload("Task").Preload("User").Preload("Reviewer").Preload("Attachments.Uploader").
+		Joins("JOIN tasks ON tasks.id = task_reviews.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.created_by = ?", user.ID)
+	if status != "all" {
+		query = query.Where("task_reviews.status = ?", status)
+	}
+
+	var reviews []models.TaskReview
+	if err := query.Order("task_reviews.created_at DESC").Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении работ на проверке"})
+		return
+	}
+
+	resp := make([]response.TaskReviewResponse, 0, len(reviews))
+	for _, r := range reviews {
+		resp = append(resp, toTaskReviewResponse(r))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetTaskReviewHistoryHandler возвращает историю проверок задачи
+// @Summary История проверок задачи
+// @Description Возвращает все отправки работы на проверку по задаче и решения автора. Доступно автору задачи, участникам команды и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.TaskReviewResponse "История проверок"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении истории проверок"
+// @Router /tasks/{id}/reviews [get]
+func GetTaskReviewHistoryHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	var reviews []models.TaskReview
+	if err := storage.DB.Preload("User").Preload("Reviewer").Preload("Attachments.Uploader").
+		Where("task_id = ?", task.ID).
+		Order("created_at").
+		Find(&reviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении истории проверок"})
+		return
+	}
+
+	resp := make([]response.TaskReviewResponse, 0, len(reviews))
+	for _, r := range reviews {
+		r.Task = task
+		resp = append(resp, toTaskReviewResponse(r))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type ReviewDecisionInput struct {
+	Comment string `json:"comment"` // Комментарий исполнителю, обязателен при отклонении
+}
+
+// ApproveTaskReviewHandler принимает работу исполнителя
+// @Summary Принять работу
+// @Description Автор задачи принимает работу: работа переходит из статуса проверки в завершающий статус процесса команды (completed в стандартном процессе) — у персональной задачи это статус самой задачи, у командной — статус участника, после чего общий статус пересчитывается по правилу выполнения. Исполнитель получает уведомление.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "ID работы на проверке"
+// @Param input body ReviewDecisionInput false "Комментарий"
+// @Success 200 {object} response.TaskReviewResponse "Работа принята"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorResponse "Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Работа на проверке не найдена Code: REVIEW_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Решение по этой работе уже принято Code: REVIEW_ALREADY_DECIDED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении решения"
+// @Router /tasks/reviews/{id}/approve [post]
+func ApproveTaskReviewHandler(c *gin.Context) {
+	decideTaskReview(c, true)
+}
+
+// RejectTaskReviewHandler возвращает работу исполнителю на доработку
+// @Summary Вернуть работу на доработку
+// @Description Автор задачи отклоняет работу с комментарием: работа возвращается из статуса проверки в рабочий статус процесса команды (in_progress в стандартном процессе), исполнитель получает комментарий.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "ID работы на проверке"
+// @Param input body ReviewDecisionInput true "Причина отклонения"
+// @Success 200 {object} response.TaskReviewResponse "Работа возвращена на доработку"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Укажите, что нужно доработать Code: COMMENT_REQUIRED"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorResponse "Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Работа на проверке не найдена Code: REVIEW_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Решение по этой работе уже принято Code: REVIEW_ALREADY_DECIDED"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Ваша роль не позволяет перевести задачу в этот статус Code: TRANSITION_FORBIDDEN"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Переход не предусмотрен процессом команды Code: TRANSITION_NOT_ALLOWED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении решения"
+// @Router /tasks/reviews/{id}/reject [post]
+func RejectTaskReviewHandler(c *gin.Context) {
+	decideTaskReview(c, false)
+}
+
+// decideTaskReview сохраняет решение автора задачи и меняет статус работы исполнителя.
+func decideTaskReview(c *gin.Context, approve bool) {
+	user := auth.CurrentUser(c)
+
+	var input ReviewDecisionInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.Comment = strings.TrimSpace(input.Comment)
+	if !approve && input.Comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Укажите, что нужно доработать", "code": "COMMENT_REQUIRED"})
+		return
+	}
+
+	var review models.TaskReview
+	if err := storage.DB.Preload("Task").Preload("User").Preload("Attachments.Uploader").First(&review, c.Param("id")).Error; err != nil || review.Task.ID == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Работа на проверке не найдена", "code": "REVIEW_NOT_FOUND"})
+		return
+	}
+
+	task := review.Task
+	if !requireTaskAuthor(c, user, &task) {
+		return
+	}
+	if review.Status != ReviewPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Решение по этой работе уже принято", "code": "REVIEW_ALREADY_DECIDED"})
+		return
+	}
+
+	flow, err := workflow.ForTeam(storage.DB, task.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении процесса команды"})
+		return
+	}
+
+	// Решение проверяющего — тоже переход процесса: из статуса проверки в завершающий или обратно в работу.
+	role := access.RoleIn(user.ID, &task.TeamID)
+	if role == "" && access.OverseesTeam(user.ID, task.TeamID) {
+		role = access.RoleManager
+	}
+	decision := ReviewRejected
+	if approve {
+		decision = ReviewApproved
+	}
+	now := time.Now()
+
+	previous := task.Status
+	var current, status string
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if current, err = workStatus(tx, &task, review.UserID, flow); err != nil {
+			return err
+		}
+		status = flow.RejectTarget(current)
+		if approve {
+			status = flow.ApproveTarget(current)
+		}
+		if err := flow.Check(current, status, role); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.TaskReview{}).
+			Where("id = ? AND status = ?", review.ID, ReviewPending).
+			Updates(map[string]interface{}{"status": decision, "reviewer_id": user.ID, "comment": input.Comment, "decided_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errReviewStale
+		}
+		if err := setWorkStatus(tx, &task, flow, review.UserID, status, review.CompletionText); err != nil {
+			return err
+		}
+		eventType := EventReviewRejected
+		if approve {
+			eventType = EventReviewApproved
+		}
+		return recordEvent(tx, models.TaskEvent{
+			TaskID:         task.ID,
+			Type:           eventType,
+			ActorID:        user.ID,
+			UserID:         &review.UserID,
+			FromStatus:     current,
+			ToStatus:       status,
+			TaskStatus:     task.Status,
+			CompletionText: review.CompletionText,
+			Attachment:     review.Attachment,
+			Comment:        input.Comment,
+		})
+	})
+	if errors.Is(err, errReviewStale) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Решение по этой работе уже принято", "code": "REVIEW_ALREADY_DECIDED"})
+		return
+	}
+	if writeTransitionError(c, err, flow, current, status) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении решения"})
+		return
+	}
+
+	review.Task = task
+	review.Status = decision
+	review.ReviewerID = &user.ID
+	review.Reviewer = user
+	review.Comment = input.Comment
+	review.DecidedAt = &now
+	notifyReviewDecision(task, review, user, flow)
+	taskStatusChanged(previous, task, flow)
+
+	c.JSON(http.StatusOK, toTaskReviewResponse(review))
+}
+
+// @Summary Получить выданные задачи
+// @Description Возвращает список задач, созданных текущим пользователем.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param status query string false "Статусы через запятую"
+// @Param priority query string false "Приоритеты через запятую: low, medium, high, urgent"
+// @Param label query string false "ID меток через запятую: задачи хотя бы с одной из них"
+// @Param assignee query string false "Telegram ID исполнителя"
+// @Param deadline_from query string false "Срок не раньше (RFC 3339)"
+// @Param deadline_to query string false "Срок не позже (RFC 3339)"
+// @Param overdue query bool false "Только просроченные незавершённые задачи"
+// @Param unestimated query bool false "Только задачи без оценки"
+// @Param sort query string false "Сортировка: deadline (по умолчанию), created_at, priority, story_points, estimate_hours"
+// @Param order query string false "Порядок: asc (по умолчанию) или desc"
+// @Success 200 {array} response.TaskResponse "Список выданных задач"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Некорректный приоритет: low1 Code: INVALID_PRIORITY, Error: Некорректная сортировка: title Code: INVALID_SORT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: deadline_from указывается в формате RFC 3339 Code: INVALID_DATE"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении задач"
+// @Router /tasks/issued [get]
+func IssuedTaskHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	query, ok := filterTasks(c, user, storage.DB.Where("tasks.created_by = ?", user.ID))
+	if !ok {
+		return
+	}
+	sort, ok := parseTaskSort(c)
+	if !ok {
+		return
+	}
+	var tasks []models.Task
+	if err := sort.apply(query).Find(&tasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
+		return
+	}
+
+	// Формирование уведомления для Telegram
+	// var notificationText string
+	// if len(tasks) == 0 {
+	// 	notificationText = "ℹ️ *Нет выданных задач*"
+	// } else {
+	// 	notificationText = "📋 *Список выданных задач:*\n\n"
+	// 	for i, task := range tasks {
+	// 		notificationText += fmt.Sprintf(
+	// 			"%d. *%s*\n▫️ Описание: _%s_\n▫️ Дедлайн: %s\n▫️ Статус: %s\n\n",
+	// 			i+1,
+	// 			task.Title,
+	// 			task.Description,
+	// 			notification.FormatDeadline(task.Deadline),
+	// 			task.Status,
+	// 		)
+	// 	}
+	// }
+
+	// // Асинхронная отправка уведомления
+	// if user.TelegramID != "" {
+	// 	go func() {
+	// 		if err := notification.SendTelegramNotification(user.TelegramID, notificationText); err != nil {
+	// 			fmt.Printf("Ошибка отправки уведомления: %v\n", err)
+	// 		}
+	// 	}()
+	// }
+
+	// Формирование ответа API
+	c.JSON(http.StatusOK, toTaskResponses(tasks))
+}
@@ -0,0 +1,204 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Типы событий истории задачи.
+const (
+	EventCreated        = "created"
+	EventUpdated        = "updated"
+	EventReassigned     = "reassigned"
+	EventStatusChanged  = "status_changed"
+	EventReviewApproved = "review_approved"
+	EventReviewRejected = "review_rejected"
+	EventDeleted        = "deleted"
+)
+
+// assigneeField — поле diffTask, изменение которого записывается в историю как переназначение.
+const assigneeField = "Исполнитель"
+
+// recordEvent добавляет событие в историю задачи.
+func recordEvent(tx *gorm.DB, event models.TaskEvent) error {
+	return tx.Create(&event).Error
+}
+
+// recordChanges записывает изменение задачи: смену исполнителя — отдельным событием reassigned, остальные поля — событием updated.
+func recordChanges(tx *gorm.DB, task *models.Task, actorID uint, changes []taskChange) error {
+	var updated, reassigned []taskChange
+	for _, change := range changes {
+		if change.field == assigneeField {
+			reassigned = append(reassigned, change)
+		} else {
+			updated = append(updated, change)
+		}
+	}
+
+	for _, group := range []struct {
+		eventType string
+		changes   []taskChange
+	}{
+		{EventUpdated, updated},
+		{EventReassigned, reassigned},
+	} {
+		if len(group.changes) == 0 {
+			continue
+		}
+		if err := recordEvent(tx, models.TaskEvent{
+			TaskID:     task.ID,
+			Type:       group.eventType,
+			ActorID:    actorID,
+			TaskStatus: task.Status,
+			Changes:    changesJSON(group.changes),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func changesJSON(changes []taskChange) string {
+	items := make([]response.TaskChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, response.TaskChangeResponse{Field: change.field, Old: change.old, New: change.new})
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// taskEvents возвращает историю задачи в хронологическом порядке.
+func taskEvents(taskID uint) ([]models.TaskEvent, error) {
+	var events []models.TaskEvent
+	err := storage.DB.Preload("Actor").Preload("User").
+		Where("task_id = ?", taskID).
+		Order("created_at, id").
+		Find(&events).Error
+	return events, err
+}
+
+func toTaskEventResponse(event models.TaskEvent) response.TaskEventResponse {
+	resp := response.TaskEventResponse{
+		ID:              event.ID,
+		Type:            event.Type,
+		ActorTelegramID: event.Actor.TelegramID,
+		ActorName:       event.Actor.Name,
+		FromStatus:      event.FromStatus,
+		ToStatus:        event.ToStatus,
+		TaskStatus:      event.TaskStatus,
+		CompletionText:  event.CompletionText,
+		Attachment:      event.Attachment,
+		Comment:         event.Comment,
+		Changes:         []response.TaskChangeResponse{},
+		CreatedAt:       event.CreatedAt,
+	}
+	if event.User != nil {
+		resp.TelegramID = event.User.TelegramID
+		resp.Name = event.User.Name
+	}
+	if event.Changes != "" {
+		_ = json.Unmarshal([]byte(event.Changes), &resp.Changes)
+	}
+	return resp
+}
+
+// describeEvent описывает событие одной строкой для уведомления.
+func describeEvent(event models.TaskEvent, flow *workflow.Machine) string {
+	var what string
+	switch event.Type {
+	case EventCreated:
+		what = "создал(а) задачу"
+	case EventUpdated, EventReassigned:
+		var changes []response.TaskChangeResponse
+		_ = json.Unmarshal([]byte(event.Changes), &changes)
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			fields = append(fields, strings.ToLower(change.Field))
+		}
+		what = "изменил(а): " + strings.Join(fields, ", ")
+	case EventStatusChanged:
+		what = fmt.Sprintf("%s → %s", flow.Title(event.FromStatus), flow.Title(event.ToStatus))
+	case EventReviewApproved:
+		what = "принял(а) работу"
+	case EventReviewRejected:
+		what = fmt.Sprintf("вернул(а) на доработку: %s", event.Comment)
+	case EventDeleted:
+		what = "удалил(а) задачу"
+	default:
+		what = event.Type
+	}
+	return fmt.Sprintf("%s — %s: %s", event.CreatedAt.Format("02.01 15:04"), event.Actor.Name, what)
+}
+
+// historySummary возвращает последние события задачи для уведомления проверяющему.
+func historySummary(taskID uint, flow *workflow.Machine, limit int) string {
+	events, err := taskEvents(taskID)
+	if err != nil || len(events) == 0 {
+		return ""
+	}
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, "▫️ "+describeEvent(event, flow))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetTaskHistoryHandler возвращает историю задачи
+// @Summary История задачи
+// @Description Возвращает события задачи в хронологическом порядке: создание, изменения полей, переназначение, переходы статусов с отчётом и вложением, решения по проверке и удаление. История удалённой задачи остаётся доступной. Доступно автору задачи, участникам команды и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.TaskEventResponse "История задачи"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении истории задачи"
+// @Router /tasks/{id}/history [get]
+func GetTaskHistoryHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.Unscoped().First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	events, err := taskEvents(task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении истории задачи"})
+		return
+	}
+
+	resp := make([]response.TaskEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, toTaskEventResponse(e))
+	}
+	c.JSON(http.StatusOK, resp)
+}
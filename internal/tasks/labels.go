@@ -0,0 +1,446 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Приоритеты задач по возрастанию важности.
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+var priorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// defaultLabelColor назначается метке, если цвет не указан.
+const defaultLabelColor = "#9E9E9E"
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// errLabelNotFound возвращается, если среди label_ids есть метки другой команды или несуществующие.
+var errLabelNotFound = errors.New("label not found")
+
+// priorityTitle возвращает название приоритета для уведомлений и истории.
+func priorityTitle(priority string) string {
+	switch priority {
+	case PriorityLow:
+		return "низкий"
+	case PriorityHigh:
+		return "высокий"
+	case PriorityUrgent:
+		return "срочный"
+	default:
+		return "обычный"
+	}
+}
+
+// estimateTitle описывает оценку задачи для списка изменений.
+func estimateTitle(points *int, hours *float64) string {
+	var parts []string
+	if points != nil {
+		parts = append(parts, fmt.Sprintf("%d SP", *points))
+	}
+	if hours != nil {
+		parts = append(parts, strconv.FormatFloat(*hours, 'f', -1, 64)+" ч")
+	}
+	if len(parts) == 0 {
+		return "нет"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// teamLabels возвращает метки команды с указанными ID. Если какой-то метки нет в команде, возвращает errLabelNotFound.
+func teamLabels(db *gorm.DB, teamID uint, ids []uint) ([]models.Label, error) {
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var labels []models.Label
+	if err := db.Where("team_id = ? AND id IN ?", teamID, ids).Order("name").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	if len(labels) != len(ids) {
+		return nil, errLabelNotFound
+	}
+	return labels, nil
+}
+
+// setTaskLabels заменяет метки задачи.
+func setTaskLabels(tx *gorm.DB, taskID uint, labels []models.Label) error {
+	if err := tx.Where("task_id = ?", taskID).Delete(&models.TaskLabel{}).Error; err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	links := make([]models.TaskLabel, 0, len(labels))
+	for _, l := range labels {
+		links = append(links, models.TaskLabel{TaskID: taskID, LabelID: l.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+// setSeriesLabels заменяет метки серии повторяющихся задач.
+func setSeriesLabels(tx *gorm.DB, seriesID uint, labels []models.Label) error {
+	if err := tx.Where("series_id = ?", seriesID).Delete(&models.TaskSeriesLabel{}).Error; err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	links := make([]models.TaskSeriesLabel, 0, len(labels))
+	for _, l := range labels {
+		links = append(links, models.TaskSeriesLabel{SeriesID: seriesID, LabelID: l.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+// setTemplateLabels заменяет метки шаблона задачи.
+func setTemplateLabels(tx *gorm.DB, templateID uint, labels []models.Label) error {
+	if err := tx.Where("template_id = ?", templateID).Delete(&models.TaskTemplateLabel{}).Error; err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	links := make([]models.TaskTemplateLabel, 0, len(labels))
+	for _, l := range labels {
+		links = append(links, models.TaskTemplateLabel{TemplateID: templateID, LabelID: l.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+// seriesLabels возвращает метки серии, загруженные через Preload("Labels.Label"), по названию.
+func seriesLabels(series models.TaskSeries) []models.Label {
+	labels := make([]models.Label, 0, len(series.Labels))
+	for _, link := range series.Labels {
+		labels = append(labels, link.Label)
+	}
+	slices.SortFunc(labels, func(a, b models.Label) int { return strings.Compare(a.Name, b.Name) })
+	return labels
+}
+
+// templateLabels возвращает метки шаблона, загруженные через Preload("Labels.Label"), по названию.
+func templateLabels(tmpl models.TaskTemplate) []models.Label {
+	labels := make([]models.Label, 0, len(tmpl.Labels))
+	for _, link := range tmpl.Labels {
+		labels = append(labels, link.Label)
+	}
+	slices.SortFunc(labels, func(a, b models.Label) int { return strings.Compare(a.Name, b.Name) })
+	return labels
+}
+
+// labelNames перечисляет метки через запятую для списка изменений.
+func labelNames(labels []models.Label) string {
+	if len(labels) == 0 {
+		return "нет"
+	}
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func toLabelResponse(l models.Label) response.LabelResponse {
+	return response.LabelResponse{ID: l.ID, TeamID: l.TeamID, Name: l.Name, Color: l.Color}
+}
+
+func toLabelResponses(labels []models.Label) []response.LabelResponse {
+	resp := make([]response.LabelResponse, 0, len(labels))
+	for _, l := range labels {
+		resp = append(resp, toLabelResponse(l))
+	}
+	return resp
+}
+
+// labelsByTask возвращает метки задач, отсортированные по названию.
+func labelsByTask(tasks []models.Task) map[uint][]response.LabelResponse {
+	labels := make(map[uint][]response.LabelResponse, len(tasks))
+	if len(tasks) == 0 {
+		return labels
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var links []models.TaskLabel
+	if err := storage.DB.Joins("Label").
+		Where("task_labels.task_id IN ?", ids).
+		Order(`"Label".name`).
+		Find(&links).Error; err != nil {
+		return labels
+	}
+	for _, link := range links {
+		labels[link.TaskID] = append(labels[link.TaskID], toLabelResponse(link.Label))
+	}
+	return labels
+}
+
+type LabelInput struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color"` // #RRGGBB, по умолчанию серый
+}
+
+type UpdateLabelInput struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Color *string `json:"color"` // #RRGGBB
+}
+
+// GetLabelsHandler возвращает метки команды
+// @Summary Метки команды
+// @Description Возвращает метки команды по названию и количество задач с каждой меткой.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {array} response.LabelResponse "Метки"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении меток"
+// @Router /team/labels [get]
+func GetLabelsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	var labels []models.Label
+	if err := storage.DB.Where("team_id = ?", membership.TeamID).Order("name").Find(&labels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении меток"})
+		return
+	}
+
+	var counts []struct {
+		LabelID uint
+		Count   int
+	}
+	if err := storage.DB.Model(&models.TaskLabel{}).
+		Select("task_labels.label_id, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = task_labels.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.team_id = ?", membership.TeamID).
+		Group("task_labels.label_id").
+		Scan(&counts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении меток"})
+		return
+	}
+	byLabel := make(map[uint]int, len(counts))
+	for _, row := range counts {
+		byLabel[row.LabelID] = row.Count
+	}
+
+	resp := make([]response.LabelResponse, 0, len(labels))
+	for _, l := range labels {
+		item := toLabelResponse(l)
+		item.TaskCount = byLabel[l.ID]
+		resp = append(resp, item)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateLabelHandler создаёт метку команды
+// @Summary Создание метки
+// @Description Создаёт метку команды с цветом в формате #RRGGBB. Названия меток в команде не повторяются. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body LabelInput true "Метка"
+// @Success 201 {object} response.LabelResponse "Метка создана"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Цвет метки указывается в формате #RRGGBB Code: INVALID_COLOR"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Метка с таким названием уже есть Code: LABEL_EXISTS"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании метки"
+// @Router /team/labels [post]
+func CreateLabelHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var input LabelInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	label := models.Label{
+		TeamID:    membership.TeamID,
+		Name:      strings.TrimSpace(input.Name),
+		Color:     input.Color,
+		CreatedBy: user.ID,
+	}
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+	if !writeLabelError(c, &label) {
+		return
+	}
+
+	if err := storage.DB.Create(&label).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании метки"})
+		return
+	}
+	c.JSON(http.StatusCreated, toLabelResponse(label))
+}
+
+// UpdateLabelHandler изменяет метку команды
+// @Summary Изменение метки
+// @Description Меняет название или цвет метки. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID метки"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body UpdateLabelInput true "Изменяемые поля"
+// @Success 200 {object} response.LabelResponse "Метка"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Цвет метки указывается в формате #RRGGBB Code: INVALID_COLOR"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Метка не найдена Code: LABEL_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Метка с таким названием уже есть Code: LABEL_EXISTS"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении метки"
+// @Router /team/labels/{id} [put]
+func UpdateLabelHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var label models.Label
+	if err := storage.DB.Where("team_id = ?", membership.TeamID).First(&label, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Метка не найдена", "code": "LABEL_NOT_FOUND"})
+		return
+	}
+
+	var input UpdateLabelInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Name != nil {
+		label.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Color != nil {
+		label.Color = *input.Color
+	}
+	if !writeLabelError(c, &label) {
+		return
+	}
+
+	if err := storage.DB.Save(&label).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении метки"})
+		return
+	}
+	c.JSON(http.StatusOK, toLabelResponse(label))
+}
+
+// writeLabelError проверяет название и цвет метки. При ошибке пишет ответ и возвращает false.
+func writeLabelError(c *gin.Context, label *models.Label) bool {
+	if label.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Название метки не может быть пустым"})
+		return false
+	}
+	if !labelColorPattern.MatchString(label.Color) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Цвет метки указывается в формате #RRGGBB", "code": "INVALID_COLOR"})
+		return false
+	}
+	label.Color = strings.ToUpper(label.Color)
+
+	var exists int64
+	if err := storage.DB.Model(&models.Label{}).
+		Where("team_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", label.TeamID, label.Name, label.ID).
+		Count(&exists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении метки"})
+		return false
+	}
+	if exists > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Метка с таким названием уже есть", "code": "LABEL_EXISTS"})
+		return false
+	}
+	return true
+}
+
+// DeleteLabelHandler удаляет метку команды
+// @Summary Удаление метки
+// @Description Удаляет метку и снимает её со всех задач, серий и шаблонов команды. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID метки"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Метка удалена"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Метка не найдена Code: LABEL_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении метки"
+// @Router /team/labels/{id} [delete]
+func DeleteLabelHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var label models.Label
+	if err := storage.DB.Where("team_id = ?", membership.TeamID).First(&label, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Метка не найдена", "code": "LABEL_NOT_FOUND"})
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("label_id = ?", label.ID).Delete(&models.TaskLabel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("label_id = ?", label.ID).Delete(&models.TaskSeriesLabel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("label_id = ?", label.ID).Delete(&models.TaskTemplateLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&label).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении метки"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Метка удалена"})
+}
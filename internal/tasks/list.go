@@ -0,0 +1,239 @@
+package tasks
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Размер страницы GET /tasks.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// errInvalidCursor возвращается, если cursor не получен из next_cursor запроса с той же сортировкой.
+var errInvalidCursor = errors.New("invalid cursor")
+
+// taskSorts — допустимые значения sort в списках задач и соответствующие выражения ORDER BY.
+var taskSorts = map[string]string{
+	"deadline":       "tasks.deadline",
+	"created_at":     "tasks.created_at",
+	"priority":       "CASE tasks.priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END",
+	"story_points":   "tasks.story_points",
+	"estimate_hours": "tasks.estimate_hours",
+}
+
+// parseIDList разбирает список ID через запятую.
+func parseIDList(raw string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// filterTasks применяет к запросу списка задач фильтры из параметров запроса: status, priority, label,
+// assignee, deadline_from, deadline_to, overdue и unestimated. При некорректных параметрах пишет ответ и возвращает false.
+func filterTasks(c *gin.Context, user *models.User, query *gorm.DB) (*gorm.DB, bool) {
+	if raw := c.Query("status"); raw != "" {
+		var statuses []string
+		for _, status := range strings.Split(raw, ",") {
+			statuses = append(statuses, strings.TrimSpace(status))
+		}
+		query = query.Where("tasks.status IN ?", statuses)
+	}
+
+	if raw := c.Query("priority"); raw != "" {
+		var wanted []string
+		for _, p := range strings.Split(raw, ",") {
+			p = strings.TrimSpace(p)
+			if !slices.Contains(priorities, p) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный приоритет: " + p, "code": "INVALID_PRIORITY"})
+				return nil, false
+			}
+			wanted = append(wanted, p)
+		}
+		query = query.Where("tasks.priority IN ?", wanted)
+	}
+
+	if raw := c.Query("label"); raw != "" {
+		ids, err := parseIDList(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный label", "code": "INVALID_LABEL_ID"})
+			return nil, false
+		}
+		// Задача подходит, если у неё есть хотя бы одна из меток
+		query = query.Where("tasks.id IN (?)", storage.DB.Model(&models.TaskLabel{}).Select("task_id").Where("label_id IN ?", ids))
+	}
+
+	if assignee := c.Query("assignee"); assignee != "" {
+		if assignee == "me" {
+			assignee = user.TelegramID
+		}
+		// Персональные задачи исполнителя и командные задачи, над которыми он работает
+		query = query.Where("(tasks.assigned_to = ? OR tasks.id IN (?))", assignee,
+			storage.DB.Model(&models.TaskAssignment{}).
+				Select("task_assignments.task_id").
+				Joins("JOIN users ON users.id = task_assignments.user_id").
+				Where("users.telegram_id = ?", assignee))
+	}
+
+	for param, cond := range map[string]string{"deadline_from": "tasks.deadline >= ?", "deadline_to": "tasks.deadline <= ?"} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " указывается в формате RFC 3339", "code": "INVALID_DATE"})
+			return nil, false
+		}
+		query = query.Where(cond, t)
+	}
+
+	if c.Query("overdue") == "true" {
+		query = query.Where("tasks.deadline < ? AND NOT (?)", time.Now(), workflow.TerminalCondition())
+	}
+
+	if c.Query("unestimated") == "true" {
+		query = query.Where("tasks.story_points IS NULL AND tasks.estimate_hours IS NULL")
+	}
+	return query, true
+}
+
+// taskSort — порядок списка задач из параметров sort и order. При равенстве ключа задачи упорядочены по ID.
+type taskSort struct {
+	key  string
+	desc bool
+}
+
+// parseTaskSort разбирает параметры sort и order. При ошибке пишет ответ и возвращает false.
+func parseTaskSort(c *gin.Context) (taskSort, bool) {
+	sort := taskSort{key: c.DefaultQuery("sort", "deadline")}
+	if _, ok := taskSorts[sort.key]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректная сортировка: " + sort.key, "code": "INVALID_SORT"})
+		return sort, false
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		sort.desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order может быть asc или desc", "code": "INVALID_SORT"})
+		return sort, false
+	}
+	return sort, true
+}
+
+// apply упорядочивает запрос; задачи без оценки идут в конце при любом порядке.
+func (s taskSort) apply(query *gorm.DB) *gorm.DB {
+	if s.desc {
+		return query.Order(taskSorts[s.key] + " DESC NULLS LAST").Order("tasks.id DESC")
+	}
+	return query.Order(taskSorts[s.key] + " ASC NULLS LAST").Order("tasks.id")
+}
+
+// taskCursor — позиция в списке: значение ключа сортировки и ID последней задачи страницы.
+type taskCursor struct {
+	Sort  string      `json:"s"`
+	Desc  bool        `json:"d,omitempty"`
+	Value interface{} `json:"v"`
+	ID    uint        `json:"id"`
+}
+
+// cursor возвращает курсор, с которого начинается страница после задачи task.
+func (s taskSort) cursor(task models.Task) string {
+	cur := taskCursor{Sort: s.key, Desc: s.desc, ID: task.ID}
+	switch s.key {
+	case "deadline":
+		cur.Value = task.Deadline.Format(time.RFC3339Nano)
+	case "created_at":
+		cur.Value = task.CreatedAt.Format(time.RFC3339Nano)
+	case "priority":
+		cur.Value = slices.Index(priorities, task.Priority) + 1
+	case "story_points":
+		if task.StoryPoints != nil {
+			cur.Value = *task.StoryPoints
+		}
+	case "estimate_hours":
+		if task.EstimateHours != nil {
+			cur.Value = *task.EstimateHours
+		}
+	}
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// after оставляет в запросе задачи, которые идут после курсора raw.
+func (s taskSort) after(query *gorm.DB, raw string) (*gorm.DB, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var cur taskCursor
+	if err := json.Unmarshal(data, &cur); err != nil || cur.Sort != s.key || cur.Desc != s.desc {
+		return nil, errInvalidCursor
+	}
+
+	value := cur.Value
+	switch v := cur.Value.(type) {
+	case string:
+		if s.key != "deadline" && s.key != "created_at" {
+			return nil, errInvalidCursor
+		}
+		if value, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, errInvalidCursor
+		}
+	case float64:
+		if s.key == "deadline" || s.key == "created_at" {
+			return nil, errInvalidCursor
+		}
+	case nil:
+	default:
+		return nil, errInvalidCursor
+	}
+
+	expr := taskSorts[s.key]
+	op := ">"
+	if s.desc {
+		op = "<"
+	}
+	// Задачи с пустым ключом идут в конце списка
+	if value == nil {
+		return query.Where(expr+" IS NULL AND tasks.id "+op+" ?", cur.ID), nil
+	}
+	return query.Where("("+expr+" "+op+" ? OR "+expr+" = ? AND tasks.id "+op+" ? OR "+expr+" IS NULL)", value, value, cur.ID), nil
+}
+
+// pageSize возвращает размер страницы из параметра limit. При ошибке пишет ответ и возвращает false.
+func pageSize(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultPageSize, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit должен быть от 1 до " + strconv.Itoa(maxPageSize), "code": "INVALID_LIMIT"})
+		return 0, false
+	}
+	return limit, true
+}
@@ -0,0 +1,555 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/recurrence"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultSeriesInterval — как часто проверяются серии, если TASK_SERIES_INTERVAL не задан.
+const defaultSeriesInterval = time.Minute
+
+// newSeries создаёт серию повторяющихся задач, первое повторение которой — задача task.
+func newSeries(tx *gorm.DB, task *models.Task, rule *recurrence.Rule, labels []models.Label) error {
+	series := models.TaskSeries{
+		TeamID:         task.TeamID,
+		CreatedBy:      task.CreatedBy,
+		Title:          task.Title,
+		Description:    task.Description,
+		IsTeam:         task.IsTeam,
+		AssignedTo:     task.AssignedTo,
+		CompletionRule: task.CompletionRule,
+		QuorumPercent:  task.QuorumPercent,
+		Priority:       task.Priority,
+		StoryPoints:    task.StoryPoints,
+		EstimateHours:  task.EstimateHours,
+		Rule:           rule.String(),
+		StartAt:        task.Deadline,
+		Generated:      1,
+		LastAt:         &task.Deadline,
+	}
+	if next, ok := rule.Next(series.StartAt, task.Deadline, series.Generated); ok {
+		series.NextAt = &next
+	}
+	if err := tx.Create(&series).Error; err != nil {
+		return err
+	}
+	task.SeriesID = &series.ID
+	return setSeriesLabels(tx, series.ID, labels)
+}
+
+// RunSeriesGenerator периодически создаёт очередные задачи повторяющихся серий. Запускается при старте сервера;
+// период проверки задаётся в TASK_SERIES_INTERVAL (например, "5m").
+func RunSeriesGenerator() {
+	interval := defaultSeriesInterval
+	if raw := os.Getenv("TASK_SERIES_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			interval = d
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		generateDueSeries()
+		<-ticker.C
+	}
+}
+
+// generateDueSeries создаёт следующую задачу в сериях, где наступил срок последнего повторения
+// или все задачи серии завершены. Серии архивных команд пропускаются.
+func generateDueSeries() {
+	open := storage.DB.Model(&models.Task{}).Select("1").
+		Where("tasks.series_id = task_series.id AND NOT (?)", workflow.TerminalCondition())
+	activeTeams := storage.DB.Model(&models.Team{}).Select("id").Where("archived_at IS NULL")
+
+	var ids []uint
+	if err := storage.DB.Model(&models.TaskSeries{}).
+		Where("stopped_at IS NULL AND next_at IS NOT NULL AND team_id IN (?)", activeTeams).
+		Where("last_at <= ? OR NOT EXISTS (?)", time.Now(), open).
+		Pluck("id", &ids).Error; err != nil {
+		fmt.Printf("Ошибка получения повторяющихся задач: %v\n", err)
+		return
+	}
+	for _, id := range ids {
+		advanceSeries(id)
+	}
+}
+
+// advanceSeries создаёт следующую задачу серии, если срок последнего повторения наступил или все задачи серии
+// завершены, и уведомляет исполнителей. Пропущенные повторения (например, пока сервер не работал) не создаются:
+// срок новой задачи — ближайшее повторение в будущем.
+func advanceSeries(seriesID uint) {
+	var task models.Task
+	var series models.TaskSeries
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Labels.Label").First(&series, seriesID).Error; err != nil {
+			return err
+		}
+		if series.StoppedAt != nil || series.NextAt == nil {
+			return nil
+		}
+
+		now := time.Now()
+		if series.LastAt != nil && series.LastAt.After(now) {
+			var open int64
+			if err := tx.Model(&models.Task{}).
+				Where("series_id = ? AND NOT (?)", series.ID, workflow.TerminalCondition()).
+				Count(&open).Error; err != nil {
+				return err
+			}
+			if open > 0 {
+				return nil
+			}
+		}
+
+		rule, err := recurrence.Parse(series.Rule)
+		if err != nil {
+			return err
+		}
+		deadline := *series.NextAt
+		if !deadline.After(now) {
+			next, ok := rule.Next(series.StartAt, now, series.Generated)
+			if !ok {
+				series.NextAt = nil
+				return tx.Omit(clause.Associations).Save(&series).Error
+			}
+			deadline = next
+		}
+
+		flow, err := workflow.ForTeam(tx, series.TeamID)
+		if err != nil {
+			return err
+		}
+		task = models.Task{
+			Title:          series.Title,
+			Description:    series.Description,
+			Deadline:       deadline,
+			Status:         flow.Initial(),
+			IsTeam:         series.IsTeam,
+			AssignedTo:     series.AssignedTo,
+			CreatedBy:      series.CreatedBy,
+			TeamID:         series.TeamID,
+			CompletionRule: series.CompletionRule,
+			QuorumPercent:  series.QuorumPercent,
+			Priority:       series.Priority,
+			StoryPoints:    series.StoryPoints,
+			EstimateHours:  series.EstimateHours,
+			SeriesID:       &series.ID,
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if err := setTaskLabels(tx, task.ID, seriesLabels(series)); err != nil {
+			return err
+		}
+		if err := recordEvent(tx, models.TaskEvent{TaskID: task.ID, Type: EventCreated, ActorID: series.CreatedBy, ToStatus: task.Status, TaskStatus: task.Status}); err != nil {
+			return err
+		}
+		if task.IsTeam {
+			if err := createAssignments(tx, &task, flow); err != nil {
+				return err
+			}
+		}
+
+		series.Generated++
+		series.LastAt = &deadline
+		series.NextAt = nil
+		if next, ok := rule.Next(series.StartAt, deadline, series.Generated); ok {
+			series.NextAt = &next
+		}
+		return tx.Omit(clause.Associations).Save(&series).Error
+	})
+	if err != nil {
+		fmt.Printf("Ошибка создания задачи серии %d: %v\n", seriesID, err)
+		return
+	}
+	if task.ID != 0 {
+		notifySeriesTask(task, series)
+	}
+}
+
+// notifySeriesTask уведомляет исполнителей о задаче, созданной по расписанию.
+func notifySeriesTask(task models.Task, series models.TaskSeries) {
+	schedule := series.Rule
+	if rule, err := recurrence.Parse(series.Rule); err == nil {
+		schedule = rule.Title()
+	}
+	text := fmt.Sprintf(
+		"🔁 *Новая задача по расписанию*\n\n"+
+			"▫️ *Заголовок:* %s\n"+
+			"▫️ *Описание:* \n_%s_\n"+
+			"▫️ *Дедлайн:* %s\n"+
+			"▫️ *Приоритет:* %s\n"+
+			"▫️ *Повторяется:* %s",
+		task.Title,
+		task.Description,
+		notification.FormatDeadline(task.Deadline),
+		priorityTitle(task.Priority),
+		schedule,
+	)
+	for _, chatID := range taskAudience(task) {
+		if chatID == "" {
+			continue
+		}
+		go func(chatID string) {
+			if err := notification.SendTelegramNotification(chatID, text); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}(chatID)
+	}
+}
+
+// taskStatusChanged вызывается после изменения общего статуса задачи: уведомляет исполнителей разблокированных
+// задач, а если завершилась задача серии — создаёт следующее повторение.
+func taskStatusChanged(previous string, task models.Task, flow *workflow.Machine) {
+	releaseDependents(previous, task, flow)
+	if task.SeriesID != nil && !flow.IsTerminal(previous) && flow.IsTerminal(task.Status) {
+		advanceSeries(*task.SeriesID)
+	}
+}
+
+// toTaskSeriesResponses собирает ответ по сериям вместе с их метками и последней созданной задачей.
+func toTaskSeriesResponses(series []models.TaskSeries) []response.TaskSeriesResponse {
+	ids := make([]uint, 0, len(series))
+	for _, s := range series {
+		ids = append(ids, s.ID)
+	}
+
+	lastTasks := make(map[uint]uint)
+	if len(ids) > 0 {
+		var rows []struct {
+			SeriesID uint
+			TaskID   uint
+		}
+		if err := storage.DB.Model(&models.Task{}).
+			Select("series_id, MAX(id) AS task_id").
+			Where("series_id IN ?", ids).
+			Group("series_id").
+			Scan(&rows).Error; err == nil {
+			for _, r := range rows {
+				lastTasks[r.SeriesID] = r.TaskID
+			}
+		}
+	}
+
+	resp := make([]response.TaskSeriesResponse, 0, len(series))
+	for _, s := range series {
+		item := response.TaskSeriesResponse{
+			ID:             s.ID,
+			TeamID:         s.TeamID,
+			CreatedBy:      s.CreatedBy,
+			Title:          s.Title,
+			Description:    s.Description,
+			IsTeam:         s.IsTeam,
+			AssignedTo:     s.AssignedTo,
+			CompletionRule: s.CompletionRule,
+			QuorumPercent:  s.QuorumPercent,
+			Priority:       s.Priority,
+			StoryPoints:    s.StoryPoints,
+			EstimateHours:  s.EstimateHours,
+			Labels:         toLabelResponses(seriesLabels(s)),
+			Recurrence:     s.Rule,
+			StartAt:        s.StartAt,
+			Generated:      s.Generated,
+			LastAt:         s.LastAt,
+			NextAt:         s.NextAt,
+			StoppedAt:      s.StoppedAt,
+			CreatedAt:      s.CreatedAt,
+		}
+		if id, ok := lastTasks[s.ID]; ok {
+			item.LastTaskID = &id
+		}
+		resp = append(resp, item)
+	}
+	return resp
+}
+
+type UpdateSeriesInput struct {
+	Title         *string    `json:"title" binding:"omitempty,min=1"`
+	Description   *string    `json:"description"`
+	AssignedTo    *string    `json:"assigned_to"` // Telegram ID исполнителя персональной серии
+	Priority      *string    `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	StoryPoints   *int       `json:"story_points" binding:"omitempty,min=-1"`   // -1 — убрать оценку
+	EstimateHours *float64   `json:"estimate_hours" binding:"omitempty,min=-1"` // -1 — убрать оценку
+	LabelIDs      *[]uint    `json:"label_ids"`                                 // Новый набор меток; пустой список убирает все метки
+	Recurrence    *string    `json:"recurrence" example:"FREQ=MONTHLY;BYMONTHDAY=-1"`
+	NextAt        *time.Time `json:"next_at"` // Срок следующей задачи (RFC 3339); от него отсчитываются дальнейшие повторения
+}
+
+// findSeries загружает серию из пути запроса и проверяет доступ: с write — как к изменению задачи,
+// иначе — как к просмотру. При отказе пишет ответ и возвращает false.
+func findSeries(c *gin.Context, user *models.User, series *models.TaskSeries, write bool) bool {
+	if err := storage.DB.Preload("Labels.Label").First(series, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Серия задач не найдена", "code": "SERIES_NOT_FOUND"})
+		return false
+	}
+	owner := &models.Task{TeamID: series.TeamID, CreatedBy: series.CreatedBy}
+	if write {
+		return requireTaskAuthor(c, user, owner)
+	}
+	return requireTaskViewer(c, user, owner, false)
+}
+
+// GetTaskSeriesListHandler возвращает повторяющиеся задачи команды
+// @Summary Повторяющиеся задачи команды
+// @Description Возвращает серии повторяющихся задач команды: шаблон задачи, правило повторения, срок следующей задачи и последнюю созданную задачу. С active=true — только серии, по которым ещё будут созданы задачи.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param active query bool false "Только действующие серии"
+// @Success 200 {array} response.TaskSeriesResponse "Серии задач"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении серий задач"
+// @Router /tasks/series [get]
+func GetTaskSeriesListHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	query := storage.DB.Where("team_id = ?", membership.TeamID)
+	if c.Query("active") == "true" {
+		query = query.Where("stopped_at IS NULL AND next_at IS NOT NULL")
+	}
+	var series []models.TaskSeries
+	if err := query.Preload("Labels.Label").Order("next_at NULLS LAST, id").Find(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении серий задач"})
+		return
+	}
+	c.JSON(http.StatusOK, toTaskSeriesResponses(series))
+}
+
+// GetTaskSeriesHandler возвращает серию повторяющихся задач
+// @Summary Серия повторяющихся задач
+// @Description Возвращает шаблон и правило повторения серии. Доступно участникам команды и автору серии.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID серии"
+// @Success 200 {object} response.TaskSeriesResponse "Серия задач"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Серия задач не найдена Code: SERIES_NOT_FOUND"
+// @Router /tasks/series/{id} [get]
+func GetTaskSeriesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var series models.TaskSeries
+	if !findSeries(c, user, &series, false) {
+		return
+	}
+	c.JSON(http.StatusOK, toTaskSeriesResponses([]models.TaskSeries{series})[0])
+}
+
+// UpdateTaskSeriesHandler изменяет серию повторяющихся задач
+// @Summary Изменение серии задач
+// @Description Меняет шаблон задачи и правило повторения. Изменения применяются к следующим задачам серии, уже созданные задачи не меняются. С next_at серия отсчитывается заново от этого срока; при смене правила без next_at следующий срок пересчитывается от начала серии. COUNT учитывает все созданные задачи серии. Доступно автору серии и владельцу команды.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID серии"
+// @Param input body UpdateSeriesInput true "Изменяемые поля"
+// @Success 200 {object} response.TaskSeriesResponse "Серия задач"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: INTERVAL должен быть от 1 до 365 Code: INVALID_RECURRENCE, Error: Срок следующей задачи должен быть в будущем Code: INVALID_NEXT_AT"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Серия задач не найдена Code: SERIES_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Серия задач остановлена Code: SERIES_STOPPED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении серии задач"
+// @Router /tasks/series/{id} [put]
+func UpdateTaskSeriesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var series models.TaskSeries
+	if !findSeries(c, user, &series, true) {
+		return
+	}
+	if series.StoppedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Серия задач остановлена", "code": "SERIES_STOPPED"})
+		return
+	}
+
+	var input UpdateSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := recurrence.Parse(series.Rule)
+	if input.Recurrence != nil {
+		rule, err = recurrence.Parse(*input.Recurrence)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_RECURRENCE"})
+			return
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении серии задач"})
+		return
+	}
+	if input.NextAt != nil && !input.NextAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Срок следующей задачи должен быть в будущем", "code": "INVALID_NEXT_AT"})
+		return
+	}
+
+	if input.Title != nil {
+		series.Title = *input.Title
+	}
+	if input.Description != nil {
+		series.Description = *input.Description
+	}
+	if input.AssignedTo != nil {
+		if series.IsTeam {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "assigned_to указывается только для персональных задач"})
+			return
+		}
+		var assignee models.User
+		if err := storage.DB.Where("telegram_id = ?", *input.AssignedTo).First(&assignee).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+			return
+		}
+		if _, err := access.GetMembership(assignee.ID, series.TeamID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+			return
+		}
+		series.AssignedTo = input.AssignedTo
+	}
+	if input.Priority != nil {
+		series.Priority = *input.Priority
+	}
+	if input.StoryPoints != nil {
+		series.StoryPoints = input.StoryPoints
+		if *input.StoryPoints < 0 {
+			series.StoryPoints = nil
+		}
+	}
+	if input.EstimateHours != nil {
+		series.EstimateHours = input.EstimateHours
+		if *input.EstimateHours < 0 {
+			series.EstimateHours = nil
+		}
+	}
+	var labels []models.Label
+	if input.LabelIDs != nil {
+		labels, err = teamLabels(storage.DB, series.TeamID, *input.LabelIDs)
+		if errors.Is(err, errLabelNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Метка не найдена в команде", "code": "LABEL_NOT_FOUND"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении серии задач"})
+			return
+		}
+	}
+
+	switch {
+	case input.NextAt != nil:
+		series.StartAt = *input.NextAt
+		series.NextAt = nil
+		if next, ok := rule.Next(series.StartAt, series.StartAt.Add(-time.Second), series.Generated); ok {
+			series.NextAt = &next
+		}
+	case input.Recurrence != nil:
+		after := time.Now()
+		if series.LastAt != nil && series.LastAt.After(after) {
+			after = *series.LastAt
+		}
+		series.NextAt = nil
+		if next, ok := rule.Next(series.StartAt, after, series.Generated); ok {
+			series.NextAt = &next
+		}
+	}
+	series.Rule = rule.String()
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&series).Error; err != nil {
+			return err
+		}
+		if input.LabelIDs == nil {
+			return nil
+		}
+		return setSeriesLabels(tx, series.ID, labels)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении серии задач"})
+		return
+	}
+	if input.LabelIDs != nil {
+		series.Labels = series.Labels[:0]
+		for _, l := range labels {
+			series.Labels = append(series.Labels, models.TaskSeriesLabel{SeriesID: series.ID, LabelID: l.ID, Label: l})
+		}
+	}
+	c.JSON(http.StatusOK, toTaskSeriesResponses([]models.TaskSeries{series})[0])
+}
+
+// StopTaskSeriesHandler останавливает серию повторяющихся задач
+// @Summary Остановка серии задач
+// @Description Прекращает создание новых задач серии. Уже созданные задачи остаются. Доступно автору серии и владельцу команды.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID серии"
+// @Success 200 {object} response.TaskSeriesResponse "Остановленная серия"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN, Задачу создали не вы"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Серия задач не найдена Code: SERIES_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Серия задач уже остановлена Code: SERIES_STOPPED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при остановке серии задач"
+// @Router /tasks/series/{id} [delete]
+func StopTaskSeriesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var series models.TaskSeries
+	if !findSeries(c, user, &series, true) {
+		return
+	}
+	if series.StoppedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Серия задач уже остановлена", "code": "SERIES_STOPPED"})
+		return
+	}
+
+	now := time.Now()
+	series.StoppedAt = &now
+	series.NextAt = nil
+	if err := storage.DB.Omit(clause.Associations).Save(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при остановке серии задач"})
+		return
+	}
+	c.JSON(http.StatusOK, toTaskSeriesResponses([]models.TaskSeries{series})[0])
+}
@@ -0,0 +1,518 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Ошибки проверки родительской задачи.
+var (
+	errParentNotFound = errors.New("parent task not found")
+	errParentCycle    = errors.New("parent task cycle")
+)
+
+// deadlineError — срок подзадачи не укладывается в срок родительской задачи.
+type deadlineError struct {
+	code  string
+	limit time.Time
+}
+
+func (e deadlineError) Error() string {
+	if e.code == "DEADLINE_AFTER_PARENT" {
+		return fmt.Sprintf("Срок подзадачи не может быть позже срока родительской задачи (%s)", e.limit.Format("02.01.2006 15:04"))
+	}
+	return fmt.Sprintf("Срок задачи не может быть раньше срока её подзадач (%s)", e.limit.Format("02.01.2006 15:04"))
+}
+
+// checkParent проверяет, что parentID может быть родительской задачей для task: задача из той же команды,
+// это не сама task и не её подзадача, а срок task не позже срока родителя.
+func checkParent(db *gorm.DB, task *models.Task, parentID uint) (*models.Task, error) {
+	var parent models.Task
+	if err := db.Where("team_id = ?", task.TeamID).First(&parent, parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errParentNotFound
+		}
+		return nil, err
+	}
+
+	// Поднимаемся от родителя к корню: если встретили саму задачу, получится цикл
+	for current := &parent; ; {
+		if task.ID != 0 && current.ID == task.ID {
+			return nil, errParentCycle
+		}
+		if current.ParentID == nil {
+			break
+		}
+		var next models.Task
+		if err := db.First(&next, *current.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		current = &next
+	}
+
+	if task.Deadline.After(parent.Deadline) {
+		return nil, deadlineError{code: "DEADLINE_AFTER_PARENT", limit: parent.Deadline}
+	}
+	return &parent, nil
+}
+
+// checkSubtaskDeadlines проверяет, что срок задачи не раньше сроков её подзадач.
+func checkSubtaskDeadlines(db *gorm.DB, task *models.Task) error {
+	var latest models.Task
+	err := db.Where("parent_id = ?", task.ID).Order("deadline DESC").First(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if latest.Deadline.After(task.Deadline) {
+		return deadlineError{code: "DEADLINE_BEFORE_SUBTASKS", limit: latest.Deadline}
+	}
+	return nil
+}
+
+// writeParentError переводит ошибки проверки родительской задачи и сроков в ответ API.
+// Возвращает true, если ответ записан.
+func writeParentError(c *gin.Context, err error) bool {
+	denial := parentDenial(err)
+	if denial == nil {
+		return false
+	}
+	denial.Write(c)
+	return true
+}
+
+// parentDenial переводит ошибки проверки родительской задачи и сроков в отказ. Возвращает nil, если ошибка другая.
+func parentDenial(err error) *access.Denial {
+	var deadline deadlineError
+	switch {
+	case errors.Is(err, errParentNotFound):
+		return &access.Denial{Status: http.StatusBadRequest, Message: "Родительская задача не найдена в команде", Code: "PARENT_TASK_NOT_FOUND"}
+	case errors.Is(err, errParentCycle):
+		return &access.Denial{Status: http.StatusBadRequest, Message: "Задача не может быть подзадачей самой себя или своей подзадачи", Code: "PARENT_TASK_CYCLE"}
+	case errors.As(err, &deadline):
+		return &access.Denial{Status: http.StatusBadRequest, Message: deadline.Error(), Code: deadline.code}
+	}
+	return nil
+}
+
+// subtaskStats — количество подзадач задачи и сколько из них выполнено.
+type subtaskStats struct {
+	total, done int
+}
+
+// rollupProgress заменяет прогресс задач с подзадачами средним прогрессом подзадач (с учётом вложенных)
+// и возвращает количество прямых подзадач. Завершённая задача остаётся выполненной на 100%.
+func rollupProgress(tasks []models.Task, progress map[uint]int) map[uint]subtaskStats {
+	stats := make(map[uint]subtaskStats)
+	own := make(map[uint]int, len(progress))
+	for id, p := range progress {
+		own[id] = p
+	}
+
+	children := make(map[uint][]uint)
+	seen := make(map[uint]bool, len(tasks))
+	level := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		seen[t.ID] = true
+		level = append(level, t.ID)
+	}
+	for len(level) > 0 {
+		var subtasks []models.Task
+		if err := storage.DB.Where("parent_id IN ?", level).Find(&subtasks).Error; err != nil {
+			return stats
+		}
+		level = level[:0]
+		for _, t := range subtasks {
+			if seen[t.ID] {
+				continue
+			}
+			seen[t.ID] = true
+			children[*t.ParentID] = append(children[*t.ParentID], t.ID)
+			level = append(level, t.ID)
+		}
+		for id, p := range progressByTask(subtasks) {
+			own[id] = p
+		}
+	}
+
+	final := make(map[uint]int)
+	var compute func(id uint) int
+	compute = func(id uint) int {
+		if p, ok := final[id]; ok {
+			return p
+		}
+		p := own[id]
+		if kids := children[id]; len(kids) > 0 && p < 100 {
+			sum := 0
+			for _, kid := range kids {
+				sum += compute(kid)
+			}
+			p = sum / len(kids)
+		}
+		final[id] = p
+		return p
+	}
+
+	for _, t := range tasks {
+		kids := children[t.ID]
+		if len(kids) == 0 {
+			continue
+		}
+		progress[t.ID] = compute(t.ID)
+		s := subtaskStats{total: len(kids)}
+		for _, kid := range kids {
+			if compute(kid) == 100 {
+				s.done++
+			}
+		}
+		stats[t.ID] = s
+	}
+	return stats
+}
+
+// checklistCounts возвращает количество пунктов чек-листа и выполненных пунктов по задачам.
+func checklistCounts(tasks []models.Task) map[uint]subtaskStats {
+	counts := make(map[uint]subtaskStats, len(tasks))
+	if len(tasks) == 0 {
+		return counts
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	var rows []struct {
+		TaskID uint
+		Total  int
+		Done   int
+	}
+	if err := storage.DB.Model(&models.ChecklistItem{}).
+		Select("task_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE done) AS done").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&rows).Error; err != nil {
+		return counts
+	}
+	for _, r := range rows {
+		counts[r.TaskID] = subtaskStats{total: r.Total, done: r.Done}
+	}
+	return counts
+}
+
+// requireTaskWorker проверяет, что пользователь может вести чек-лист задачи: это автор задачи,
+// исполнитель персональной задачи или участник команды, который работает с командной задачей.
+// При отказе пишет ответ и возвращает false.
+func requireTaskWorker(c *gin.Context, user *models.User, task *models.Task) bool {
+	if task.CreatedBy == user.ID || !task.IsTeam && task.AssignedTo != nil && *task.AssignedTo == user.TelegramID {
+		return access.RequireActive(c, task.TeamID)
+	}
+	if !task.IsTeam {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Чек-лист задачи ведут её автор и исполнители", "code": "NOT_TASK_ASSIGNEE"})
+		return false
+	}
+	_, ok := access.Require(c, user, task.TeamID, access.WorkOnTasks)
+	return ok
+}
+
+func toChecklistItemResponse(item models.ChecklistItem) response.ChecklistItemResponse {
+	resp := response.ChecklistItemResponse{
+		ID:        item.ID,
+		Text:      item.Text,
+		Position:  item.Position,
+		Done:      item.Done,
+		DoneAt:    item.DoneAt,
+		CreatedAt: item.CreatedAt,
+	}
+	if item.DoneByUser != nil {
+		resp.DoneByTelegramID = item.DoneByUser.TelegramID
+		resp.DoneByName = item.DoneByUser.Name
+	}
+	return resp
+}
+
+// GetSubtasksHandler возвращает подзадачи задачи
+// @Summary Подзадачи
+// @Description Возвращает прямые подзадачи задачи по сроку. Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.TaskResponse "Подзадачи"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении подзадач"
+// @Router /tasks/{id}/subtasks [get]
+func GetSubtasksHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	var subtasks []models.Task
+	if err := storage.DB.Where("parent_id = ?", task.ID).Order("deadline, id").Find(&subtasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении подзадач"})
+		return
+	}
+	resp := toTaskResponses(subtasks)
+	if resp == nil {
+		resp = []response.TaskResponse{}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type ChecklistItemInput struct {
+	Text string `json:"text" binding:"required,max=500"`
+}
+
+type UpdateChecklistItemInput struct {
+	Text *string `json:"text" binding:"omitempty,max=500"`
+	Done *bool   `json:"done"`
+}
+
+// GetChecklistHandler возвращает чек-лист задачи
+// @Summary Чек-лист задачи
+// @Description Возвращает пункты чек-листа задачи по порядку. Доступно участникам команды, автору задачи и руководителю отдела.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Success 200 {array} response.ChecklistItemResponse "Пункты чек-листа"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении чек-листа"
+// @Router /tasks/{id}/checklist [get]
+func GetChecklistHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskViewer(c, user, &task, false) {
+		return
+	}
+
+	var items []models.ChecklistItem
+	if err := storage.DB.Preload("DoneByUser").Where("task_id = ?", task.ID).Order("position, id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении чек-листа"})
+		return
+	}
+
+	resp := make([]response.ChecklistItemResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, toChecklistItemResponse(item))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddChecklistItemHandler добавляет пункт в чек-лист задачи
+// @Summary Пункт чек-листа
+// @Description Добавляет пункт в конец чек-листа задачи. Доступно автору задачи и исполнителям: исполнителю персональной задачи или участникам команды, работающим с командной задачей.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param input body ChecklistItemInput true "Текст пункта"
+// @Success 201 {object} response.ChecklistItemResponse "Пункт добавлен"
+// @Failure 400 {object} response.ErrorResponse "Текст пункта не может быть пустым"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE"
+// @Failure 404 {object} response.ErrorResponse "Задача не найдена"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при добавлении пункта"
+// @Router /tasks/{id}/checklist [post]
+func AddChecklistItemHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	if err := storage.DB.First(&task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if !requireTaskWorker(c, user, &task) {
+		return
+	}
+
+	var input ChecklistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Текст пункта не может быть пустым"})
+		return
+	}
+
+	item := models.ChecklistItem{TaskID: task.ID, Text: input.Text, CreatedBy: user.ID}
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&models.ChecklistItem{}).Where("task_id = ?", task.ID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		item.Position = last + 1
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении пункта"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toChecklistItemResponse(item))
+}
+
+// findChecklistItem ищет пункт чек-листа из параметров :id и :item_id. При ошибке пишет ответ и возвращает false.
+func findChecklistItem(c *gin.Context, task *models.Task, item *models.ChecklistItem) bool {
+	if err := storage.DB.First(task, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return false
+	}
+	if err := storage.DB.Preload("DoneByUser").Where("task_id = ?", task.ID).First(item, c.Param("item_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Пункт чек-листа не найден", "code": "CHECKLIST_ITEM_NOT_FOUND"})
+		return false
+	}
+	return true
+}
+
+// UpdateChecklistItemHandler отмечает пункт чек-листа или меняет его текст
+// @Summary Изменение пункта чек-листа
+// @Description Отмечает пункт выполненным (done=true), снимает отметку или меняет текст. Доступно автору задачи и исполнителям.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param item_id path string true "ID пункта"
+// @Param input body UpdateChecklistItemInput true "Изменяемые поля"
+// @Success 200 {object} response.ChecklistItemResponse "Пункт изменён"
+// @Failure 400 {object} response.ErrorResponse "Текст пункта не может быть пустым"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Пункт чек-листа не найден Code: CHECKLIST_ITEM_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении пункта"
+// @Router /tasks/{id}/checklist/{item_id} [put]
+func UpdateChecklistItemHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var item models.ChecklistItem
+	if !findChecklistItem(c, &task, &item) {
+		return
+	}
+	if !requireTaskWorker(c, user, &task) {
+		return
+	}
+
+	var input UpdateChecklistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Text != nil {
+		text := strings.TrimSpace(*input.Text)
+		if text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Текст пункта не может быть пустым"})
+			return
+		}
+		item.Text = text
+	}
+	if input.Done != nil && *input.Done != item.Done {
+		item.Done = *input.Done
+		if item.Done {
+			now := time.Now()
+			item.DoneBy = &user.ID
+			item.DoneAt = &now
+			item.DoneByUser = user
+		} else {
+			item.DoneBy = nil
+			item.DoneAt = nil
+			item.DoneByUser = nil
+		}
+	}
+
+	if err := storage.DB.Omit("DoneByUser").Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении пункта"})
+		return
+	}
+	c.JSON(http.StatusOK, toChecklistItemResponse(item))
+}
+
+// DeleteChecklistItemHandler удаляет пункт чек-листа
+// @Summary Удаление пункта чек-листа
+// @Description Удаляет пункт из чек-листа задачи. Доступно автору задачи и исполнителям.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID задачи"
+// @Param item_id path string true "ID пункта"
+// @Success 200 {object} response.SuccessResponse "Пункт удалён"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Чек-лист задачи ведут её автор и исполнители Code: NOT_TASK_ASSIGNEE"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Пункт чек-листа не найден Code: CHECKLIST_ITEM_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении пункта"
+// @Router /tasks/{id}/checklist/{item_id} [delete]
+func DeleteChecklistItemHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var task models.Task
+	var item models.ChecklistItem
+	if !findChecklistItem(c, &task, &item) {
+		return
+	}
+	if !requireTaskWorker(c, user, &task) {
+		return
+	}
+
+	if err := storage.DB.Delete(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении пункта"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Пункт удалён"})
+}
@@ -0,0 +1,774 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Переменные, которые подставляются в шаблон автоматически.
+const (
+	VariableAssignee = "assignee" // Имя исполнителя, для командной задачи — название команды
+	VariableDate     = "date"     // Дата, от которой отсчитывается срок, в формате 02.01.2006
+)
+
+// maxTemplateTasks ограничивает количество задач, создаваемых по шаблону за один запрос.
+const maxTemplateTasks = 100
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([\p{L}\p{N}_]+)\s*\}\}`)
+
+// deadlineOffsetPattern — одна часть относительного срока: число и единица измерения.
+var deadlineOffsetPattern = regexp.MustCompile(`(\d+)\s*(\p{L}+)`)
+
+// deadlineUnits — единицы относительного срока и их длина в часах.
+var deadlineUnits = map[string]int{
+	"h": 1, "hour": 1, "hours": 1, "ч": 1, "час": 1, "часа": 1, "часов": 1,
+	"d": 24, "day": 24, "days": 24, "д": 24, "дн": 24, "день": 24, "дня": 24, "дней": 24,
+	"w": 24 * 7, "week": 24 * 7, "weeks": 24 * 7, "нед": 24 * 7, "неделя": 24 * 7, "недели": 24 * 7, "недель": 24 * 7,
+}
+
+// errInvalidDeadlineOffset возвращается, если относительный срок шаблона записан неверно.
+var errInvalidDeadlineOffset = errors.New(`Срок шаблона указывается относительно создания задачи, например "+3 days", "+1 week 2 days" или "+4h"`)
+
+// missingVariablesError — при создании задач по шаблону не переданы значения переменных.
+type missingVariablesError struct {
+	names []string
+}
+
+func (e missingVariablesError) Error() string {
+	return "Не заданы переменные шаблона: " + strings.Join(e.names, ", ")
+}
+
+// deadlineOffset — срок задачи относительно момента создания: дни прибавляются по календарю, часы — по времени.
+type deadlineOffset struct {
+	days, hours int
+}
+
+// parseDeadlineOffset разбирает относительный срок вида "+3 days", "+1 week 2 days", "+4h" или "+2 дня".
+func parseDeadlineOffset(raw string) (deadlineOffset, error) {
+	var offset deadlineOffset
+	rest := strings.TrimPrefix(strings.TrimSpace(strings.ToLower(raw)), "+")
+	parts := deadlineOffsetPattern.FindAllStringSubmatch(rest, -1)
+	if len(parts) == 0 || strings.TrimSpace(deadlineOffsetPattern.ReplaceAllString(rest, "")) != "" {
+		return offset, errInvalidDeadlineOffset
+	}
+	for _, part := range parts {
+		n, err := strconv.Atoi(part[1])
+		hours, ok := deadlineUnits[part[2]]
+		if err != nil || !ok || n > 3650 {
+			return offset, errInvalidDeadlineOffset
+		}
+		if hours%24 == 0 {
+			offset.days += n * hours / 24
+		} else {
+			offset.hours += n * hours
+		}
+	}
+	if offset.days == 0 && offset.hours == 0 {
+		return offset, errInvalidDeadlineOffset
+	}
+	return offset, nil
+}
+
+func (o deadlineOffset) from(start time.Time) time.Time {
+	return start.AddDate(0, 0, o.days).Add(time.Duration(o.hours) * time.Hour)
+}
+
+// templateVariables возвращает переменные шаблона в порядке появления, кроме подставляемых автоматически.
+func templateVariables(tmpl models.TaskTemplate) []string {
+	texts := []string{tmpl.Title, tmpl.Description}
+	for _, item := range tmpl.Items {
+		texts = append(texts, item.Text)
+	}
+	variables := []string{}
+	for _, text := range texts {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if name != VariableAssignee && name != VariableDate && !slices.Contains(variables, name) {
+				variables = append(variables, name)
+			}
+		}
+	}
+	return variables
+}
+
+// fillTemplate подставляет значения переменных в текст.
+func fillTemplate(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// checkTemplateVariables проверяет, что для всех переменных шаблона переданы непустые значения.
+func checkTemplateVariables(tmpl models.TaskTemplate, values map[string]string) error {
+	var missing []string
+	for _, name := range templateVariables(tmpl) {
+		if strings.TrimSpace(values[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return missingVariablesError{names: missing}
+	}
+	return nil
+}
+
+// templateTask собирает задачу по шаблону с подставленными переменными.
+func templateTask(tmpl models.TaskTemplate, values map[string]string, deadline time.Time, assignee *string, status string, createdBy uint) models.Task {
+	return models.Task{
+		Title:          fillTemplate(tmpl.Title, values),
+		Description:    fillTemplate(tmpl.Description, values),
+		Deadline:       deadline,
+		Status:         status,
+		IsTeam:         tmpl.IsTeam,
+		AssignedTo:     assignee,
+		CreatedBy:      createdBy,
+		TeamID:         tmpl.TeamID,
+		CompletionRule: tmpl.CompletionRule,
+		QuorumPercent:  tmpl.QuorumPercent,
+		Priority:       tmpl.Priority,
+		StoryPoints:    tmpl.StoryPoints,
+		EstimateHours:  tmpl.EstimateHours,
+	}
+}
+
+// templateChecklist возвращает пункты чек-листа для задачи, созданной по шаблону.
+func templateChecklist(tmpl models.TaskTemplate, values map[string]string, taskID, createdBy uint) []models.ChecklistItem {
+	items := make([]models.ChecklistItem, 0, len(tmpl.Items))
+	for i, item := range tmpl.Items {
+		items = append(items, models.ChecklistItem{
+			TaskID:    taskID,
+			Text:      fillTemplate(item.Text, values),
+			Position:  i + 1,
+			CreatedBy: createdBy,
+		})
+	}
+	return items
+}
+
+func toTaskTemplateResponses(templates []models.TaskTemplate) []response.TaskTemplateResponse {
+	members := templateMembers(storage.DB, templates)
+
+	resp := make([]response.TaskTemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		assignees := make([]response.TemplateAssigneeResponse, 0, len(t.Assignees))
+		for _, a := range t.Assignees {
+			assignees = append(assignees, response.TemplateAssigneeResponse{
+				TelegramID: a.User.TelegramID,
+				Name:       a.User.Name,
+				InTeam:     members[templateMember{t.TeamID, a.UserID}],
+			})
+		}
+		slices.SortFunc(assignees, func(a, b response.TemplateAssigneeResponse) int { return strings.Compare(a.Name, b.Name) })
+		checklist := make([]string, 0, len(t.Items))
+		for _, item := range t.Items {
+			checklist = append(checklist, item.Text)
+		}
+		resp = append(resp, response.TaskTemplateResponse{
+			ID:             t.ID,
+			TeamID:         t.TeamID,
+			Name:           t.Name,
+			Title:          t.Title,
+			Description:    t.Description,
+			Deadline:       t.Deadline,
+			IsTeam:         t.IsTeam,
+			Assignees:      assignees,
+			CompletionRule: t.CompletionRule,
+			QuorumPercent:  t.QuorumPercent,
+			Priority:       t.Priority,
+			StoryPoints:    t.StoryPoints,
+			EstimateHours:  t.EstimateHours,
+			Labels:         toLabelResponses(templateLabels(t)),
+			Checklist:      checklist,
+			Variables:      templateVariables(t),
+			CreatedBy:      t.CreatedBy,
+			CreatedAt:      t.CreatedAt,
+			UpdatedAt:      t.UpdatedAt,
+		})
+	}
+	return resp
+}
+
+// templateMember — участник команды шаблона.
+type templateMember struct {
+	TeamID uint
+	UserID uint
+}
+
+// templateMembers определяет, кто из исполнителей по умолчанию ещё состоит в команде своего шаблона.
+func templateMembers(db *gorm.DB, templates []models.TaskTemplate) map[templateMember]bool {
+	members := make(map[templateMember]bool)
+	var userIDs []uint
+	for _, t := range templates {
+		for _, a := range t.Assignees {
+			userIDs = append(userIDs, a.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return members
+	}
+	var memberships []models.TeamMembership
+	if err := db.Where("user_id IN ?", userIDs).Find(&memberships).Error; err == nil {
+		for _, m := range memberships {
+			members[templateMember{m.TeamID, m.UserID}] = true
+		}
+	}
+	return members
+}
+
+// setTemplateAssignees заменяет исполнителей по умолчанию в шаблоне задачи.
+func setTemplateAssignees(tx *gorm.DB, templateID uint, users []models.User) error {
+	if err := tx.Where("template_id = ?", templateID).Delete(&models.TaskTemplateAssignee{}).Error; err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	links := make([]models.TaskTemplateAssignee, 0, len(users))
+	for _, u := range users {
+		links = append(links, models.TaskTemplateAssignee{TemplateID: templateID, UserID: u.ID})
+	}
+	return tx.Create(&links).Error
+}
+
+// preloadTemplate загружает пункты чек-листа, метки и исполнителей по умолчанию шаблона.
+func preloadTemplate(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", orderedItems).Preload("Labels.Label").Preload("Assignees.User")
+}
+
+// orderedItems сортирует пункты чек-листа шаблона при загрузке через Preload.
+func orderedItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// errAssigneeNotInTeam возвращается, если среди исполнителей есть пользователи не из команды.
+var errAssigneeNotInTeam = errors.New("assignee not in team")
+
+// teamAssignees возвращает участников команды с указанными Telegram ID. Если кого-то нет в команде,
+// возвращает errAssigneeNotInTeam.
+func teamAssignees(db *gorm.DB, teamID uint, telegramIDs []string) (map[string]models.User, error) {
+	telegramIDs = slices.Compact(slices.Sorted(slices.Values(telegramIDs)))
+	users := make(map[string]models.User, len(telegramIDs))
+	if len(telegramIDs) == 0 {
+		return users, nil
+	}
+	var found []models.User
+	if err := db.Joins("JOIN team_memberships ON team_memberships.user_id = users.id").
+		Where("team_memberships.team_id = ? AND users.telegram_id IN ?", teamID, telegramIDs).
+		Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range found {
+		users[u.TelegramID] = u
+	}
+	if len(users) != len(telegramIDs) {
+		return nil, errAssigneeNotInTeam
+	}
+	return users, nil
+}
+
+type TemplateInput struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Title       string `json:"title" binding:"required"` // Может содержать переменные {{name}}
+	Description string `json:"description"`
+	// Срок относительно создания задачи: "+3 days", "+1 week 2 days", "+4h"
+	Deadline       string   `json:"deadline" binding:"required" example:"+3 days"`
+	IsTeam         bool     `json:"is_team"`
+	Assignees      []string `json:"assignees"` // Telegram ID исполнителей персональных задач по умолчанию
+	CompletionRule string   `json:"completion_rule" binding:"omitempty,oneof=all any quorum"`
+	QuorumPercent  int      `json:"quorum_percent" binding:"min=0,max=100"`
+	Priority       string   `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	StoryPoints    *int     `json:"story_points" binding:"omitempty,min=0"`
+	EstimateHours  *float64 `json:"estimate_hours" binding:"omitempty,min=0"`
+	LabelIDs       []uint   `json:"label_ids"`
+	Checklist      []string `json:"checklist" binding:"max=50,dive,required,max=500"` // Пункты чек-листа по умолчанию
+}
+
+type TemplateInstanceInput struct {
+	Variables map[string]string `json:"variables"` // Значения переменных шаблона
+	Assignees []string          `json:"assignees"` // Исполнители персональных задач вместо указанных в шаблоне; каждому создаётся своя задача
+	StartAt   *time.Time        `json:"start_at"`  // От этого момента отсчитывается срок шаблона, по умолчанию — сейчас
+	Deadline  *time.Time        `json:"deadline"`  // Точный срок вместо рассчитанного по шаблону
+}
+
+type InstantiateTemplateInput struct {
+	Items []TemplateInstanceInput `json:"items" binding:"required,min=1,dive"`
+}
+
+// findTemplate загружает шаблон команды из пути запроса с чек-листом, метками и исполнителями. При ошибке пишет ответ и возвращает false.
+func findTemplate(c *gin.Context, teamID uint, tmpl *models.TaskTemplate) bool {
+	if err := preloadTemplate(storage.DB).Where("team_id = ?", teamID).First(tmpl, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Шаблон не найден", "code": "TEMPLATE_NOT_FOUND"})
+		return false
+	}
+	return true
+}
+
+// saveTemplate проверяет поля шаблона и сохраняет его вместе с чек-листом, метками и исполнителями. При ошибке пишет ответ и возвращает false.
+func saveTemplate(c *gin.Context, tmpl *models.TaskTemplate, input TemplateInput) bool {
+	tmpl.Name = strings.TrimSpace(input.Name)
+	tmpl.Title = input.Title
+	tmpl.Description = input.Description
+	tmpl.Deadline = strings.TrimSpace(input.Deadline)
+	tmpl.IsTeam = input.IsTeam
+	tmpl.CompletionRule, tmpl.QuorumPercent = completionRule(input.CompletionRule, input.QuorumPercent)
+	tmpl.Priority = taskPriority(input.Priority)
+	tmpl.StoryPoints = input.StoryPoints
+	tmpl.EstimateHours = input.EstimateHours
+	tmpl.Items = nil
+	for i, text := range input.Checklist {
+		tmpl.Items = append(tmpl.Items, models.TaskTemplateItem{Text: text, Position: i + 1})
+	}
+
+	if tmpl.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Название шаблона не может быть пустым"})
+		return false
+	}
+	if _, err := parseDeadlineOffset(tmpl.Deadline); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_DEADLINE_OFFSET"})
+		return false
+	}
+	var assignees []models.User
+	if !tmpl.IsTeam {
+		users, err := teamAssignees(storage.DB, tmpl.TeamID, input.Assignees)
+		if errors.Is(err, errAssigneeNotInTeam) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+			return false
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+			return false
+		}
+		for _, u := range users {
+			assignees = append(assignees, u)
+		}
+	}
+	labels, err := teamLabels(storage.DB, tmpl.TeamID, input.LabelIDs)
+	if errors.Is(err, errLabelNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Метка не найдена в команде", "code": "LABEL_NOT_FOUND"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+		return false
+	}
+
+	var exists int64
+	if err := storage.DB.Model(&models.TaskTemplate{}).
+		Where("team_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", tmpl.TeamID, tmpl.Name, tmpl.ID).
+		Count(&exists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+		return false
+	}
+	if exists > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Шаблон с таким названием уже есть", "code": "TEMPLATE_EXISTS"})
+		return false
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if tmpl.ID != 0 {
+			if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Labels", "Assignees").Save(tmpl).Error; err != nil {
+			return err
+		}
+		if err := setTemplateLabels(tx, tmpl.ID, labels); err != nil {
+			return err
+		}
+		return setTemplateAssignees(tx, tmpl.ID, assignees)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении шаблона"})
+		return false
+	}
+
+	tmpl.Labels = tmpl.Labels[:0]
+	for _, l := range labels {
+		tmpl.Labels = append(tmpl.Labels, models.TaskTemplateLabel{TemplateID: tmpl.ID, LabelID: l.ID, Label: l})
+	}
+	tmpl.Assignees = tmpl.Assignees[:0]
+	for _, u := range assignees {
+		tmpl.Assignees = append(tmpl.Assignees, models.TaskTemplateAssignee{TemplateID: tmpl.ID, UserID: u.ID, User: u})
+	}
+	return true
+}
+
+// GetTaskTemplatesHandler возвращает шаблоны задач команды
+// @Summary Шаблоны задач команды
+// @Description Возвращает шаблоны задач команды по названию. В variables перечислены переменные {{name}}, значения которых нужно передать при создании задач; {{assignee}} и {{date}} подставляются автоматически.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {array} response.TaskTemplateResponse "Шаблоны"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении шаблонов"
+// @Router /team/templates [get]
+func GetTaskTemplatesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	var templates []models.TaskTemplate
+	if err := preloadTemplate(storage.DB).Where("team_id = ?", membership.TeamID).Order("name").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении шаблонов"})
+		return
+	}
+	c.JSON(http.StatusOK, toTaskTemplateResponses(templates))
+}
+
+// GetTaskTemplateHandler возвращает шаблон задачи
+// @Summary Шаблон задачи
+// @Description Возвращает шаблон задачи команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID шаблона"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.TaskTemplateResponse "Шаблон"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND"
+// @Router /team/templates/{id} [get]
+func GetTaskTemplateHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	var tmpl models.TaskTemplate
+	if !findTemplate(c, membership.TeamID, &tmpl) {
+		return
+	}
+	c.JSON(http.StatusOK, toTaskTemplateResponses([]models.TaskTemplate{tmpl})[0])
+}
+
+// CreateTaskTemplateHandler создаёт шаблон задачи
+// @Summary Создание шаблона задачи
+// @Description Создаёт шаблон задачи команды. В заголовке, описании и пунктах чек-листа можно использовать переменные {{name}}; {{assignee}} заменяется именем исполнителя (для командной задачи — названием команды), {{date}} — датой, от которой отсчитывается срок. Срок задаётся относительно создания задачи. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body TemplateInput true "Шаблон"
+// @Success 201 {object} response.TaskTemplateResponse "Шаблон создан"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Срок шаблона указывается относительно создания задачи, например \"+3 days\" Code: INVALID_DEADLINE_OFFSET"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Шаблон с таким названием уже есть Code: TEMPLATE_EXISTS"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении шаблона"
+// @Router /team/templates [post]
+func CreateTaskTemplateHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var input TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tmpl := models.TaskTemplate{TeamID: membership.TeamID, CreatedBy: user.ID}
+	if !saveTemplate(c, &tmpl, input) {
+		return
+	}
+	c.JSON(http.StatusCreated, toTaskTemplateResponses([]models.TaskTemplate{tmpl})[0])
+}
+
+// UpdateTaskTemplateHandler заменяет шаблон задачи
+// @Summary Изменение шаблона задачи
+// @Description Заменяет шаблон задачи целиком, включая чек-лист. Задачи, уже созданные по шаблону, не меняются. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID шаблона"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body TemplateInput true "Шаблон"
+// @Success 200 {object} response.TaskTemplateResponse "Шаблон"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Срок шаблона указывается относительно создания задачи, например \"+3 days\" Code: INVALID_DEADLINE_OFFSET"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: Метка не найдена в команде Code: LABEL_NOT_FOUND"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Шаблон с таким названием уже есть Code: TEMPLATE_EXISTS"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении шаблона"
+// @Router /team/templates/{id} [put]
+func UpdateTaskTemplateHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var tmpl models.TaskTemplate
+	if !findTemplate(c, membership.TeamID, &tmpl) {
+		return
+	}
+	var input TemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !saveTemplate(c, &tmpl, input) {
+		return
+	}
+	c.JSON(http.StatusOK, toTaskTemplateResponses([]models.TaskTemplate{tmpl})[0])
+}
+
+// DeleteTaskTemplateHandler удаляет шаблон задачи
+// @Summary Удаление шаблона задачи
+// @Description Удаляет шаблон. Задачи, созданные по шаблону, остаются. Доступно тем, кто управляет задачами команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID шаблона"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Шаблон удалён"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении шаблона"
+// @Router /team/templates/{id} [delete]
+func DeleteTaskTemplateHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var tmpl models.TaskTemplate
+	if !findTemplate(c, membership.TeamID, &tmpl) {
+		return
+	}
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.TaskTemplateLabel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", tmpl.ID).Delete(&models.TaskTemplateAssignee{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tmpl).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении шаблона"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Шаблон удалён"})
+}
+
+// InstantiateTaskTemplateHandler создаёт задачи по шаблону
+// @Summary Создание задач по шаблону
+// @Description Создаёт задачи по шаблону в одной транзакции: если хотя бы одну задачу создать нельзя, не создаётся ни одна. Каждый элемент items — свои значения переменных и срок; по командному шаблону элемент даёт одну командную задачу, по персональному — по задаче каждому исполнителю из assignees (по умолчанию — исполнители шаблона, которые ещё состоят в команде). Срок считается от start_at (по умолчанию — сейчас), если не указан deadline. Задачи получают чек-лист и метки шаблона, исполнители — уведомления. За один запрос можно создать до 100 задач.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID шаблона"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body InstantiateTemplateInput true "Значения переменных, исполнители и сроки"
+// @Success 201 {array} response.TaskResponse "Созданные задачи"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Не заданы переменные шаблона: name Code: MISSING_VARIABLES, Error: Укажите исполнителей: в шаблоне нет участников команды Code: ASSIGNEE_REQUIRED"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: Исполнитель не состоит в команде Code: ASSIGNEE_NOT_IN_TEAM, Error: За один раз можно создать не больше 100 задач Code: TOO_MANY_TASKS"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять задачами Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Шаблон не найден Code: TEMPLATE_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании задач"
+// @Router /team/templates/{id}/instantiate [post]
+func InstantiateTaskTemplateHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTasks)
+	if !ok {
+		return
+	}
+
+	var tmpl models.TaskTemplate
+	if !findTemplate(c, membership.TeamID, &tmpl) {
+		return
+	}
+	var input InstantiateTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offset, err := parseDeadlineOffset(tmpl.Deadline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задач"})
+		return
+	}
+	flow, err := workflow.ForTeam(storage.DB, tmpl.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задач"})
+		return
+	}
+	var team models.Team
+	if err := storage.DB.First(&team, tmpl.TeamID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задач"})
+		return
+	}
+
+	// Исполнители по умолчанию, которые вышли из команды, пропускаются
+	var defaults []string
+	members := templateMembers(storage.DB, []models.TaskTemplate{tmpl})
+	for _, a := range tmpl.Assignees {
+		if members[templateMember{tmpl.TeamID, a.UserID}] {
+			defaults = append(defaults, a.User.TelegramID)
+		}
+	}
+
+	// Собираем задачи до транзакции, чтобы проверить переменные и исполнителей всех элементов
+	var planned []models.Task
+	var values []map[string]string
+	for _, item := range input.Items {
+		start := time.Now()
+		if item.StartAt != nil {
+			start = *item.StartAt
+		}
+		deadline := offset.from(start)
+		if item.Deadline != nil {
+			deadline = *item.Deadline
+		}
+
+		assignees := []*string{nil}
+		if !tmpl.IsTeam {
+			ids := item.Assignees
+			if len(ids) == 0 {
+				ids = defaults
+			}
+			if len(ids) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Укажите исполнителей: в шаблоне нет участников команды", "code": "ASSIGNEE_REQUIRED"})
+				return
+			}
+			users, err := teamAssignees(storage.DB, tmpl.TeamID, ids)
+			if errors.Is(err, errAssigneeNotInTeam) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не состоит в команде", "code": "ASSIGNEE_NOT_IN_TEAM"})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задач"})
+				return
+			}
+			assignees = assignees[:0]
+			seen := make(map[string]bool, len(ids))
+			for _, id := range ids {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				telegramID := users[id].TelegramID
+				assignees = append(assignees, &telegramID)
+			}
+		}
+
+		for _, assignee := range assignees {
+			vals := map[string]string{VariableDate: start.Format("02.01.2006"), VariableAssignee: team.Name}
+			if assignee != nil {
+				vals[VariableAssignee] = assigneeTitle(false, assignee)
+			}
+			for name, value := range item.Variables {
+				vals[name] = value
+			}
+			if err := checkTemplateVariables(tmpl, vals); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "MISSING_VARIABLES"})
+				return
+			}
+			planned = append(planned, templateTask(tmpl, vals, deadline, assignee, flow.Initial(), user.ID))
+			values = append(values, vals)
+		}
+		if len(planned) > maxTemplateTasks {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("За один раз можно создать не больше %d задач", maxTemplateTasks), "code": "TOO_MANY_TASKS"})
+			return
+		}
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		labels := templateLabels(tmpl)
+		for i := range planned {
+			if err := tx.Create(&planned[i]).Error; err != nil {
+				return err
+			}
+			if err := setTaskLabels(tx, planned[i].ID, labels); err != nil {
+				return err
+			}
+			if items := templateChecklist(tmpl, values[i], planned[i].ID, user.ID); len(items) > 0 {
+				if err := tx.Create(&items).Error; err != nil {
+					return err
+				}
+			}
+			if err := recordEvent(tx, models.TaskEvent{TaskID: planned[i].ID, Type: EventCreated, ActorID: user.ID, ToStatus: planned[i].Status, TaskStatus: planned[i].Status}); err != nil {
+				return err
+			}
+			if planned[i].IsTeam {
+				if err := createAssignments(tx, &planned[i], flow); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задач"})
+		return
+	}
+
+	for _, task := range planned {
+		notifyTaskCreated(task)
+	}
+	c.JSON(http.StatusCreated, toTaskResponses(planned))
+}
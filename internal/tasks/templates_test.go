@@ -0,0 +1,71 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDeadlineOffset(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want deadlineOffset
+		err  bool
+	}{
+		{raw: "+3 days", want: deadlineOffset{days: 3}},
+		{raw: "+1 week 2 days", want: deadlineOffset{days: 9}},
+		{raw: "+4h", want: deadlineOffset{hours: 4}},
+		{raw: "+2 дня", want: deadlineOffset{days: 2}},
+		{raw: "+1 неделя 3 часа", want: deadlineOffset{days: 7, hours: 3}},
+		{raw: "  +1 Day 6 Hours ", want: deadlineOffset{days: 1, hours: 6}},
+		{raw: "5d", want: deadlineOffset{days: 5}},
+		{raw: "+2w1d12h", want: deadlineOffset{days: 15, hours: 12}},
+		{raw: "+3650 days", want: deadlineOffset{days: 3650}},
+		{raw: "", err: true},
+		{raw: "+", err: true},
+		{raw: "+3", err: true},
+		{raw: "+0 days", err: true},
+		{raw: "+3 months", err: true},
+		{raw: "+3 days later", err: true},
+		{raw: "+3 days!", err: true},
+		{raw: "-3 days", err: true},
+		{raw: "+3651 days", err: true},
+		{raw: "+99999999999999999999 days", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseDeadlineOffset(tt.raw)
+			if tt.err {
+				if !errors.Is(err, errInvalidDeadlineOffset) {
+					t.Errorf("parseDeadlineOffset(%q) error = %v; want errInvalidDeadlineOffset", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDeadlineOffset(%q): %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDeadlineOffset(%q) = %+v; want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadlineOffsetFrom(t *testing.T) {
+	start := time.Date(2025, 1, 30, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		offset deadlineOffset
+		want   time.Time
+	}{
+		{deadlineOffset{days: 3}, time.Date(2025, 2, 2, 10, 0, 0, 0, time.UTC)},
+		{deadlineOffset{hours: 20}, time.Date(2025, 1, 31, 6, 0, 0, 0, time.UTC)},
+		{deadlineOffset{days: 1, hours: 14}, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.offset.from(start); !got.Equal(tt.want) {
+			t.Errorf("%+v.from(%v) = %v; want %v", tt.offset, start, got, tt.want)
+		}
+	}
+}
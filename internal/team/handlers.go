@@ -1,566 +1,1822 @@
-package team
-
-import (
-	"crypto/rand"
-	"fmt"
-	"math/big"
-	"net/http"
-	"time"
-
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
-	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
-)
-
-type CreateTeamInput struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-}
-
-func generateInviteLink() (string, error) {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	const length = 16
-	invite := make([]byte, length)
-	for i := 0; i < length; i++ {
-		// Получаем случайное число от 0 до len(charset)-1
-		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
-		if err != nil {
-			return "", err
-		}
-		invite[i] = charset[num.Int64()]
-	}
-	return string(invite), nil
-}
-
-// CreateTeamHandler создаёт команду. Создавать команду может только менеджер.
-// @Summary Создание команды
-// @Description Создает команду, если запрос исходит от пользователя с ролью manager.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Param input body CreateTeamInput true "Данные команды"
-// @Success 200 {object} response.TeamResponse "Информация о созданной команде"
-// @Failure 400 {object} response.ErrorResponse "Ошибка валидации или отсутствует telegram_id"
-// @Failure 403 {object} response.ErrorResponse "Доступ запрещен (не менеджер)"
-// @Failure 500 {object} response.ErrorResponse "Ошибка создания команды"
-// @Router /team [post]
-func CreateTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может создать команду"})
-		return
-	}
-
-	var input CreateTeamInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	team := models.Team{
-		Name:        input.Name,
-		Description: input.Description,
-		ManagerID:   user.ID,
-	}
-
-	if err := storage.DB.Create(&team).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании команды"})
-		return
-	}
-
-	user.TeamID = &team.ID
-	if err := storage.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении пользователя"})
-		return
-	}
-
-	c.JSON(http.StatusOK, team)
-}
-
-type InviteJoinRequest struct {
-	TelegramID string `json:"telegram_id" binding:"required"`
-	InviteCode string `json:"invite_code" binding:"required"`
-}
-
-// JoinTeamHandler позволяет пользователю присоединиться к команде, используя пригласительный код.
-// @Summary Присоединение к команде
-// @Description Позволяет пользователю присоединиться к команде, используя пригласительный код.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param input body InviteJoinRequest true "Данные для присоединения к команде"
-// @Success 200 {object} response.SuccessResponse "Успешное присоединение к команде"
-// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
-// @Failure 404 {object} response.ErrorCodeResponse "Error: Команда не найдена CODE: INVITE_CODE_INVALID, Error: Команда не найдена. CODE: TEAM_NOT_FOUND, Error: Пользователь не найден. Зарегистрируйтесь через бота. CODE: USER_NOT_FOUND"
-// @Failure 409 {object} response.ErrorResponse "Вы уже присоединились к этой команде"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при присоединении к команде"
-// @Router /team/join [post]
-func JoinTeamHandler(c *gin.Context) {
-	var req InviteJoinRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Ищем активную ссылку
-	var invite models.InviteLink
-	if err := storage.DB.Where("code = ? AND expires_at > ?", req.InviteCode, time.Now()).First(&invite).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Неверный или просроченный код приглашения",
-			"code":  "INVITE_CODE_INVALID",
-		})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.First(&team, invite.TeamID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не найдена", "code": "TEAM_NOT_FOUND"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", req.TelegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден. Зарегистрируйтесь через бота.", "code": "USER_NOT_FOUND"})
-		return
-	}
-
-	if user.TeamID != nil && *user.TeamID == team.ID {
-		c.JSON(http.StatusConflict, gin.H{"message": "Вы уже присоединились к этой команде"})
-		return
-	}
-
-	user.TeamID = &team.ID
-	if err := storage.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при присоединении к команде"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Вы успешно присоединились к команде", "team": team})
-}
-
-// GetLinkTeamHandler получает ссылку-приглашение для команды
-// @Summary Получение ссылки-приглашения
-// @Description Возвращает ссылку для приглашения новых участников в команду. Доступно только для менеджеров.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {string} string "URL ссылки-приглашения"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Доступ запрещен (не менеджер)"
-// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при создании/получении ссылки-приглашения"
-// @Router /team/invite [get]
-func GetLinkTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может получить ссылку на приглашение в команду"})
-		return
-	}
-
-	if user.TeamID == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Отсутствует команда у пользователя",
-			"code":  "USER_HAS_NO_TEAM",
-		})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.Where("id = ?", user.TeamID).First(&team).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Команда не найдена",
-			"code":  "TEAM_NOT_FOUND",
-		})
-		return
-	}
-
-	code, err := generateInviteLink()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации ссылки"})
-		return
-	}
-
-	// Сохраняем ссылку с временем жизни
-	expiresAt := time.Now().Add(24 * time.Hour)
-	invite := models.InviteLink{
-		Code:      code,
-		TeamID:    team.ID,
-		ExpiresAt: expiresAt,
-	}
-
-	if err := storage.DB.Create(&invite).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения ссылки"})
-		return
-	}
-
-	// Формируем URL
-	urlLink := fmt.Sprintf("http://t.me/LamadjoTask_bot?start=%s", code)
-	c.JSON(http.StatusOK, urlLink)
-}
-
-// GetMyTeamHandler получает информацию о команде текущего пользователя
-// @Summary Получение информации о своей команде
-// @Description Возвращает данные о команде, к которой принадлежит пользователь
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {object} response.TeamResponse "Информация о команде"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Сode:USER_HAS_NO_TEAM, Error: Команда не найдена Сode:TEAM_NOT_FOUND"
-// @Router /team/my [get]
-func GetMyTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.TeamID == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Отсутствует команда у пользователя",
-			"code":  "USER_HAS_NO_TEAM",
-		})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.Where("id = ?", user.TeamID).First(&team).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Команда не найдена",
-			"code":  "TEAM_NOT_FOUND",
-		})
-	}
-	c.JSON(http.StatusOK, team)
-}
-
-// ChangeTeamHandler изменяет информацию о команде
-// @Summary Изменение информации о команде
-// @Description Обновляет название и описание команды. Доступно только для менеджеров.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Param input body CreateTeamInput true "Данные для обновления команды"
-// @Success 200 {object} response.SuccessResponse "Команда успешно обновлена"
-// @Failure 400 {object} response.ErrorResponse "Ошибка валидации или отсутствует telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Доступ запрещен (не менеджер)"
-// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении команды"
-// @Router /team [put]
-func ChangeTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var input CreateTeamInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может получить ссылку на приглашение в команду"})
-		return
-	}
-
-	if user.TeamID == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Отсутствует команда у пользователя",
-			"code":  "USER_HAS_NO_TEAM",
-		})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.Where("id = ?", user.TeamID).First(&team).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Команда не найдена",
-			"code":  "TEAM_NOT_FOUND",
-		})
-	}
-
-	team.Name = input.Name
-	team.Description = input.Description
-
-	if err := storage.DB.Save(&team).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении команды"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Команда успешно обновлена"})
-}
-
-// GetMembersTeam получает список участников команды
-// @Summary Получение списка участников команды
-// @Description Возвращает список всех участников команды, кроме текущего пользователя. Доступно только для менеджеров.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {array} response.UserResponse "Список участников команды"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id или ошибка валидации"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Доступ запрещен (не менеджер)"
-// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при получении участников команды"
-// @Router /team/members [get]
-func GetMembersTeam(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может получить ссылку на приглашение в команду"})
-		return
-	}
-
-	if user.TeamID == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Отсутствует команда у пользователя",
-			"code":  "USER_HAS_NO_TEAM",
-		})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.Where("id = ?", user.TeamID).First(&team).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Команда не найдена",
-			"code":  "TEAM_NOT_FOUND",
-		})
-	}
-
-	var member []models.User
-	if err := storage.DB.Where("team_id = ? AND telegram_id != ?", user.TeamID, telegramID).Find(&member).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении участников команды"})
-		return
-	}
-	c.JSON(http.StatusOK, member)
-}
-
-// LeaveMemberTeamHandler позволяет пользователю покинуть текущую команду
-// @Summary Покинуть команду
-// @Description Позволяет пользователю выйти из текущей команды
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {object} response.SuccessResponse "Команда успешно покинута"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Manager не может просто так покинуть команду"
-// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при попытке покинуть команду"
-// @Router /team/leave [get]
-func LeaveMemberTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role == "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Manager не может просто так покинуть команду"})
-		return
-	}
-
-	if user.TeamID == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Отсутствует команда у пользователя",
-			"code":  "USER_HAS_NO_TEAM",
-		})
-		return
-	}
-
-	user.TeamID = nil
-	if err := storage.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при попытке покинуть команду"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Команда покинута"})
-}
-
-// KickMemberTeamHandler позволяет менеджеру исключить участника из команды
-// @Summary Исключить участника из команды
-// @Description Позволяет менеджеру исключить участника из команды
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Param kick_telegram_id query string true "Уникальный идентификатор Telegram участника, который будет исключен"
-// @Success 200 {object} response.SuccessResponse "Участник успешно исключен из команды"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id или kick_telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorResponse "Error: Только менеджер может исключить участника из команды. CODE: NOT_MANAGER, Error: Пользователь не находится в вашей команде, CODE: NOT_IN_TEAM"
-// @Failure 500 {{object} response.ErrorResponse "Ошибка при попытке исключить участника из команды"
-// @Router /team/kick [get]
-func KickMemberTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-	kickTelegramID := c.Query("kick_telegram_id")
-	if kickTelegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "kick_telegram_id is required"})
-		return
-	}
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только менеджер может исключить участника из команды", "code": "NOT_MANAGER"})
-		return
-	}
-	var userKick models.User
-	if err := storage.DB.Where("telegram_id = ?", kickTelegramID).First(&userKick).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if *userKick.TeamID != *user.TeamID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
-		return
-	}
-
-	userKick.TeamID = nil
-
-	if err := storage.DB.Save(&userKick).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при попытке исключить участника из команды"})
-		return
-	}
-
-	notificationText := fmt.Sprintf(
-		"😕 *Мы сожелеем, но... *\n\n" +
-			"Вы были исключены из команды...",
-	)
-	if err := notification.SendTelegramNotification(userKick.TelegramID, notificationText); err != nil {
-		fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", userKick.TelegramID, err)
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Участник успешно исключен из команды"})
-}
-
-// DeleteTeamHandler удаляет команду и очищает связи с участниками
-// @Summary Удаление команды
-// @Description Удаляет команду и очищает связи со всеми участниками. Доступно только для владельца команды.
-// @Tags team
-// @Accept json
-// @Produce json
-// @Param telegram_id query string true "Уникальный идентификатор Telegram"
-// @Success 200 {object} response.SuccessResponse "Команда успешно удалена"
-// @Failure 400 {object} response.ErrorResponse "Отсутствует telegram_id"
-// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
-// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может удалить команду Code: ONLY_MANAGER_DELETE_TEAM, Error: Вы не являетесь владельцем команды Code: NOT_OWNER_OF_TEAM"
-// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении команды"
-// @Router /team [delete]
-func DeleteTeamHandler(c *gin.Context) {
-	telegramID := c.Query("telegram_id")
-	if telegramID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "telegram_id is required"})
-		return
-	}
-
-	var user models.User
-	if err := storage.DB.Where("telegram_id = ?", telegramID).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
-		return
-	}
-
-	if user.Role != "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только руководитель может удалить команду", "code": "ONLY_MANAGER_DELETE_TEAM"})
-		return
-	}
-
-	var team models.Team
-	if err := storage.DB.Where("manager_id = ?", user.ID).First(&team).Error; err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Вы не являетесь владельцем команды", "code": "NOT_OWNER_OF_TEAM"})
-		return
-	}
-
-	// Удаляем команду и обнуляем TeamID у участников
-	err := storage.DB.Transaction(func(tx *gorm.DB) error {
-		// Обнуляем TeamID у всех участников команды
-		if err := tx.Model(&models.User{}).
-			Where("team_id = ?", team.ID).
-			Update("team_id", nil).
-			Error; err != nil {
-			return err
-		}
-
-		// Удаляем саму команду
-		if err := tx.Delete(&team).Error; err != nil {
-			return err
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении команды"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Команда успешно удалена"})
-}
+package team
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/blob"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/notification"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreateTeamInput struct {
+	Name             string `json:"name" binding:"required"`
+	Description      string `json:"description"`
+	RequiresApproval *bool  `json:"requires_approval"` // Вступление по приглашению только после одобрения руководителем; если не передано при изменении — не меняется
+}
+
+func generateInviteLink() (string, error) {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	const length = 16
+	invite := make([]byte, length)
+	for i := 0; i < length; i++ {
+		// Получаем случайное число от 0 до len(charset)-1
+		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		invite[i] = charset[num.Int64()]
+	}
+	return string(invite), nil
+}
+
+// CreateTeamHandler создаёт команду. Создатель становится её владельцем.
+// @Summary Создание команды
+// @Description Создает команду. Пользователь, создавший команду, получает в ней роль owner, новая команда становится активной.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body CreateTeamInput true "Данные команды"
+// @Success 200 {object} response.TeamResponse "Информация о созданной команде"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 500 {object} response.ErrorResponse "Ошибка создания команды"
+// @Router /team [post]
+func CreateTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var input CreateTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team := models.Team{
+		Name:             input.Name,
+		Description:      input.Description,
+		ManagerID:        user.ID,
+		RequiresApproval: input.RequiresApproval != nil && *input.RequiresApproval,
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&team).Error; err != nil {
+			return err
+		}
+
+		membership := models.TeamMembership{UserID: user.ID, TeamID: team.ID, Role: access.RoleOwner}
+		if err := tx.Create(&membership).Error; err != nil {
+			return err
+		}
+
+		user.TeamID = &team.ID
+		return tx.Save(user).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+type InviteJoinRequest struct {
+	InviteCode string `json:"invite_code" binding:"required"`
+}
+
+// JoinTeamHandler позволяет пользователю присоединиться к команде, используя пригласительный код.
+// @Summary Присоединение к команде
+// @Description Позволяет пользователю присоединиться к команде, используя пригласительный код. Роль в команде задаётся приглашением. Пользователь может состоять в нескольких командах, новая команда становится активной.
+// @Description Если команда требует одобрения, вместо вступления создаётся заявка (ответ 202), а владелец и руководители получают уведомление с кнопками одобрения и отклонения. Заявка расходует одно использование приглашения.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body InviteJoinRequest true "Данные для присоединения к команде"
+// @Success 200 {object} response.SuccessResponse "Успешное присоединение к команде"
+// @Success 202 {object} response.JoinRequestResponse "Заявка на вступление отправлена руководителю"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Неверный или просроченный код приглашения CODE: INVITE_CODE_INVALID, Error: Приглашение уже использовано максимальное число раз CODE: INVITE_EXHAUSTED, Error: Команда не найдена. CODE: TEAM_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Вы уже присоединились к этой команде, Error: Заявка на вступление уже ожидает решения Code: JOIN_REQUEST_PENDING, Error: Команда в архиве, вступление закрыто Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при присоединении к команде"
+// @Router /team/join [post]
+func JoinTeamHandler(c *gin.Context) {
+	var req InviteJoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Ищем активную ссылку
+	var invite models.InviteLink
+	if err := storage.DB.Where("code = ? AND expires_at > ? AND revoked_at IS NULL", req.InviteCode, time.Now()).First(&invite).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Неверный или просроченный код приглашения",
+			"code":  "INVITE_CODE_INVALID",
+		})
+		return
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, invite.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не найдена", "code": "TEAM_NOT_FOUND"})
+		return
+	}
+	if team.ArchivedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Команда в архиве, вступление закрыто", "code": "TEAM_ARCHIVED"})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	if _, err := access.GetMembership(user.ID, team.ID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"message": "Вы уже присоединились к этой команде"})
+		return
+	}
+
+	if team.RequiresApproval {
+		createJoinRequest(c, user, &team, &invite)
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useInvite(tx, invite.ID); err != nil {
+			return err
+		}
+
+		membership := models.TeamMembership{UserID: user.ID, TeamID: team.ID, Role: invite.Role}
+		if err := tx.Create(&membership).Error; err != nil {
+			return err
+		}
+
+		use := models.InviteUse{InviteLinkID: invite.ID, TeamID: team.ID, UserID: user.ID, Role: invite.Role}
+		if err := tx.Create(&use).Error; err != nil {
+			return err
+		}
+
+		user.TeamID = &team.ID
+		return tx.Save(user).Error
+	})
+	if errors.Is(err, errInviteExhausted) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Приглашение уже использовано максимальное число раз",
+			"code":  "INVITE_EXHAUSTED",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при присоединении к команде"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Вы успешно присоединились к команде", "team": team})
+}
+
+// createJoinRequest создаёт заявку на вступление в команду, которая требует одобрения, и уведомляет руководителей.
+func createJoinRequest(c *gin.Context, user *models.User, team *models.Team, invite *models.InviteLink) {
+	var pending int64
+	if err := storage.DB.Model(&models.JoinRequest{}).
+		Where("team_id = ? AND user_id = ? AND status = ?", team.ID, user.ID, "pending").
+		Count(&pending).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отправке заявки"})
+		return
+	}
+	if pending > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Заявка на вступление уже ожидает решения", "code": "JOIN_REQUEST_PENDING"})
+		return
+	}
+
+	request := models.JoinRequest{
+		TeamID:       team.ID,
+		UserID:       user.ID,
+		InviteLinkID: invite.ID,
+		Role:         invite.Role,
+		Status:       "pending",
+	}
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useInvite(tx, invite.ID); err != nil {
+			return err
+		}
+		return tx.Create(&request).Error
+	})
+	if errors.Is(err, errInviteExhausted) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Приглашение уже использовано максимальное число раз",
+			"code":  "INVITE_EXHAUSTED",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отправке заявки"})
+		return
+	}
+
+	managers, err := access.TeamManagers(team.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения руководителей команды %d: %v\n", team.ID, err)
+	}
+
+	notificationText := fmt.Sprintf(
+		"📥 *Заявка на вступление*\n\n"+
+			"%s хочет вступить в команду *%s* с ролью %s.",
+		user.Name,
+		team.Name,
+		request.Role,
+	)
+	buttons := []notification.InlineButton{
+		{Text: "✅ Одобрить", CallbackData: fmt.Sprintf("approve_join_%d", request.ID)},
+		{Text: "❌ Отклонить", CallbackData: fmt.Sprintf("reject_join_%d", request.ID)},
+	}
+	for _, manager := range managers {
+		go func(chatID string) {
+			if err := notification.SendTelegramNotificationWithButtons(chatID, notificationText, buttons); err != nil {
+				fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+			}
+		}(manager.TelegramID)
+	}
+
+	request.User = *user
+	c.JSON(http.StatusAccepted, toJoinRequestResponse(request))
+}
+
+func toJoinRequestResponse(request models.JoinRequest) response.JoinRequestResponse {
+	resp := response.JoinRequestResponse{
+		ID:         request.ID,
+		TeamID:     request.TeamID,
+		TelegramID: request.User.TelegramID,
+		Name:       request.User.Name,
+		Role:       request.Role,
+		Status:     request.Status,
+		CreatedAt:  request.CreatedAt,
+		DecidedAt:  request.DecidedAt,
+	}
+	if request.DecidedBy != nil {
+		var manager models.User
+		if err := storage.DB.First(&manager, *request.DecidedBy).Error; err == nil {
+			resp.DecidedByTelegramID = manager.TelegramID
+		}
+	}
+	return resp
+}
+
+// GetJoinRequestsHandler возвращает заявки на вступление в команду
+// @Summary Заявки на вступление в команду
+// @Description Возвращает ожидающие решения заявки на вступление. С параметром status=all возвращается и история: одобренные и отклонённые заявки с датой решения и руководителем, который его принял. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param status query string false "pending (по умолчанию), approved, rejected или all"
+// @Success 200 {array} response.JoinRequestResponse "Список заявок"
+// @Failure 400 {object} response.ErrorResponse "Некорректный статус"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении заявок"
+// @Router /team/requests [get]
+func GetJoinRequestsHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	query := storage.DB.Preload("User").Where("team_id = ?", membership.TeamID)
+	switch status := c.DefaultQuery("status", "pending"); status {
+	case "all":
+	case "pending", "approved", "rejected":
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный статус заявки"})
+		return
+	}
+
+	var requests []models.JoinRequest
+	if err := query.Order("created_at DESC").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении заявок"})
+		return
+	}
+
+	resp := make([]response.JoinRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		resp = append(resp, toJoinRequestResponse(request))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+var errJoinRequestStale = errors.New("join request already decided")
+
+// findPendingJoinRequest ищет заявку из пути запроса и проверяет, что пользователь может её рассмотреть.
+// При ошибке пишет ответ.
+func findPendingJoinRequest(c *gin.Context, user *models.User) (*models.JoinRequest, bool) {
+	var request models.JoinRequest
+	if err := storage.DB.Preload("User").First(&request, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Заявка не найдена", "code": "JOIN_REQUEST_NOT_FOUND"})
+		return nil, false
+	}
+
+	if _, ok := access.Require(c, user, request.TeamID, access.ManageTeam); !ok {
+		return nil, false
+	}
+
+	if request.Status != "pending" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Заявка уже рассмотрена", "code": "JOIN_REQUEST_CLOSED"})
+		return nil, false
+	}
+	return &request, true
+}
+
+// decideJoinRequest переводит заявку из pending в указанный статус.
+// Условное обновление защищает от повторного решения параллельными запросами.
+func decideJoinRequest(tx *gorm.DB, request *models.JoinRequest, status string, decidedBy uint) error {
+	now := time.Now()
+	result := tx.Model(&models.JoinRequest{}).
+		Where("id = ? AND status = ?", request.ID, "pending").
+		Updates(map[string]interface{}{"status": status, "decided_by": decidedBy, "decided_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errJoinRequestStale
+	}
+
+	request.Status = status
+	request.DecidedBy = &decidedBy
+	request.DecidedAt = &now
+	return nil
+}
+
+// ApproveJoinRequestHandler одобряет заявку на вступление в команду
+// @Summary Одобрение заявки на вступление
+// @Description Пользователь становится участником команды с ролью из приглашения и получает уведомление. Если у него нет активной команды, она становится активной. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID заявки"
+// @Success 200 {object} response.JoinRequestResponse "Заявка одобрена"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Заявка не найдена Code: JOIN_REQUEST_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Заявка уже рассмотрена Code: JOIN_REQUEST_CLOSED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при одобрении заявки"
+// @Router /team/requests/{id}/approve [post]
+func ApproveJoinRequestHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	request, ok := findPendingJoinRequest(c, user)
+	if !ok {
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := decideJoinRequest(tx, request, "approved", user.ID); err != nil {
+			return err
+		}
+
+		// Пока заявка ждала решения, пользователь мог вступить в команду по другому приглашению
+		var existing int64
+		if err := tx.Model(&models.TeamMembership{}).
+			Where("user_id = ? AND team_id = ?", request.UserID, request.TeamID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		membership := models.TeamMembership{UserID: request.UserID, TeamID: request.TeamID, Role: request.Role}
+		if err := tx.Create(&membership).Error; err != nil {
+			return err
+		}
+
+		use := models.InviteUse{InviteLinkID: request.InviteLinkID, TeamID: request.TeamID, UserID: request.UserID, Role: request.Role}
+		if err := tx.Create(&use).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ? AND team_id IS NULL", request.UserID).
+			Update("team_id", request.TeamID).Error
+	})
+	if errors.Is(err, errJoinRequestStale) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Заявка уже рассмотрена", "code": "JOIN_REQUEST_CLOSED"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при одобрении заявки"})
+		return
+	}
+
+	notifyJoinDecision(request, "✅ *Заявка одобрена*\n\nВы вступили в команду *%s*.")
+	c.JSON(http.StatusOK, toJoinRequestResponse(*request))
+}
+
+// RejectJoinRequestHandler отклоняет заявку на вступление в команду
+// @Summary Отклонение заявки на вступление
+// @Description Заявка отклоняется, пользователь получает уведомление. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID заявки"
+// @Success 200 {object} response.JoinRequestResponse "Заявка отклонена"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Заявка не найдена Code: JOIN_REQUEST_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Заявка уже рассмотрена Code: JOIN_REQUEST_CLOSED, Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при отклонении заявки"
+// @Router /team/requests/{id}/reject [post]
+func RejectJoinRequestHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	request, ok := findPendingJoinRequest(c, user)
+	if !ok {
+		return
+	}
+
+	err := decideJoinRequest(storage.DB, request, "rejected", user.ID)
+	if errors.Is(err, errJoinRequestStale) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Заявка уже рассмотрена", "code": "JOIN_REQUEST_CLOSED"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отклонении заявки"})
+		return
+	}
+
+	notifyJoinDecision(request, "❌ *Заявка отклонена*\n\nРуководитель команды *%s* отклонил вашу заявку на вступление.")
+	c.JSON(http.StatusOK, toJoinRequestResponse(*request))
+}
+
+// notifyJoinDecision сообщает автору заявки о решении. format получает название команды.
+func notifyJoinDecision(request *models.JoinRequest, format string) {
+	var team models.Team
+	if err := storage.DB.First(&team, request.TeamID).Error; err != nil {
+		return
+	}
+
+	notificationText := fmt.Sprintf(format, team.Name)
+	go func(chatID string) {
+		if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+		}
+	}(request.User.TelegramID)
+}
+
+// useInvite учитывает использование приглашения. Условное увеличение счётчика не даёт
+// использовать одноразовую ссылку дважды параллельными запросами.
+func useInvite(tx *gorm.DB, inviteID uint) error {
+	result := tx.Model(&models.InviteLink{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", inviteID).
+		UpdateColumn("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInviteExhausted
+	}
+	return nil
+}
+
+// inviteURLFormat — ссылка на бота, который по параметру start присоединяет пользователя к команде.
+const inviteURLFormat = "http://t.me/LamadjoTask_bot?start=%s"
+
+var errInviteExhausted = errors.New("invite exhausted")
+
+type CreateInviteInput struct {
+	Role           string `json:"role" binding:"omitempty,oneof=manager member observer"` // По умолчанию member
+	MaxUses        int    `json:"max_uses" binding:"min=0"`                               // 0 — без ограничения, 1 — одноразовая ссылка
+	ExpiresInHours int    `json:"expires_in_hours" binding:"min=0,max=720"`               // По умолчанию 24
+}
+
+func toInviteResponse(invite models.InviteLink) response.InviteResponse {
+	return response.InviteResponse{
+		Code:      invite.Code,
+		URL:       fmt.Sprintf(inviteURLFormat, invite.Code),
+		Role:      invite.Role,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		Active:    invite.RevokedAt == nil && invite.ExpiresAt.After(time.Now()) && (invite.MaxUses == 0 || invite.Uses < invite.MaxUses),
+		ExpiresAt: invite.ExpiresAt,
+		RevokedAt: invite.RevokedAt,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+// createInvite создаёт ссылку-приглашение. Приглашать сразу в руководители может только владелец.
+// При ошибке пишет ответ и возвращает false.
+func createInvite(c *gin.Context, membership *models.TeamMembership, input CreateInviteInput) (*models.InviteLink, bool) {
+	if input.Role == "" {
+		input.Role = access.RoleMember
+	}
+	if input.ExpiresInHours == 0 {
+		input.ExpiresInHours = 24
+	}
+
+	if input.Role == access.RoleManager && membership.Role != access.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Приглашать руководителей может только владелец", "code": "ROLE_TOO_HIGH"})
+		return nil, false
+	}
+
+	code, err := generateInviteLink()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка генерации ссылки"})
+		return nil, false
+	}
+
+	invite := models.InviteLink{
+		Code:      code,
+		TeamID:    membership.TeamID,
+		Role:      input.Role,
+		MaxUses:   input.MaxUses,
+		CreatedBy: membership.UserID,
+		ExpiresAt: time.Now().Add(time.Duration(input.ExpiresInHours) * time.Hour),
+	}
+
+	if err := storage.DB.Create(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка сохранения ссылки"})
+		return nil, false
+	}
+	return &invite, true
+}
+
+// findTeamInvite ищет ссылку-приглашение команды по коду из пути запроса. При ошибке пишет ответ.
+func findTeamInvite(c *gin.Context, teamID uint) (*models.InviteLink, bool) {
+	var invite models.InviteLink
+	if err := storage.DB.Where("code = ? AND team_id = ?", c.Param("code"), teamID).First(&invite).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ссылка-приглашение не найдена", "code": "INVITE_NOT_FOUND"})
+		return nil, false
+	}
+	return &invite, true
+}
+
+// GetLinkTeamHandler получает ссылку-приглашение для команды
+// @Summary Получение ссылки-приглашения
+// @Description Создаёт ссылку для приглашения новых участников в команду с настройками по умолчанию: роль member, без ограничения использований, срок 24 часа. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {string} string "URL ссылки-приглашения"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании/получении ссылки-приглашения"
+// @Router /team/invite [get]
+func GetLinkTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	invite, ok := createInvite(c, membership, CreateInviteInput{})
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, fmt.Sprintf(inviteURLFormat, invite.Code))
+}
+
+// CreateInviteHandler создаёт ссылку-приглашение с заданными ограничениями
+// @Summary Создание ссылки-приглашения
+// @Description Создаёт ссылку с ролью для вступивших, ограничением числа использований и сроком действия (до 720 часов). Приглашать сразу в руководители может только владелец.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body CreateInviteInput true "Параметры приглашения"
+// @Success 200 {object} response.InviteResponse "Созданная ссылка"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN, Error: Приглашать руководителей может только владелец Code: ROLE_TOO_HIGH"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка сохранения ссылки"
+// @Router /team/invites [post]
+func CreateInviteHandler(c *gin.Context) {
+	var input CreateInviteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	invite, ok := createInvite(c, membership, input)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toInviteResponse(*invite))
+}
+
+// GetInvitesHandler возвращает ссылки-приглашения команды
+// @Summary Список ссылок-приглашений
+// @Description Возвращает действующие ссылки-приглашения команды со счётчиками использований. С параметром all=true возвращает также отозванные, истёкшие и исчерпанные ссылки.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param all query bool false "Включить недействующие ссылки"
+// @Success 200 {array} response.InviteResponse "Ссылки-приглашения"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении ссылок"
+// @Router /team/invites [get]
+func GetInvitesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	query := storage.DB.Where("team_id = ?", membership.TeamID)
+	if c.Query("all") != "true" {
+		query = query.Where("revoked_at IS NULL AND expires_at > ? AND (max_uses = 0 OR uses < max_uses)", time.Now())
+	}
+
+	var invites []models.InviteLink
+	if err := query.Order("created_at DESC").Find(&invites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении ссылок"})
+		return
+	}
+
+	resp := make([]response.InviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		resp = append(resp, toInviteResponse(invite))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetInviteUsesHandler возвращает, кто вступил в команду по ссылке
+// @Summary Использования ссылки-приглашения
+// @Description Возвращает пользователей, вступивших в команду по ссылке, с ролью и временем вступления.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param code path string true "Код приглашения"
+// @Success 200 {array} response.InviteUseResponse "Вступившие пользователи"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Ссылка-приглашение не найдена Code: INVITE_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении использований"
+// @Router /team/invites/{code}/uses [get]
+func GetInviteUsesHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	invite, ok := findTeamInvite(c, membership.TeamID)
+	if !ok {
+		return
+	}
+
+	var uses []models.InviteUse
+	if err := storage.DB.Preload("User").Where("invite_link_id = ?", invite.ID).Order("created_at").Find(&uses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении использований"})
+		return
+	}
+
+	resp := make([]response.InviteUseResponse, 0, len(uses))
+	for _, use := range uses {
+		resp = append(resp, response.InviteUseResponse{
+			TelegramID: use.User.TelegramID,
+			Name:       use.User.Name,
+			Role:       use.Role,
+			JoinedAt:   use.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeInviteHandler отзывает ссылку-приглашение
+// @Summary Отзыв ссылки-приглашения
+// @Description Отзывает ссылку: по ней больше нельзя вступить в команду. Запись остаётся в списке с параметром all=true.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param code path string true "Код приглашения"
+// @Success 200 {object} response.InviteResponse "Отозванная ссылка"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Ссылка-приглашение не найдена Code: INVITE_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при отзыве ссылки"
+// @Router /team/invites/{code} [delete]
+func RevokeInviteHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	invite, ok := findTeamInvite(c, membership.TeamID)
+	if !ok {
+		return
+	}
+
+	if invite.RevokedAt == nil {
+		now := time.Now()
+		invite.RevokedAt = &now
+		if err := storage.DB.Save(invite).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отзыве ссылки"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, toInviteResponse(*invite))
+}
+
+// GetMyTeamHandler получает список команд текущего пользователя
+// @Summary Получение списка своих команд
+// @Description Возвращает все команды, в которых состоит пользователь, с его ролью в каждой и отметкой активной команды
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} response.MyTeamResponse "Команды пользователя"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении команд"
+// @Router /team/my [get]
+func GetMyTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var memberships []models.TeamMembership
+	if err := storage.DB.Where("user_id = ?", user.ID).Order("created_at").Find(&memberships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении команд"})
+		return
+	}
+
+	roles := make(map[uint]string, len(memberships))
+	teamIDs := make([]uint, 0, len(memberships))
+	for _, m := range memberships {
+		roles[m.TeamID] = m.Role
+		teamIDs = append(teamIDs, m.TeamID)
+	}
+
+	var teams []models.Team
+	if len(teamIDs) > 0 {
+		if err := storage.DB.Where("id IN ?", teamIDs).Order("name").Find(&teams).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении команд"})
+			return
+		}
+	}
+
+	resp := make([]response.MyTeamResponse, 0, len(teams))
+	for _, team := range teams {
+		resp = append(resp, response.MyTeamResponse{
+			ID:               team.ID,
+			Name:             team.Name,
+			Description:      team.Description,
+			Role:             roles[team.ID],
+			IsActive:         user.TeamID != nil && *user.TeamID == team.ID,
+			RequiresApproval: team.RequiresApproval,
+			ArchivedAt:       team.ArchivedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type SetActiveTeamInput struct {
+	TeamID uint `json:"team_id" binding:"required"`
+}
+
+// SetActiveTeamHandler выбирает активную команду пользователя
+// @Summary Выбор активной команды
+// @Description Делает команду активной: запросы к задачам, встречам и команде без параметра team_id выполняются для неё.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body SetActiveTeamInput true "ID команды"
+// @Success 200 {object} response.MyTeamResponse "Активная команда"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Команда не найдена Code: TEAM_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при выборе команды"
+// @Router /team/active [put]
+func SetActiveTeamHandler(c *gin.Context) {
+	var input SetActiveTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	membership, ok := access.Require(c, user, input.TeamID, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, membership.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не найдена", "code": "TEAM_NOT_FOUND"})
+		return
+	}
+
+	user.TeamID = &team.ID
+	if err := storage.DB.Save(user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при выборе команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.MyTeamResponse{
+		ID:               team.ID,
+		Name:             team.Name,
+		Description:      team.Description,
+		Role:             membership.Role,
+		IsActive:         true,
+		RequiresApproval: team.RequiresApproval,
+		ArchivedAt:       team.ArchivedAt,
+	})
+}
+
+// ChangeTeamHandler изменяет информацию о команде
+// @Summary Изменение информации о команде
+// @Description Обновляет название, описание и настройку одобрения заявок на вступление. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body CreateTeamInput true "Данные для обновления команды"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Команда успешно обновлена"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при обновлении команды"
+// @Router /team [put]
+func ChangeTeamHandler(c *gin.Context) {
+	var input CreateTeamInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, membership.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Команда не найдена",
+			"code":  "TEAM_NOT_FOUND",
+		})
+		return
+	}
+
+	team.Name = input.Name
+	team.Description = input.Description
+	if input.RequiresApproval != nil {
+		team.RequiresApproval = *input.RequiresApproval
+	}
+
+	if err := storage.DB.Save(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда успешно обновлена"})
+}
+
+// GetMembersTeam получает список участников команды
+// @Summary Получение списка участников команды
+// @Description Возвращает список всех участников команды с их ролями, кроме текущего пользователя. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {array} response.MemberResponse "Список участников команды"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error:Отсутствует команда у пользователя Code:USER_HAS_NO_TEAM, Error:Команда не найдена Code:TEAM_NOT_FOUND"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении участников команды"
+// @Router /team/members [get]
+func GetMembersTeam(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	membership, ok := access.RequireTeamRead(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	var memberships []models.TeamMembership
+	if err := storage.DB.Preload("User").Where("team_id = ? AND user_id != ?", membership.TeamID, user.ID).Find(&memberships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении участников команды"})
+		return
+	}
+
+	members := make([]response.MemberResponse, 0, len(memberships))
+	for _, m := range memberships {
+		members = append(members, response.MemberResponse{
+			TelegramID: m.User.TelegramID,
+			Name:       m.User.Name,
+			Role:       m.Role,
+		})
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// LeaveMemberTeamHandler позволяет пользователю покинуть текущую команду
+// @Summary Покинуть команду
+// @Description Позволяет пользователю выйти из текущей команды
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Команда успешно покинута"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Владелец не может покинуть команду. Сначала передайте права владельца другому участнику Code: OWNER_CANNOT_LEAVE"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при попытке покинуть команду"
+// @Router /team/leave [get]
+func LeaveMemberTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	membership, ok := access.RequireTeam(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	if membership.Role == access.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Владелец не может покинуть команду. Сначала передайте права владельца другому участнику", "code": "OWNER_CANNOT_LEAVE"})
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(membership).Error; err != nil {
+			return err
+		}
+
+		return access.ResetActiveTeam(tx, user.ID, membership.TeamID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при попытке покинуть команду"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда покинута"})
+}
+
+// KickMemberTeamHandler позволяет менеджеру исключить участника из команды
+// @Summary Исключить участника из команды
+// @Description Позволяет владельцу или руководителю исключить участника из команды. Руководителей может исключить только владелец, владельца исключить нельзя.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param kick_telegram_id query string true "Уникальный идентификатор Telegram участника, который будет исключен"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Участник успешно исключен из команды"
+// @Failure 400 {object} response.ErrorResponse "Отсутствует kick_telegram_id"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой CODE: FORBIDDEN, Error: Пользователь не находится в вашей команде CODE: NOT_IN_TEAM, Error: Недостаточно прав для исключения этого участника CODE: ROLE_TOO_HIGH"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {{object} response.ErrorResponse "Ошибка при попытке исключить участника из команды"
+// @Router /team/kick [get]
+func KickMemberTeamHandler(c *gin.Context) {
+	kickTelegramID := c.Query("kick_telegram_id")
+	if kickTelegramID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kick_telegram_id is required"})
+		return
+	}
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+	var userKick models.User
+	if err := storage.DB.Where("telegram_id = ?", kickTelegramID).First(&userKick).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	kickMembership, err := access.GetMembership(userKick.ID, membership.TeamID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
+		return
+	}
+
+	if !canManageMember(membership.Role, kickMembership.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав для исключения этого участника", "code": "ROLE_TOO_HIGH"})
+		return
+	}
+
+	err = storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(kickMembership).Error; err != nil {
+			return err
+		}
+
+		return access.ResetActiveTeam(tx, userKick.ID, membership.TeamID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при попытке исключить участника из команды"})
+		return
+	}
+
+	notificationText := fmt.Sprintf(
+		"😕 *Мы сожелеем, но... *\n\n" +
+			"Вы были исключены из команды...",
+	)
+	if err := notification.SendTelegramNotification(userKick.TelegramID, notificationText); err != nil {
+		fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", userKick.TelegramID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Участник успешно исключен из команды"})
+}
+
+// ArchiveTeamHandler отправляет команду в архив
+// @Summary Архивация команды
+// @Description Переводит команду в архив: задачи и встречи доступны только для просмотра, вступление по приглашениям и управление командой отключены, неотвеченные предложения о передаче команды отменяются. Участники и данные команды сохраняются, команду можно восстановить. Доступно только владельцу команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Команда перенесена в архив"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только владелец может удалить команду Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда уже в архиве Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при архивации команды"
+// @Router /team/archive [post]
+// @Router /team [delete]
+func ArchiveTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	membership, ok := access.RequireTeam(c, user, access.DeleteTeam)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, membership.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не найдена", "code": "TEAM_NOT_FOUND"})
+		return
+	}
+	if team.ArchivedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Команда уже в архиве", "code": "TEAM_ARCHIVED"})
+		return
+	}
+
+	now := time.Now()
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&team).Updates(map[string]interface{}{"archived_at": now, "archived_by": user.ID}).Error; err != nil {
+			return err
+		}
+
+		// Архивная команда не может сменить владельца
+		return tx.Model(&models.OwnershipTransfer{}).
+			Where("team_id = ? AND status = ?", team.ID, "pending").
+			Update("status", "cancelled").Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при архивации команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда перенесена в архив"})
+}
+
+// RestoreTeamHandler возвращает команду из архива
+// @Summary Восстановление команды из архива
+// @Description Снимает с команды режим только для просмотра. Ссылки-приглашения, срок которых не истёк, снова начинают действовать. Доступно только владельцу команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.SuccessResponse "Команда восстановлена"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только владелец может удалить команду Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при восстановлении команды"
+// @Router /team/restore [post]
+func RestoreTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	team, ok := findArchivedTeam(c, user)
+	if !ok {
+		return
+	}
+
+	if err := storage.DB.Model(team).Updates(map[string]interface{}{"archived_at": nil, "archived_by": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при восстановлении команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Команда восстановлена"})
+}
+
+// findArchivedTeam возвращает архивную команду, с которой владелец может работать. При ошибке пишет ответ.
+func findArchivedTeam(c *gin.Context, user *models.User) (*models.Team, bool) {
+	membership, ok := access.RequireTeam(c, user, access.DeleteTeam)
+	if !ok {
+		return nil, false
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, membership.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Команда не найдена", "code": "TEAM_NOT_FOUND"})
+		return nil, false
+	}
+	if team.ArchivedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Команда не находится в архиве", "code": "TEAM_NOT_ARCHIVED"})
+		return nil, false
+	}
+	return &team, true
+}
+
+// teamPurgeScope — таблицы, записи которых удаляются вместе с командой.
+func teamPurgeScope(tx *gorm.DB, team *models.Team) (response.TeamPurgeResponse, error) {
+	resp := response.TeamPurgeResponse{TeamID: team.ID, TeamName: team.Name}
+	counts := []struct {
+		model interface{}
+		dest  *int64
+	}{
+		{&models.Task{}, &resp.Tasks},
+		{&models.Meeting{}, &resp.Meetings},
+		{&models.InviteLink{}, &resp.InviteLinks},
+		{&models.InviteUse{}, &resp.InviteUses},
+		{&models.TeamMembership{}, &resp.Members},
+		{&models.JoinRequest{}, &resp.JoinRequests},
+		{&models.OwnershipTransfer{}, &resp.OwnershipTransfers},
+	}
+	for _, item := range counts {
+		if err := tx.Unscoped().Model(item.model).Where("team_id = ?", team.ID).Count(item.dest).Error; err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// GetTeamPurgePreviewHandler показывает, что будет удалено вместе с командой
+// @Summary Предпросмотр окончательного удаления команды
+// @Description Возвращает количество задач, встреч, приглашений, участников и других записей, которые будут безвозвратно удалены вместе с архивной командой. Доступно только владельцу команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.TeamPurgeResponse "Что будет удалено"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только владелец может удалить команду Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при подсчёте данных команды"
+// @Router /team/purge [get]
+func GetTeamPurgePreviewHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	team, ok := findArchivedTeam(c, user)
+	if !ok {
+		return
+	}
+
+	resp, err := teamPurgeScope(storage.DB, team)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при подсчёте данных команды"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PurgeTeamHandler безвозвратно удаляет архивную команду
+// @Summary Окончательное удаление команды
+// @Description В одной транзакции безвозвратно удаляет архивную команду вместе с задачами, встречами, приглашениями, участиями, заявками и предложениями о передаче. У участников, для которых команда была активной, активной становится другая их команда. Возвращает количество удалённых записей. Доступно только владельцу команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.TeamPurgeResponse "Удалённые данные"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только владелец может удалить команду Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда не находится в архиве Code: TEAM_NOT_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при удалении команды"
+// @Router /team/purge [delete]
+func PurgeTeamHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	team, ok := findArchivedTeam(c, user)
+	if !ok {
+		return
+	}
+
+	var resp response.TeamPurgeResponse
+	var fileKeys []string
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if resp, err = teamPurgeScope(tx, team); err != nil {
+			return err
+		}
+
+		// Переключаем участников, у которых команда была активной, на другие их команды
+		var memberships []models.TeamMembership
+		if err := tx.Where("team_id = ?", team.ID).Find(&memberships).Error; err != nil {
+			return err
+		}
+		for _, m := range memberships {
+			if err := access.ResetActiveTeam(tx, m.UserID, team.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := workflow.Reset(tx, team.ID); err != nil {
+			return err
+		}
+
+		taskIDs := tx.Unscoped().Model(&models.Task{}).Select("id").Where("team_id = ?", team.ID)
+		if err := tx.Model(&models.Attachment{}).Where("task_id IN (?)", taskIDs).Pluck("storage_key", &fileKeys).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.Attachment{}, &models.TaskAssignment{}, &models.TaskReview{}, &models.TaskEvent{}, &models.TaskComment{}, &models.ChecklistItem{}, &models.TaskLabel{}} {
+			if err := tx.Unscoped().Where("task_id IN (?)", taskIDs).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("task_id IN (?) OR blocker_id IN (?)", taskIDs, taskIDs).Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		seriesIDs := tx.Model(&models.TaskSeries{}).Select("id").Where("team_id = ?", team.ID)
+		if err := tx.Where("series_id IN (?)", seriesIDs).Delete(&models.TaskSeriesLabel{}).Error; err != nil {
+			return err
+		}
+		templateIDs := tx.Model(&models.TaskTemplate{}).Select("id").Where("team_id = ?", team.ID)
+		if err := tx.Where("template_id IN (?)", templateIDs).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id IN (?)", templateIDs).Delete(&models.TaskTemplateLabel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id IN (?)", templateIDs).Delete(&models.TaskTemplateAssignee{}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&models.Task{},
+			&models.Label{},
+			&models.TaskSeries{},
+			&models.TaskTemplate{},
+			&models.Meeting{},
+			&models.InviteUse{},
+			&models.InviteLink{},
+			&models.JoinRequest{},
+			&models.OwnershipTransfer{},
+			&models.TeamMembership{},
+		} {
+			if err := tx.Unscoped().Where("team_id = ?", team.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Delete(team).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении команды"})
+		return
+	}
+
+	// Файлы задач удаляются из хранилища после удаления команды из базы.
+	for _, key := range fileKeys {
+		if err := blob.Files.Delete(c.Request.Context(), key); err != nil {
+			fmt.Printf("Ошибка удаления файла %s из хранилища: %v\n", key, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// canManageMember проверяет, может ли участник с ролью actor исключить участника с ролью target
+// или изменить его роль: владельца не трогает никто, руководителей — только владелец.
+func canManageMember(actor, target string) bool {
+	switch target {
+	case access.RoleOwner:
+		return false
+	case access.RoleManager:
+		return actor == access.RoleOwner
+	default:
+		return access.Can(actor, access.ManageTeam)
+	}
+}
+
+type ChangeMemberRoleInput struct {
+	TelegramID string `json:"telegram_id" binding:"required"`
+	Role       string `json:"role" binding:"required,oneof=manager member observer"`
+}
+
+// ChangeMemberRoleHandler меняет роль участника в текущей команде
+// @Summary Изменение роли участника
+// @Description Владелец может назначить участнику роль manager, member или observer. Руководитель может переключать между member и observer только обычных участников.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param input body ChangeMemberRoleInput true "Участник и новая роль"
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.MemberResponse "Участник с новой ролью"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Пользователь не находится в вашей команде CODE: NOT_IN_TEAM, Error: Недостаточно прав для изменения роли этого участника CODE: ROLE_TOO_HIGH"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при изменении роли"
+// @Router /team/members/role [put]
+func ChangeMemberRoleHandler(c *gin.Context) {
+	var input ChangeMemberRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	var target models.User
+	if err := storage.DB.Where("telegram_id = ?", input.TelegramID).First(&target).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
+		return
+	}
+
+	targetMembership, err := access.GetMembership(target.ID, membership.TeamID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
+		return
+	}
+
+	// Назначать руководителей может только владелец
+	if !canManageMember(membership.Role, targetMembership.Role) ||
+		(input.Role == access.RoleManager && membership.Role != access.RoleOwner) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав для изменения роли этого участника", "code": "ROLE_TOO_HIGH"})
+		return
+	}
+
+	targetMembership.Role = input.Role
+	if err := storage.DB.Save(targetMembership).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении роли"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.MemberResponse{
+		TelegramID: target.TelegramID,
+		Name:       target.Name,
+		Role:       targetMembership.Role,
+	})
+}
+
+// ownershipTransferTTL — сколько предложение передать команду ждёт ответа нового владельца.
+const ownershipTransferTTL = 48 * time.Hour
+
+type TransferOwnershipInput struct {
+	TelegramID string `json:"telegram_id" binding:"required"` // Участник команды, которому передаются права владельца
+}
+
+func toTransferResponse(transfer models.OwnershipTransfer) response.OwnershipTransferResponse {
+	resp := response.OwnershipTransferResponse{
+		ID:        transfer.ID,
+		TeamID:    transfer.TeamID,
+		Status:    transfer.Status,
+		ExpiresAt: transfer.ExpiresAt,
+		CreatedAt: transfer.CreatedAt,
+	}
+
+	var team models.Team
+	if err := storage.DB.First(&team, transfer.TeamID).Error; err == nil {
+		resp.TeamName = team.Name
+	}
+	var from, to models.User
+	if err := storage.DB.First(&from, transfer.FromUserID).Error; err == nil {
+		resp.FromTelegramID = from.TelegramID
+	}
+	if err := storage.DB.First(&to, transfer.ToUserID).Error; err == nil {
+		resp.ToTelegramID = to.TelegramID
+	}
+	return resp
+}
+
+// findPendingTransfer ищет ожидающее ответа предложение из пути запроса. При ошибке пишет ответ.
+func findPendingTransfer(c *gin.Context) (*models.OwnershipTransfer, bool) {
+	var transfer models.OwnershipTransfer
+	if err := storage.DB.First(&transfer, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Предложение не найдено", "code": "TRANSFER_NOT_FOUND"})
+		return nil, false
+	}
+
+	if transfer.Status != "pending" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Предложение уже рассмотрено", "code": "TRANSFER_CLOSED"})
+		return nil, false
+	}
+	if transfer.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Срок действия предложения истёк", "code": "TRANSFER_EXPIRED"})
+		return nil, false
+	}
+	return &transfer, true
+}
+
+// TransferOwnershipHandler предлагает участнику команды стать её владельцем
+// @Summary Передача прав владельца
+// @Description Создаёт предложение передать команду участнику. Участник получает уведомление в Telegram и должен принять его в течение 48 часов. После принятия прежний владелец становится руководителем. Предыдущее неотвеченное предложение по команде отменяется.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body TransferOwnershipInput true "Новый владелец"
+// @Success 200 {object} response.OwnershipTransferResponse "Предложение создано"
+// @Failure 400 {object} response.ErrorResponse "Ошибка валидации или попытка передать команду самому себе"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только владелец может передать команду Code: FORBIDDEN, Error: Пользователь не находится в вашей команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при создании предложения"
+// @Router /team/transfer [post]
+func TransferOwnershipHandler(c *gin.Context) {
+	var input TransferOwnershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.TransferTeam)
+	if !ok {
+		return
+	}
+
+	if input.TelegramID == user.TelegramID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Вы уже владелец команды"})
+		return
+	}
+
+	var target models.User
+	if err := storage.DB.Where("telegram_id = ?", input.TelegramID).First(&target).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
+		return
+	}
+	if _, err := access.GetMembership(target.ID, membership.TeamID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Пользователь не находится в вашей команде", "code": "NOT_IN_TEAM"})
+		return
+	}
+
+	transfer := models.OwnershipTransfer{
+		TeamID:     membership.TeamID,
+		FromUserID: user.ID,
+		ToUserID:   target.ID,
+		Status:     "pending",
+		ExpiresAt:  time.Now().Add(ownershipTransferTTL),
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		// У команды может быть только одно активное предложение
+		if err := tx.Model(&models.OwnershipTransfer{}).
+			Where("team_id = ? AND status = ?", membership.TeamID, "pending").
+			Update("status", "cancelled").Error; err != nil {
+			return err
+		}
+		return tx.Create(&transfer).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании предложения"})
+		return
+	}
+
+	resp := toTransferResponse(transfer)
+
+	notificationText := fmt.Sprintf(
+		"👑 *Передача команды*\n\n"+
+			"%s предлагает вам стать владельцем команды *%s*.\n"+
+			"Предложение действует до %s.",
+		user.Name,
+		resp.TeamName,
+		transfer.ExpiresAt.Format("02.01.2006 15:04"),
+	)
+	buttons := []notification.InlineButton{
+		{Text: "✅ Принять", CallbackData: fmt.Sprintf("accept_transfer_%d", transfer.ID)},
+		{Text: "❌ Отклонить", CallbackData: fmt.Sprintf("decline_transfer_%d", transfer.ID)},
+	}
+	go func(chatID string) {
+		if err := notification.SendTelegramNotificationWithButtons(chatID, notificationText, buttons); err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+		}
+	}(target.TelegramID)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetOwnershipTransfersHandler возвращает предложения стать владельцем, ожидающие ответа пользователя
+// @Summary Входящие предложения о передаче команды
+// @Description Возвращает неотвеченные и не истёкшие предложения стать владельцем команды, адресованные текущему пользователю.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} response.OwnershipTransferResponse "Список предложений"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении предложений"
+// @Router /team/transfer [get]
+func GetOwnershipTransfersHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	var transfers []models.OwnershipTransfer
+	if err := storage.DB.Where("to_user_id = ? AND status = ? AND expires_at > ?", user.ID, "pending", time.Now()).
+		Order("created_at DESC").Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении предложений"})
+		return
+	}
+
+	resp := make([]response.OwnershipTransferResponse, 0, len(transfers))
+	for _, transfer := range transfers {
+		resp = append(resp, toTransferResponse(transfer))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptOwnershipTransferHandler принимает предложение стать владельцем команды
+// @Summary Принятие прав владельца
+// @Description Новый владелец принимает предложение. Прежний владелец становится руководителем команды и получает уведомление.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID предложения"
+// @Success 200 {object} response.OwnershipTransferResponse "Права владельца переданы"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Предложение адресовано другому пользователю Code: TRANSFER_FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED, Error: Предложение больше не действительно Code: TRANSFER_STALE"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при передаче прав"
+// @Router /team/transfer/{id}/accept [post]
+func AcceptOwnershipTransferHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	transfer, ok := findPendingTransfer(c)
+	if !ok {
+		return
+	}
+	if transfer.ToUserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Предложение адресовано другому пользователю", "code": "TRANSFER_FORBIDDEN"})
+		return
+	}
+
+	errStale := errors.New("stale transfer")
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		// Условное обновление защищает от повторного принятия параллельными запросами
+		result := tx.Model(&models.OwnershipTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, "pending").
+			Update("status", "accepted")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStale
+		}
+
+		// Пока предложение ждало ответа, владелец мог смениться, а получатель — покинуть команду
+		var from, to models.TeamMembership
+		if err := tx.Where("user_id = ? AND team_id = ? AND role = ?", transfer.FromUserID, transfer.TeamID, access.RoleOwner).First(&from).Error; err != nil {
+			return errStale
+		}
+		if err := tx.Where("user_id = ? AND team_id = ?", transfer.ToUserID, transfer.TeamID).First(&to).Error; err != nil {
+			return errStale
+		}
+
+		if err := tx.Model(&from).Update("role", access.RoleManager).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&to).Update("role", access.RoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Team{}).Where("id = ?", transfer.TeamID).Update("manager_id", transfer.ToUserID).Error
+	})
+	if errors.Is(err, errStale) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Предложение больше не действительно", "code": "TRANSFER_STALE"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при передаче прав"})
+		return
+	}
+
+	transfer.Status = "accepted"
+	resp := toTransferResponse(*transfer)
+
+	notificationText := fmt.Sprintf(
+		"👑 *Команда передана*\n\n"+
+			"%s принял(а) права владельца команды *%s*. Вы остаётесь в команде руководителем.",
+		user.Name,
+		resp.TeamName,
+	)
+	go func(chatID string) {
+		if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+		}
+	}(resp.FromTelegramID)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeclineOwnershipTransferHandler отклоняет предложение стать владельцем команды
+// @Summary Отклонение прав владельца
+// @Description Получатель отклоняет предложение, владелец получает уведомление.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID предложения"
+// @Success 200 {object} response.OwnershipTransferResponse "Предложение отклонено"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Предложение адресовано другому пользователю Code: TRANSFER_FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при отклонении предложения"
+// @Router /team/transfer/{id}/decline [post]
+func DeclineOwnershipTransferHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	transfer, ok := findPendingTransfer(c)
+	if !ok {
+		return
+	}
+	if transfer.ToUserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Предложение адресовано другому пользователю", "code": "TRANSFER_FORBIDDEN"})
+		return
+	}
+
+	transfer.Status = "declined"
+	if err := storage.DB.Save(transfer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отклонении предложения"})
+		return
+	}
+
+	resp := toTransferResponse(*transfer)
+
+	notificationText := fmt.Sprintf(
+		"👑 *Передача команды отклонена*\n\n"+
+			"%s отказался(ась) становиться владельцем команды *%s*.",
+		user.Name,
+		resp.TeamName,
+	)
+	go func(chatID string) {
+		if err := notification.SendTelegramNotification(chatID, notificationText); err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %s: %v\n", chatID, err)
+		}
+	}(resp.FromTelegramID)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CancelOwnershipTransferHandler отзывает предложение о передаче команды
+// @Summary Отзыв предложения о передаче команды
+// @Description Владелец отзывает своё предложение, пока получатель на него не ответил.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path string true "ID предложения"
+// @Success 200 {object} response.OwnershipTransferResponse "Предложение отозвано"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Отозвать предложение может только его автор Code: TRANSFER_FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Предложение не найдено Code: TRANSFER_NOT_FOUND"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Предложение уже рассмотрено Code: TRANSFER_CLOSED, Error: Срок действия предложения истёк Code: TRANSFER_EXPIRED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при отзыве предложения"
+// @Router /team/transfer/{id} [delete]
+func CancelOwnershipTransferHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+
+	transfer, ok := findPendingTransfer(c)
+	if !ok {
+		return
+	}
+	if transfer.FromUserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Отозвать предложение может только его автор", "code": "TRANSFER_FORBIDDEN"})
+		return
+	}
+
+	transfer.Status = "cancelled"
+	if err := storage.DB.Save(transfer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отзыве предложения"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toTransferResponse(*transfer))
+}
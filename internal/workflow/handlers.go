@@ -0,0 +1,233 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/response"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type WorkflowInput struct {
+	Statuses    []WorkflowStatusInput     `json:"statuses" binding:"required,min=2,dive"` // Первый статус назначается новым задачам
+	Transitions []WorkflowTransitionInput `json:"transitions" binding:"required,min=1,dive"`
+}
+
+type WorkflowStatusInput struct {
+	Key      string `json:"key" binding:"required"` // Латинские буквы в нижнем регистре, цифры и _
+	Title    string `json:"title" binding:"required"`
+	Terminal bool   `json:"terminal"` // Работа по задаче завершена
+	Review   bool   `json:"review"`   // Работа ждёт проверки автором задачи
+}
+
+type WorkflowTransitionInput struct {
+	From  string   `json:"from" binding:"required"`
+	To    string   `json:"to" binding:"required"`
+	Roles []string `json:"roles"` // owner, manager, member; пусто — все, кто может работать с задачами
+}
+
+// errStatusInUse возвращается, если в новом процессе нет статуса, в котором находятся задачи команды.
+type errStatusInUse struct {
+	status string
+}
+
+func (e errStatusInUse) Error() string {
+	return fmt.Sprintf("Статус %s используется в задачах команды, его нельзя убрать из процесса", e.status)
+}
+
+func toWorkflowResponse(teamID uint, m *Machine) response.WorkflowResponse {
+	resp := response.WorkflowResponse{
+		TeamID:      teamID,
+		Custom:      m.Custom,
+		Statuses:    make([]response.WorkflowStatusResponse, 0, len(m.Statuses)),
+		Transitions: make([]response.WorkflowTransitionResponse, 0, len(m.Transitions)),
+	}
+	for _, s := range m.Statuses {
+		resp.Statuses = append(resp.Statuses, response.WorkflowStatusResponse{
+			Key: s.Key, Title: s.Title, Terminal: s.Terminal, Review: s.Review,
+		})
+	}
+	for _, t := range m.Transitions {
+		roles := t.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		resp.Transitions = append(resp.Transitions, response.WorkflowTransitionResponse{From: t.From, To: t.To, Roles: roles})
+	}
+	return resp
+}
+
+// checkStatusesInUse проверяет, что все статусы задач команды и их участников есть в процессе.
+func checkStatusesInUse(tx *gorm.DB, teamID uint, m *Machine) error {
+	var used []string
+	if err := tx.Model(&models.Task{}).Where("team_id = ?", teamID).Distinct().Pluck("status", &used).Error; err != nil {
+		return err
+	}
+
+	var assigned []string
+	if err := tx.Model(&models.TaskAssignment{}).
+		Joins("JOIN tasks ON tasks.id = task_assignments.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.team_id = ?", teamID).
+		Distinct().
+		Pluck("task_assignments.status", &assigned).Error; err != nil {
+		return err
+	}
+
+	keys := m.Keys()
+	for _, status := range append(used, assigned...) {
+		if !slices.Contains(keys, status) {
+			return errStatusInUse{status: status}
+		}
+	}
+	return nil
+}
+
+// GetWorkflowHandler возвращает процесс работы над задачами команды
+// @Summary Процесс работы над задачами команды
+// @Description Возвращает статусы задач команды по порядку и разрешённые переходы с ролями, которые могут их выполнять. Если команда не настраивала процесс, возвращается стандартный: assigned → in_progress → in_review → completed.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.WorkflowResponse "Процесс команды"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Вы не состоите в этой команде Code: NOT_IN_TEAM"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при получении процесса команды"
+// @Router /team/workflow [get]
+func GetWorkflowHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeamRead(c, user, access.ViewTeam)
+	if !ok {
+		return
+	}
+
+	m, err := ForTeam(storage.DB, membership.TeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении процесса команды"})
+		return
+	}
+	c.JSON(http.StatusOK, toWorkflowResponse(membership.TeamID, m))
+}
+
+// UpdateWorkflowHandler заменяет процесс работы над задачами команды
+// @Summary Настройка процесса работы над задачами
+// @Description Заменяет статусы и переходы команды. Первый статус назначается новым задачам, завершающие статусы (terminal) означают, что работа выполнена, а в статусе проверки (review) работа ждёт решения автора задачи — из него нужны переходы в завершающий статус и обратно в работу. Для каждого перехода можно ограничить роли. Статусы, в которых сейчас находятся задачи команды, убрать нельзя. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Param input body WorkflowInput true "Статусы и переходы"
+// @Success 200 {object} response.WorkflowResponse "Процесс команды сохранён"
+// @Failure 400 {object} response.ErrorCodeResponse "Error: в процессе нужен хотя бы один завершающий статус Code: INVALID_WORKFLOW"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Статус in_review используется в задачах команды, его нельзя убрать из процесса Code: WORKFLOW_STATUS_IN_USE"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сохранении процесса команды"
+// @Router /team/workflow [put]
+func UpdateWorkflowHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	var input WorkflowInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m := &Machine{Custom: true}
+	for _, s := range input.Statuses {
+		m.Statuses = append(m.Statuses, Status{Key: s.Key, Title: s.Title, Terminal: s.Terminal, Review: s.Review})
+	}
+	for _, t := range input.Transitions {
+		m.Transitions = append(m.Transitions, Transition{From: t.From, To: t.To, Roles: t.Roles})
+	}
+	if err := m.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_WORKFLOW"})
+		return
+	}
+
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkStatusesInUse(tx, membership.TeamID, m); err != nil {
+			return err
+		}
+		return Save(tx, membership.TeamID, user.ID, m)
+	})
+	var inUse errStatusInUse
+	if errors.As(err, &inUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": inUse.Error(), "code": "WORKFLOW_STATUS_IN_USE"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении процесса команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkflowResponse(membership.TeamID, m))
+}
+
+// ResetWorkflowHandler возвращает команде стандартный процесс
+// @Summary Сброс процесса к стандартному
+// @Description Удаляет настроенный процесс команды, после чего задачи работают по стандартному процессу. Если задачи команды находятся в статусах, которых нет в стандартном процессе, сброс невозможен. Доступно владельцу и руководителям команды.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security TelegramInitData
+// @Security TelegramLogin
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param team_id query int false "ID команды (по умолчанию — активная команда пользователя)"
+// @Success 200 {object} response.WorkflowResponse "Стандартный процесс"
+// @Failure 401 {object} response.ErrorResponse "Пользователь не найден"
+// @Failure 403 {object} response.ErrorCodeResponse "Error: Только руководитель может управлять командой Code: FORBIDDEN"
+// @Failure 404 {object} response.ErrorCodeResponse "Error: Отсутствует команда у пользователя Code: USER_HAS_NO_TEAM"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Статус blocked используется в задачах команды, его нельзя убрать из процесса Code: WORKFLOW_STATUS_IN_USE"
+// @Failure 409 {object} response.ErrorCodeResponse "Error: Команда в архиве, доступен только просмотр Code: TEAM_ARCHIVED"
+// @Failure 500 {object} response.ErrorResponse "Ошибка при сбросе процесса команды"
+// @Router /team/workflow [delete]
+func ResetWorkflowHandler(c *gin.Context) {
+	user := auth.CurrentUser(c)
+	membership, ok := access.RequireTeam(c, user, access.ManageTeam)
+	if !ok {
+		return
+	}
+
+	m := Default()
+	err := storage.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkStatusesInUse(tx, membership.TeamID, m); err != nil {
+			return err
+		}
+		return Reset(tx, membership.TeamID)
+	})
+	var inUse errStatusInUse
+	if errors.As(err, &inUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": inUse.Error(), "code": "WORKFLOW_STATUS_IN_USE"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сбросе процесса команды"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkflowResponse(membership.TeamID, m))
+}
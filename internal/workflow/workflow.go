@@ -0,0 +1,345 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ошибки проверки перехода. Обработчики переводят их в ответы API.
+var (
+	ErrUnknownStatus        = errors.New("статус не входит в процесс команды")
+	ErrTransitionNotAllowed = errors.New("переход между статусами не предусмотрен процессом команды")
+	ErrRoleNotAllowed       = errors.New("роль не может выполнить этот переход")
+)
+
+// Status — статус задачи в процессе команды.
+type Status struct {
+	Key      string
+	Title    string
+	Terminal bool // Работа по задаче завершена
+	Review   bool // Работа ждёт проверки автором задачи
+}
+
+// Transition — разрешённый переход. Пустой Roles разрешает переход всем, кто может работать с задачами.
+type Transition struct {
+	From  string
+	To    string
+	Roles []string
+}
+
+// Machine — процесс работы над задачами: упорядоченные статусы и переходы между ними.
+// Первый статус назначается новым задачам.
+type Machine struct {
+	Statuses    []Status
+	Transitions []Transition
+	Custom      bool // Процесс настроен командой, а не стандартный
+}
+
+// Default возвращает стандартный процесс: исполнитель берёт задачу в работу и отправляет на проверку,
+// автор задачи принимает работу или возвращает на доработку. Руководитель может завершить задачу без проверки.
+func Default() *Machine {
+	workers := []string{access.RoleOwner, access.RoleManager, access.RoleMember}
+	managers := []string{access.RoleOwner, access.RoleManager}
+	return &Machine{
+		Statuses: []Status{
+			{Key: "assigned", Title: "Назначена"},
+			{Key: "in_progress", Title: "В работе"},
+			{Key: "in_review", Title: "На проверке", Review: true},
+			{Key: "completed", Title: "Завершена", Terminal: true},
+		},
+		Transitions: []Transition{
+			{From: "assigned", To: "in_progress", Roles: workers},
+			{From: "assigned", To: "in_review", Roles: workers},
+			{From: "in_progress", To: "in_review", Roles: workers},
+			{From: "assigned", To: "completed", Roles: managers},
+			{From: "in_progress", To: "completed", Roles: managers},
+			{From: "in_review", To: "completed", Roles: managers},
+			{From: "in_review", To: "in_progress", Roles: managers},
+		},
+	}
+}
+
+// ForTeam загружает процесс команды или возвращает стандартный, если команда его не настраивала.
+func ForTeam(db *gorm.DB, teamID uint) (*Machine, error) {
+	var flow models.Workflow
+	err := db.Preload("Statuses", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("team_id = ?", teamID).
+		First(&flow).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Machine{Custom: true}
+	for _, s := range flow.Statuses {
+		m.Statuses = append(m.Statuses, Status{Key: s.Key, Title: s.Title, Terminal: s.Terminal, Review: s.Review})
+	}
+	for _, t := range flow.Transitions {
+		m.Transitions = append(m.Transitions, Transition{From: t.FromStatus, To: t.ToStatus, Roles: strings.Fields(t.Roles)})
+	}
+	return m, nil
+}
+
+// Save заменяет процесс команды.
+func Save(tx *gorm.DB, teamID, userID uint, m *Machine) error {
+	if err := Reset(tx, teamID); err != nil {
+		return err
+	}
+
+	flow := models.Workflow{TeamID: teamID, UpdatedBy: userID}
+	for i, s := range m.Statuses {
+		flow.Statuses = append(flow.Statuses, models.WorkflowStatus{
+			Key: s.Key, Title: s.Title, Position: i, Terminal: s.Terminal, Review: s.Review,
+		})
+	}
+	for _, t := range m.Transitions {
+		flow.Transitions = append(flow.Transitions, models.WorkflowTransition{
+			FromStatus: t.From, ToStatus: t.To, Roles: strings.Join(t.Roles, " "),
+		})
+	}
+	return tx.Create(&flow).Error
+}
+
+// Reset удаляет процесс команды, после чего команда использует стандартный.
+func Reset(tx *gorm.DB, teamID uint) error {
+	sub := tx.Model(&models.Workflow{}).Select("id").Where("team_id = ?", teamID)
+	if err := tx.Where("workflow_id IN (?)", sub).Delete(&models.WorkflowStatus{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("workflow_id IN (?)", sub).Delete(&models.WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("team_id = ?", teamID).Delete(&models.Workflow{}).Error
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Validate проверяет, что процесс можно использовать: ключи уникальны, переходы ссылаются на существующие статусы,
+// из каждого незавершающего статуса есть выход, а из статуса проверки можно и принять, и вернуть работу.
+func (m *Machine) Validate() error {
+	if len(m.Statuses) < 2 {
+		return errors.New("в процессе должно быть не меньше двух статусов")
+	}
+	seen := make(map[string]bool, len(m.Statuses))
+	for _, s := range m.Statuses {
+		if !keyPattern.MatchString(s.Key) {
+			return fmt.Errorf("некорректный ключ статуса %q: латинские буквы в нижнем регистре, цифры и _", s.Key)
+		}
+		if seen[s.Key] {
+			return fmt.Errorf("статус %q указан дважды", s.Key)
+		}
+		if strings.TrimSpace(s.Title) == "" {
+			return fmt.Errorf("у статуса %q нет названия", s.Key)
+		}
+		if s.Terminal && s.Review {
+			return fmt.Errorf("статус %q не может быть одновременно завершающим и статусом проверки", s.Key)
+		}
+		seen[s.Key] = true
+	}
+	if first := m.Statuses[0]; first.Terminal || first.Review {
+		return errors.New("начальный статус не может быть завершающим или статусом проверки")
+	}
+	if !slices.ContainsFunc(m.Statuses, func(s Status) bool { return s.Terminal }) {
+		return errors.New("в процессе нужен хотя бы один завершающий статус")
+	}
+
+	pairs := make(map[[2]string]bool, len(m.Transitions))
+	for _, t := range m.Transitions {
+		if !seen[t.From] || !seen[t.To] {
+			return fmt.Errorf("переход %s → %s ссылается на неизвестный статус", t.From, t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("переход %s → %s никуда не ведёт", t.From, t.To)
+		}
+		if pairs[[2]string{t.From, t.To}] {
+			return fmt.Errorf("переход %s → %s указан дважды", t.From, t.To)
+		}
+		pairs[[2]string{t.From, t.To}] = true
+		for _, role := range t.Roles {
+			if !access.ValidRole(role) {
+				return fmt.Errorf("неизвестная роль %q в переходе %s → %s", role, t.From, t.To)
+			}
+		}
+	}
+
+	for _, s := range m.Statuses {
+		switch {
+		case s.Review:
+			if m.ApproveTarget(s.Key) == "" || m.RejectTarget(s.Key) == "" {
+				return fmt.Errorf("из статуса проверки %q нужны переходы в завершающий статус и обратно в работу", s.Key)
+			}
+		case !s.Terminal:
+			if !slices.ContainsFunc(m.Transitions, func(t Transition) bool { return t.From == s.Key }) {
+				return fmt.Errorf("из статуса %q нет ни одного перехода", s.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// Get возвращает статус по ключу.
+func (m *Machine) Get(key string) (Status, bool) {
+	for _, s := range m.Statuses {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Status{}, false
+}
+
+// Keys возвращает ключи всех статусов по порядку.
+func (m *Machine) Keys() []string {
+	keys := make([]string, 0, len(m.Statuses))
+	for _, s := range m.Statuses {
+		keys = append(keys, s.Key)
+	}
+	return keys
+}
+
+// Title возвращает название статуса или сам ключ, если статуса нет в процессе.
+func (m *Machine) Title(key string) string {
+	if s, ok := m.Get(key); ok {
+		return s.Title
+	}
+	return key
+}
+
+// Initial возвращает статус, с которым создаются задачи.
+func (m *Machine) Initial() string {
+	return m.Statuses[0].Key
+}
+
+// Done возвращает основной завершающий статус — первый по порядку.
+func (m *Machine) Done() string {
+	for _, s := range m.Statuses {
+		if s.Terminal {
+			return s.Key
+		}
+	}
+	return ""
+}
+
+// Working возвращает первый рабочий статус после начального: не завершающий и не статус проверки.
+func (m *Machine) Working() string {
+	for _, s := range m.Statuses[1:] {
+		if !s.Terminal && !s.Review {
+			return s.Key
+		}
+	}
+	return m.Initial()
+}
+
+// IsTerminal сообщает, что работа в этом статусе завершена.
+func (m *Machine) IsTerminal(key string) bool {
+	s, ok := m.Get(key)
+	return ok && s.Terminal
+}
+
+// TerminalCondition возвращает условие SQL для выборки из tasks: статус задачи завершающий в процессе её команды.
+// Для команд без своего процесса используются завершающие статусы стандартного.
+func TerminalCondition() clause.Expr {
+	var keys []string
+	for _, s := range Default().Statuses {
+		if s.Terminal {
+			keys = append(keys, s.Key)
+		}
+	}
+	return gorm.Expr(`CASE WHEN EXISTS (SELECT 1 FROM workflows WHERE workflows.team_id = tasks.team_id)
+		THEN tasks.status IN (SELECT workflow_statuses.key FROM workflow_statuses
+			JOIN workflows ON workflows.id = workflow_statuses.workflow_id
+			WHERE workflows.team_id = tasks.team_id AND workflow_statuses.terminal)
+		ELSE tasks.status IN ? END`, keys)
+}
+
+// IsReview сообщает, что работа в этом статусе ждёт проверки.
+func (m *Machine) IsReview(key string) bool {
+	s, ok := m.Get(key)
+	return ok && s.Review
+}
+
+// Position возвращает порядковый номер статуса или -1.
+func (m *Machine) Position(key string) int {
+	return slices.IndexFunc(m.Statuses, func(s Status) bool { return s.Key == key })
+}
+
+func (m *Machine) transition(from, to string) (Transition, bool) {
+	for _, t := range m.Transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// Check проверяет, что роль может перевести задачу из статуса from в статус to.
+func (m *Machine) Check(from, to, role string) error {
+	if _, ok := m.Get(to); !ok {
+		return ErrUnknownStatus
+	}
+	t, ok := m.transition(from, to)
+	if !ok {
+		return ErrTransitionNotAllowed
+	}
+	if len(t.Roles) == 0 {
+		if !access.Can(role, access.WorkOnTasks) {
+			return ErrRoleNotAllowed
+		}
+		return nil
+	}
+	if !slices.Contains(t.Roles, role) {
+		return ErrRoleNotAllowed
+	}
+	return nil
+}
+
+// Allowed возвращает статусы, в которые роль может перевести задачу из статуса from.
+func (m *Machine) Allowed(from, role string) []string {
+	var keys []string
+	for _, t := range m.Transitions {
+		if t.From == from && m.Check(from, t.To, role) == nil {
+			keys = append(keys, t.To)
+		}
+	}
+	return keys
+}
+
+// ReviewTarget возвращает статус проверки, в который роль может отправить работу из статуса from.
+func (m *Machine) ReviewTarget(from, role string) string {
+	for _, key := range m.Allowed(from, role) {
+		if m.IsReview(key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// ApproveTarget возвращает статус, в который переходит принятая работа.
+func (m *Machine) ApproveTarget(review string) string {
+	for _, t := range m.Transitions {
+		if t.From == review && m.IsTerminal(t.To) {
+			return t.To
+		}
+	}
+	return ""
+}
+
+// RejectTarget возвращает статус, в который возвращается отклонённая работа.
+func (m *Machine) RejectTarget(review string) string {
+	for _, t := range m.Transitions {
+		if t.From == review && !m.IsTerminal(t.To) && !m.IsReview(t.To) {
+			return t.To
+		}
+	}
+	return ""
+}
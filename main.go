@@ -1,99 +1,233 @@
-package main
-
-import (
-	"fmt"
-	"log"
-	"os"
-
-	_ "github.com/Anabol1ks/Lamadjo-Task-Board/docs"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/meetings"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/tasks"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/team"
-	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/users"
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-)
-
-// @Title Сервис для контроля задачами и встречами команды
-func main() {
-	key := os.Getenv("DB_HOST")
-	if key == "" {
-		fmt.Println("Используется данные из .env")
-		if err := godotenv.Load(); err != nil {
-			log.Fatal("Ошибка получения .env")
-		}
-	}
-
-	storage.ConnectDatabase()
-
-	if err := storage.DB.AutoMigrate(&models.User{}); err != nil {
-		log.Fatal("Ошибка миграции пользователей: ", err.Error())
-	}
-	if err := storage.DB.AutoMigrate(&models.Team{}, &models.Task{}, &models.Meeting{}, &models.Room{}, &models.InviteLink{}); err != nil {
-		log.Fatal("Ошибка миграции остальных моделей: ", err.Error())
-	}
-
-	// Инициализация бота
-	//
-
-	r := gin.Default()
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Эндпоинты для авторизации
-	r.POST("/auth", auth.RegisterHandler)
-	r.GET("/auth", auth.CheckAuthHandler)
-	//
-
-	r.GET("/user", users.GetMyUser)
-
-	teamGroup := r.Group("/team")
-	// Эндпоинты для управления командами
-	{
-		teamGroup.POST("", team.CreateTeamHandler)
-		teamGroup.POST("/join", team.JoinTeamHandler)
-		teamGroup.GET("/my", team.GetMyTeamHandler)
-		teamGroup.GET("/invite", team.GetLinkTeamHandler)
-		teamGroup.GET("/leave", team.LeaveMemberTeamHandler)
-		teamGroup.PUT("", team.ChangeTeamHandler)
-		teamGroup.DELETE("", team.DeleteTeamHandler)
-		//
-
-		// Эндпоинты для управления участниками команды
-		teamGroup.GET("/members", team.GetMembersTeam)
-		teamGroup.GET("/kick", team.KickMemberTeamHandler)
-		//
-	}
-
-	// Эндпоинты задач
-	tasksGroup := r.Group("/tasks")
-	{
-		tasksGroup.POST("", tasks.CreateTaskHandlres)
-		tasksGroup.GET("", tasks.GetTasksHandlres)
-		tasksGroup.DELETE("/:id", tasks.DeleteTaskHandler)
-		tasksGroup.PUT("/:id/status", tasks.UpdateTaskStatusHandler)
-		tasksGroup.GET("/issued", tasks.IssuedTaskHandler)
-	}
-	//
-
-	meetingsGroup := r.Group("/meetings")
-	{
-		meetingsGroup.POST("/", meetings.CreateMeetingHandler)
-		meetingsGroup.GET("/available-slots", meetings.GetAvailableTimeSlotsHandler)
-		meetingsGroup.DELETE("/:id", meetings.DeleteMeetingHandler)
-		meetingsGroup.GET("/my", meetings.GetMyMeeting)
-	}
-	// Эндпоинты встреч
-	//
-
-	// Модуль уведомлений
-	//
-
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("Ошибка запуска сервера: ", err)
-	}
-}
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/Anabol1ks/Lamadjo-Task-Board/docs"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/access"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/apikeys"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/auth"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/blob"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/meetings"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/models"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/org"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/search"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/storage"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/tasks"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/team"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/users"
+	"github.com/Anabol1ks/Lamadjo-Task-Board/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// @Title Сервис для контроля задачами и встречами команды
+// @securityDefinitions.apikey TelegramInitData
+// @in header
+// @name X-Telegram-Init-Data
+// @description initData из Telegram WebApp
+// @securityDefinitions.apikey TelegramLogin
+// @in header
+// @name X-Telegram-Login
+// @description Данные Telegram Login Widget в виде query-строки (id, auth_date, hash, ...)
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access-токен в формате "Bearer <token>", выданный /auth
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description Сервисный API-ключ. Ключ с правом действовать от имени пользователя принимает его telegram_id в заголовке X-On-Behalf-Of
+func main() {
+	key := os.Getenv("DB_HOST")
+	if key == "" {
+		fmt.Println("Используется данные из .env")
+		if err := godotenv.Load(); err != nil {
+			log.Fatal("Ошибка получения .env")
+		}
+	}
+
+	storage.ConnectDatabase()
+	blob.Connect()
+
+	if err := storage.DB.AutoMigrate(&models.User{}); err != nil {
+		log.Fatal("Ошибка миграции пользователей: ", err.Error())
+	}
+	if err := storage.DB.AutoMigrate(&models.Team{}, &models.Task{}, &models.TaskAssignment{}, &models.TaskReview{}, &models.TaskEvent{}, &models.TaskComment{}, &models.Attachment{}, &models.ChecklistItem{}, &models.TaskDependency{}, &models.TaskSeries{}, &models.Label{}, &models.TaskLabel{}, &models.TaskSeriesLabel{}, &models.TaskTemplate{}, &models.TaskTemplateItem{}, &models.TaskTemplateLabel{}, &models.TaskTemplateAssignee{}, &models.Meeting{}, &models.Room{}, &models.InviteLink{}, &models.InviteUse{}, &models.TeamMembership{}, &models.OwnershipTransfer{}, &models.JoinRequest{}, &models.Organization{}, &models.Department{}, &models.Workflow{}, &models.WorkflowStatus{}, &models.WorkflowTransition{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.APIKey{}); err != nil {
+		log.Fatal("Ошибка миграции остальных моделей: ", err.Error())
+	}
+	if err := access.MigrateLegacyRoles(storage.DB); err != nil {
+		log.Fatal("Ошибка переноса ролей пользователей в команды: ", err.Error())
+	}
+	if err := search.Migrate(storage.DB); err != nil {
+		log.Fatal("Ошибка создания поисковых индексов: ", err.Error())
+	}
+
+	// Создание очередных задач повторяющихся серий
+	go tasks.RunSeriesGenerator()
+
+	// Инициализация бота
+	//
+
+	r := gin.Default()
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Эндпоинты для авторизации
+	r.POST("/auth", auth.Middleware(), auth.RequireScope("users"), auth.RegisterHandler)
+	r.GET("/auth", auth.Middleware(), auth.RequireScope("users"), auth.CheckAuthHandler)
+	r.POST("/auth/refresh", auth.RefreshHandler)
+	r.POST("/auth/logout", auth.Middleware(), auth.RequireScope("users"), auth.RequireUser(), auth.LogoutHandler)
+	//
+
+	// Эндпоинты для управления API-ключами сервисов
+	apiKeysGroup := r.Group("/apikeys", auth.Middleware(), auth.RequireScope("apikeys"), auth.RequireAdminOrServiceKey())
+	{
+		apiKeysGroup.POST("", apikeys.CreateAPIKeyHandler)
+		apiKeysGroup.GET("", apikeys.ListAPIKeysHandler)
+		apiKeysGroup.POST("/:id/rotate", apikeys.RotateAPIKeyHandler)
+		apiKeysGroup.DELETE("/:id", apikeys.RevokeAPIKeyHandler)
+	}
+	//
+
+	r.GET("/user", auth.Middleware(), auth.RequireScope("users"), auth.RequireUser(), users.GetMyUser)
+	r.GET("/search", auth.Middleware(), auth.RequireScope("search"), auth.RequireUser(), search.SearchHandler)
+
+	teamGroup := r.Group("/team", auth.Middleware(), auth.RequireScope("team"), auth.RequireUser())
+	// Эндпоинты для управления командами
+	{
+		teamGroup.POST("", team.CreateTeamHandler)
+		teamGroup.POST("/join", team.JoinTeamHandler)
+		teamGroup.GET("/my", team.GetMyTeamHandler)
+		teamGroup.PUT("/active", team.SetActiveTeamHandler)
+		teamGroup.GET("/invite", team.GetLinkTeamHandler)
+		teamGroup.POST("/invites", team.CreateInviteHandler)
+		teamGroup.GET("/invites", team.GetInvitesHandler)
+		teamGroup.GET("/invites/:code/uses", team.GetInviteUsesHandler)
+		teamGroup.DELETE("/invites/:code", team.RevokeInviteHandler)
+		teamGroup.GET("/leave", team.LeaveMemberTeamHandler)
+		teamGroup.PUT("", team.ChangeTeamHandler)
+		teamGroup.DELETE("", team.ArchiveTeamHandler)
+		teamGroup.POST("/archive", team.ArchiveTeamHandler)
+		teamGroup.POST("/restore", team.RestoreTeamHandler)
+		teamGroup.GET("/purge", team.GetTeamPurgePreviewHandler)
+		teamGroup.DELETE("/purge", team.PurgeTeamHandler)
+		//
+
+		// Эндпоинты для управления участниками команды
+		teamGroup.GET("/members", team.GetMembersTeam)
+		teamGroup.GET("/kick", team.KickMemberTeamHandler)
+		teamGroup.PUT("/members/role", team.ChangeMemberRoleHandler)
+		//
+
+		// Эндпоинты для заявок на вступление
+		teamGroup.GET("/requests", team.GetJoinRequestsHandler)
+		teamGroup.POST("/requests/:id/approve", team.ApproveJoinRequestHandler)
+		teamGroup.POST("/requests/:id/reject", team.RejectJoinRequestHandler)
+		//
+
+		// Эндпоинты для передачи прав владельца
+		teamGroup.POST("/transfer", team.TransferOwnershipHandler)
+		teamGroup.GET("/transfer", team.GetOwnershipTransfersHandler)
+		teamGroup.POST("/transfer/:id/accept", team.AcceptOwnershipTransferHandler)
+		teamGroup.POST("/transfer/:id/decline", team.DeclineOwnershipTransferHandler)
+		teamGroup.DELETE("/transfer/:id", team.CancelOwnershipTransferHandler)
+		//
+
+		// Эндпоинты для процесса работы над задачами
+		teamGroup.GET("/workflow", workflow.GetWorkflowHandler)
+		teamGroup.PUT("/workflow", workflow.UpdateWorkflowHandler)
+		teamGroup.DELETE("/workflow", workflow.ResetWorkflowHandler)
+		//
+
+		// Эндпоинты для меток задач
+		teamGroup.GET("/labels", tasks.GetLabelsHandler)
+		teamGroup.POST("/labels", tasks.CreateLabelHandler)
+		teamGroup.PUT("/labels/:id", tasks.UpdateLabelHandler)
+		teamGroup.DELETE("/labels/:id", tasks.DeleteLabelHandler)
+		//
+
+		// Эндпоинты для шаблонов задач
+		teamGroup.GET("/templates", tasks.GetTaskTemplatesHandler)
+		teamGroup.POST("/templates", tasks.CreateTaskTemplateHandler)
+		teamGroup.GET("/templates/:id", tasks.GetTaskTemplateHandler)
+		teamGroup.PUT("/templates/:id", tasks.UpdateTaskTemplateHandler)
+		teamGroup.DELETE("/templates/:id", tasks.DeleteTaskTemplateHandler)
+		teamGroup.POST("/templates/:id/instantiate", tasks.InstantiateTaskTemplateHandler)
+		//
+	}
+
+	// Эндпоинты организаций и отделов
+	orgGroup := r.Group("/org", auth.Middleware(), auth.RequireScope("org"), auth.RequireUser())
+	{
+		orgGroup.POST("", org.CreateOrganizationHandler)
+		orgGroup.GET("/my", org.GetMyOrganizationsHandler)
+		orgGroup.GET("/:id", org.GetOrganizationHandler)
+		orgGroup.POST("/:id/departments", org.CreateDepartmentHandler)
+		orgGroup.PUT("/departments/:id", org.UpdateDepartmentHandler)
+		orgGroup.DELETE("/departments/:id", org.DeleteDepartmentHandler)
+		orgGroup.POST("/departments/:id/teams", org.AttachTeamHandler)
+		orgGroup.DELETE("/departments/:id/teams/:team_id", org.DetachTeamHandler)
+	}
+	//
+
+	// Эндпоинты задач
+	tasksGroup := r.Group("/tasks", auth.Middleware(), auth.RequireScope("tasks"), auth.RequireUser())
+	{
+		tasksGroup.POST("", tasks.CreateTaskHandlres)
+		tasksGroup.POST("/department", tasks.CreateDepartmentTaskHandler)
+		tasksGroup.POST("/bulk", tasks.BulkTaskHandler)
+		tasksGroup.GET("", tasks.GetTasksHandlres)
+		tasksGroup.PUT("/:id", tasks.UpdateTaskHandler)
+		tasksGroup.DELETE("/:id", tasks.DeleteTaskHandler)
+		tasksGroup.PUT("/:id/status", tasks.UpdateTaskStatusHandler)
+		tasksGroup.GET("/:id/assignments", tasks.GetTaskAssignmentsHandler)
+		tasksGroup.GET("/:id/reviews", tasks.GetTaskReviewHistoryHandler)
+		tasksGroup.GET("/:id/history", tasks.GetTaskHistoryHandler)
+		tasksGroup.POST("/:id/comments", tasks.CreateTaskCommentHandler)
+		tasksGroup.GET("/:id/comments", tasks.GetTaskCommentsHandler)
+		tasksGroup.PUT("/:id/comments/:comment_id", tasks.UpdateTaskCommentHandler)
+		tasksGroup.DELETE("/:id/comments/:comment_id", tasks.DeleteTaskCommentHandler)
+		tasksGroup.GET("/:id/subtasks", tasks.GetSubtasksHandler)
+		tasksGroup.GET("/:id/checklist", tasks.GetChecklistHandler)
+		tasksGroup.POST("/:id/checklist", tasks.AddChecklistItemHandler)
+		tasksGroup.PUT("/:id/checklist/:item_id", tasks.UpdateChecklistItemHandler)
+		tasksGroup.DELETE("/:id/checklist/:item_id", tasks.DeleteChecklistItemHandler)
+		tasksGroup.GET("/:id/dependencies", tasks.GetTaskDependenciesHandler)
+		tasksGroup.POST("/:id/dependencies", tasks.AddTaskDependencyHandler)
+		tasksGroup.DELETE("/:id/dependencies/:blocker_id", tasks.RemoveTaskDependencyHandler)
+		tasksGroup.POST("/:id/attachments", tasks.UploadTaskAttachmentHandler)
+		tasksGroup.GET("/:id/attachments", tasks.GetTaskAttachmentsHandler)
+		tasksGroup.GET("/:id/attachments/:attachment_id", tasks.DownloadTaskAttachmentHandler)
+		tasksGroup.DELETE("/:id/attachments/:attachment_id", tasks.DeleteTaskAttachmentHandler)
+		tasksGroup.GET("/reviews", tasks.GetTaskReviewsHandler)
+		tasksGroup.POST("/reviews/:id/approve", tasks.ApproveTaskReviewHandler)
+		tasksGroup.POST("/reviews/:id/reject", tasks.RejectTaskReviewHandler)
+		tasksGroup.GET("/issued", tasks.IssuedTaskHandler)
+		tasksGroup.GET("/series", tasks.GetTaskSeriesListHandler)
+		tasksGroup.GET("/series/:id", tasks.GetTaskSeriesHandler)
+		tasksGroup.PUT("/series/:id", tasks.UpdateTaskSeriesHandler)
+		tasksGroup.DELETE("/series/:id", tasks.StopTaskSeriesHandler)
+	}
+	//
+
+	meetingsGroup := r.Group("/meetings", auth.Middleware(), auth.RequireScope("meetings"), auth.RequireUser())
+	{
+		meetingsGroup.POST("/", meetings.CreateMeetingHandler)
+		meetingsGroup.GET("/available-slots", meetings.GetAvailableTimeSlotsHandler)
+		meetingsGroup.DELETE("/:id", meetings.DeleteMeetingHandler)
+		meetingsGroup.GET("/my", meetings.GetMyMeeting)
+	}
+	// Эндпоинты встреч
+	//
+
+	// Модуль уведомлений
+	//
+
+	if err := r.Run(":8080"); err != nil {
+		log.Fatal("Ошибка запуска сервера: ", err)
+	}
+}
@@ -1,6 +1,9 @@
 import requests
 import time
 import json
+import hashlib
+import hmac
+from urllib.parse import urlencode, unquote
 from dotenv import load_dotenv
 import os
 from typing import Dict, Any
@@ -13,6 +16,7 @@ TOKEN = os.getenv("TELEGRAM_BOT_TOKEN")
 ADMIN_CHAT_ID = os.getenv("ADMIN_CHAT_ID")
 TELEGRAM_URL = f"https://api.telegram.org/bot{TOKEN}/"
 BACKEND_BASE_URL = os.getenv("BACKEND_BASE_URL")
+BACKEND_API_KEY = os.getenv("BACKEND_API_KEY")
 
 # Добавим константы для фиксированных аудиторий и временных слотов
 AVAILABLE_ROOMS = ["A-1", "A-2", "A-3", "A-4", "A-5"]
@@ -62,6 +66,30 @@ def send_message(chat_id, text, reply_markup=None, parse_mode="Markdown"):
     except Exception as e:
         print(f"Исключение при отправке сообщения: {e}")
 
+def send_document(chat_id, filename, content, caption=None):
+    url = TELEGRAM_URL + "sendDocument"
+    data = {"chat_id": chat_id}
+    if caption:
+        data["caption"] = caption
+    try:
+        response = requests.post(url, data=data, files={"document": (filename, content)})
+        if response.status_code != 200:
+            print(f"Ошибка отправки файла: {response.text}")
+    except Exception as e:
+        print(f"Исключение при отправке файла: {e}")
+
+def download_telegram_file(file_id):
+    """Скачивает файл, присланный пользователем боту, и возвращает его содержимое или None."""
+    try:
+        response = requests.get(TELEGRAM_URL + "getFile", params={"file_id": file_id})
+        file_path = response.json()["result"]["file_path"]
+        content = requests.get(f"https://api.telegram.org/file/bot{TOKEN}/{file_path}")
+        if content.status_code == 200:
+            return content.content
+    except Exception as e:
+        print(f"Исключение при скачивании файла из Telegram: {e}")
+    return None
+
 def answer_callback(callback_query_id):
     url = TELEGRAM_URL + "answerCallbackQuery"
     payload = {"callback_query_id": callback_query_id}
@@ -72,15 +100,29 @@ def delete_message(chat_id, message_id):
     payload = {"chat_id": chat_id, "message_id": message_id}
     requests.post(url, json=payload)
 
+def backend_auth_headers(chat_id):
+    """Заголовки авторизации запроса к бэкенду от имени пользователя chat_id.
+
+    Если задан BACKEND_API_KEY, бот действует как сервис от имени пользователя,
+    иначе подписывает запрос по схеме Telegram Login Widget токеном бота.
+    """
+    if BACKEND_API_KEY:
+        return {"X-API-Key": BACKEND_API_KEY, "X-On-Behalf-Of": str(chat_id)}
+    data = {"id": str(chat_id), "auth_date": str(int(time.time()))}
+    data_check_string = "\n".join(f"{key}={data[key]}" for key in sorted(data))
+    secret_key = hashlib.sha256(TOKEN.encode()).digest()
+    data["hash"] = hmac.new(secret_key, data_check_string.encode(), hashlib.sha256).hexdigest()
+    return {"X-Telegram-Login": urlencode(data)}
+
 def auth_get_request(chat_id):
     url = f"{BACKEND_BASE_URL}/auth"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         print(f"Auth response status: {response.status_code}")  # Отладочный вывод
         print(f"Auth response body: {response.text}")  # Отладочный вывод
         
@@ -94,11 +136,12 @@ def auth_get_request(chat_id):
         print(f"Auth request exception: {str(e)}")  # Отладочный вывод
         return {"success": False, "error": str(e)}
 
-def auth_post_request(chat_id, name, role):
+def auth_post_request(chat_id, name):
     url = f"{BACKEND_BASE_URL}/auth"
-    payload = {"telegram_id": str(chat_id), "name": name, "role": role}
+    payload = {"name": name}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
@@ -125,43 +168,66 @@ def send_welcome(chat_id):
     }
     send_message(chat_id, "👋 Добро пожаловать в систему управления задачами и встречами команды!\n\nНажмите кнопку 'Начать' для регистрации или входа.", reply_markup=keyboard)
 
-def send_role_selection(chat_id, name):
-    keyboard = {
-        "inline_keyboard": [
-            [
-                {"text": "👨‍💼 Руководитель", "callback_data": "role_manager"},
-                {"text": "👥 Участник", "callback_data": "role_member"}
-            ]
-        ]
-    }
-    send_message(chat_id, f"Приятно познакомиться, {name}! Выберите вашу роль:", reply_markup=keyboard)
+ROLE_TITLES = {
+    "owner": "Владелец",
+    "manager": "Руководитель",
+    "member": "Участник",
+    "observer": "Наблюдатель",
+}
+
+def is_manager(user_state: UserState):
+    # Роль задаётся в каждой команде отдельно; управлять командой могут владелец и руководители
+    return user_state.role in ("owner", "manager")
+
+def register_user(chat_id, user_state: UserState, name):
+    result = auth_post_request(chat_id, name)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка регистрации: {result['error']}")
+        process_start_command(chat_id)
+        return
+
+    user_state.role = ""
+    user_state.name = name
+    user_state.state = "authorized"
+    send_message(chat_id, f"✅ Приятно познакомиться, {name}! Вы успешно зарегистрированы.")
+
+    # Проверяем наличие сохраненного кода приглашения
+    invite_code = user_state.data.get("invite_code")
+    if invite_code:
+        result = team_join_request(chat_id, invite_code)
+        if result["success"]:
+            report_join_result(chat_id, user_state, result)
+        else:
+            send_message(chat_id, f"❌ Ошибка присоединения к команде: {result['error']}")
+
+    send_main_menu(chat_id, user_state)
 
 def send_main_menu(chat_id, user_state: UserState):
     keyboard_buttons = []
     
-    if user_state.role == "manager":
-        if user_state.team_id:
-            # Менеджер с командой
-            keyboard_buttons.extend([
-                [{"text": "👥 Управление командой", "callback_data": "manage_team"}],
-                [{"text": "📋 Управление задачами", "callback_data": "manage_tasks"}],
-                [{"text": "📅 Управление встречами", "callback_data": "manage_meetings"}]
-            ])
-        else:
-            # Менеджер без команды
-            keyboard_buttons.append([{"text": "📝 Создать команду", "callback_data": "create_team"}])
+    if not user_state.team_id:
+        # Пользователь без команды может создать свою или присоединиться к существующей
+        keyboard_buttons.extend([
+            [{"text": "📝 Создать команду", "callback_data": "create_team"}],
+            [{"text": "🤝 Присоединиться к команде", "callback_data": "join_team"}]
+        ])
+    elif is_manager(user_state):
+        # Владелец или руководитель команды
+        keyboard_buttons.extend([
+            [{"text": "👥 Управление командой", "callback_data": "manage_team"}],
+            [{"text": "📋 Управление задачами", "callback_data": "manage_tasks"}],
+            [{"text": "📅 Управление встречами", "callback_data": "manage_meetings"}]
+        ])
     else:
-        # Участник
-        if user_state.team_id:
-            # Участник с командой
-            keyboard_buttons.extend([
-                [{"text": "📋 Мои задачи", "callback_data": "my_tasks"}],
-                [{"text": "📅 Мои встречи", "callback_data": "my_meetings"}]
-            ])
-        else:
-            # Участник без команды
-            keyboard_buttons.append([{"text": "🤝 Присоединиться к команде", "callback_data": "join_team"}])
+        # Участник или наблюдатель
+        keyboard_buttons.extend([
+            [{"text": "📋 Мои задачи", "callback_data": "my_tasks"}],
+            [{"text": "📅 Мои встречи", "callback_data": "my_meetings"}]
+        ])
     
+    if user_state.team_id:
+        keyboard_buttons.append([{"text": "🔍 Поиск", "callback_data": "search"}])
+        keyboard_buttons.append([{"text": "🔀 Мои команды", "callback_data": "my_teams"}])
     keyboard_buttons.append([{"text": "👤 Мой профиль", "callback_data": "my_profile"}])
     
     keyboard = {
@@ -171,10 +237,8 @@ def send_main_menu(chat_id, user_state: UserState):
     message = f"🏠 *Главное меню*\n"
     if user_state.team_name:
         message += f"\nВаша команда: *{user_state.team_name}*"
-    elif user_state.role == "manager":
-        message += "\n\n_Создайте свою команду, чтобы начать работу_"
-    elif user_state.role == "member":
-        message += "\n\n_Присоединитесь к команде, чтобы начать работу_"
+    else:
+        message += "\n\n_Создайте свою команду или присоединитесь к существующей, чтобы начать работу_"
     
     print(f"User state: {user_state}")  # Отладочный вывод
     print(f"Sending menu with buttons: {keyboard_buttons}")  # Отладочный вывод
@@ -185,13 +249,13 @@ def send_profile_menu(chat_id, user_state: UserState):
     message = (
         f"*👤 Профиль*\n\n"
         f"*Имя:* {user_state.name}\n"
-        f"*Роль:* {'Руководитель' if user_state.role == 'manager' else 'Участник'}\n"
     )
     
     if user_state.team_name:
         message += f"*Команда:* {user_state.team_name}\n"
-        # Добавляем кнопку выхода из команды только для участников
-        if user_state.role == "member":
+        message += f"*Роль:* {ROLE_TITLES.get(user_state.role, 'Участник')}\n"
+        # Владелец не может покинуть команду
+        if user_state.role != "owner":
             keyboard = {
                 "inline_keyboard": [
                     [{"text": "🚪 Покинуть команду", "callback_data": "leave_team"}],
@@ -232,21 +296,10 @@ def process_start_command(chat_id, command_args=None):
             )
             user_states[chat_id] = user_state
             
-            # Если пользователь уже в команде
-            if user_state.team_id:
-                send_message(chat_id, "❌ Вы уже состоите в команде")
-                send_main_menu(chat_id, user_state)
-                return
-            
-            # Пробуем присоединить к команде
+            # Пробуем присоединить к команде; пользователь может состоять в нескольких командах
             result = team_join_request(chat_id, invite_code)
             if result["success"]:
-                send_message(chat_id, "✅ Вы успешно присоединились к команде!")
-                # Обновляем информацию о команде
-                team_info = team_get_my_request(chat_id)
-                if team_info["success"]:
-                    user_state.team_name = team_info["data"].get("Name", "")
-                    user_state.team_id = team_info["data"].get("ID")
+                report_join_result(chat_id, user_state, result)
                 send_main_menu(chat_id, user_state)
             else:
                 send_message(chat_id, f"❌ Ошибка: {result['error']}")
@@ -275,9 +328,7 @@ def process_start_command(chat_id, command_args=None):
         )
         
         if user_state.team_id:
-            team_result = team_get_my_request(chat_id)
-            if team_result["success"]:
-                user_state.team_name = team_result["data"].get("Name", "")
+            refresh_active_team(chat_id, user_state)
         
         user_states[chat_id] = user_state
         send_main_menu(chat_id, user_states[chat_id])
@@ -303,45 +354,10 @@ def process_callback(callback):
         process_start_command(chat_id)
         return
 
-    if data.startswith("role_"):
-        if user_state.state == "awaiting_role":
-            role = data.split("_")[1]
-            name = user_state.data.get("name")
-            
-            result = auth_post_request(chat_id, name, role)
-            if result["success"]:
-                user_state.role = role
-                user_state.name = name
-                user_state.state = "authorized"
-                send_message(chat_id, "✅ Вы успешно зарегистрированы!")
-                
-                # Проверяем наличие сохраненного кода приглашения
-                invite_code = user_state.data.get("invite_code")
-                if invite_code:
-                    result = team_join_request(chat_id, invite_code)
-                    if result["success"]:
-                        send_message(chat_id, "✅ Вы успешно присоединились к команде!")
-                        team_info = team_get_my_request(chat_id)
-                        if team_info["success"]:
-                            user_state.team_name = team_info["data"].get("Name", "")
-                            user_state.team_id = team_info["data"].get("ID")
-                    else:
-                        send_message(chat_id, f"❌ Ошибка присоединения к команде: {result['error']}")
-                
-                send_main_menu(chat_id, user_state)
-            else:
-                send_message(chat_id, f"❌ Ошибка регистрации: {result['error']}")
-                process_start_command(chat_id)
-            return
-
     # Обработка создания команды
     if data == "create_team":
-        if user_state.role == "manager":
-            user_state.state = "awaiting_team_name"
-            send_message(chat_id, "Введите название команды:")
-        else:
-            send_message(chat_id, "❌ Только руководитель может создать команду")
-            send_main_menu(chat_id, user_state)
+        user_state.state = "awaiting_team_name"
+        send_message(chat_id, "Введите название команды:")
         return
 
     # Добавим обработку команд для команды
@@ -349,21 +365,81 @@ def process_callback(callback):
         send_team_management_menu(chat_id)
     elif data == "join_team":
         send_team_join_menu(chat_id)
+    elif data == "my_teams":
+        send_my_teams_menu(chat_id)
+    elif data.startswith("switch_team_"):
+        team_id = int(data.split("_")[2])
+        result = team_set_active_request(chat_id, team_id)
+        if result["success"]:
+            team = result["data"]
+            user_state.team_id = team.get("id")
+            user_state.team_name = team.get("name", "")
+            user_state.role = team.get("role", "")
+            send_message(chat_id, f"✅ Активная команда: *{user_state.team_name}*")
+            send_main_menu(chat_id, user_state)
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+            send_my_teams_menu(chat_id)
     elif data == "team_info":
         result = team_get_my_request(chat_id)
         if result["success"]:
             team_data = result["data"]
+            requires_approval = team_data.get("requires_approval", False)
+            archived = bool(team_data.get("archived_at"))
             message = (
                 f"*Информация о команде*\n"
-                f"Название: *{team_data.get('Name', 'Н/Д')}*\n"
-                f"Описание: _{team_data.get('Description', 'Отсутствует')}_"
+                f"Название: *{team_data.get('name', 'Н/Д')}*\n"
+                f"Описание: _{team_data.get('description') or 'Отсутствует'}_\n"
+                f"Вступление: {'по заявке' if requires_approval else 'сразу по приглашению'}"
             )
-            keyboard = {
-                "inline_keyboard": [[{"text": "🔙 Назад", "callback_data": "manage_team"}]]
-            }
+            if archived:
+                message += "\n\n🗄 _Команда в архиве, доступен только просмотр_"
+            keyboard = {"inline_keyboard": []}
+            if archived and user_state.role == "owner":
+                keyboard["inline_keyboard"].extend([
+                    [{"text": "♻️ Восстановить из архива", "callback_data": "team_restore"}],
+                    [{"text": "🗑 Удалить навсегда", "callback_data": "team_purge"}]
+                ])
+            elif is_manager(user_state):
+                keyboard["inline_keyboard"].append([{
+                    "text": "🔓 Вступать без одобрения" if requires_approval else "🔒 Вступать по заявке",
+                    "callback_data": "toggle_join_approval"
+                }])
+            keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_team"}])
             send_message(chat_id, message, reply_markup=keyboard)
         else:
             send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data == "toggle_join_approval":
+        result = team_get_my_request(chat_id)
+        if result["success"]:
+            team_data = result["data"]
+            result = team_update_request(
+                chat_id,
+                team_data.get("name", ""),
+                team_data.get("description", ""),
+                not team_data.get("requires_approval", False),
+            )
+        if result["success"]:
+            send_message(chat_id, "✅ Настройка вступления изменена")
+            process_callback({"id": callback_id, "message": callback["message"], "data": "team_info"})
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data == "team_workflow":
+        send_team_workflow_menu(chat_id)
+    elif data == "team_requests":
+        send_team_requests_menu(chat_id)
+    elif data.startswith("approve_join_") or data.startswith("reject_join_"):
+        action, _, request_id = data.split("_")
+        result = team_join_decision_request(chat_id, request_id, action)
+        if result["success"]:
+            name = result["data"].get("name", "")
+            if action == "approve":
+                send_message(chat_id, f"✅ {name} принят(а) в команду")
+            else:
+                send_message(chat_id, f"Заявка {name} отклонена")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_team_requests_menu(chat_id)
     elif data == "team_invite":
         result = team_get_invite_request(chat_id)
         if result["success"]:
@@ -375,6 +451,26 @@ def process_callback(callback):
             send_message(chat_id, message, reply_markup=keyboard)
         else:
             send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data == "team_invites":
+        send_team_invites_menu(chat_id)
+    elif data == "team_invite_single":
+        result = team_create_invite_request(chat_id, max_uses=1)
+        if result["success"]:
+            message = f"*Одноразовая ссылка для приглашения:*\n`{result['data'].get('url')}`"
+            keyboard = {
+                "inline_keyboard": [[{"text": "🔙 Назад", "callback_data": "team_invites"}]]
+            }
+            send_message(chat_id, message, reply_markup=keyboard)
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data.startswith("revoke_invite_"):
+        code = data[len("revoke_invite_"):]
+        result = team_revoke_invite_request(chat_id, code)
+        if result["success"]:
+            send_message(chat_id, "✅ Приглашение отозвано")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_team_invites_menu(chat_id)
     elif data == "team_members":
         result = team_get_members_request(chat_id)
         if result["success"]:
@@ -383,14 +479,20 @@ def process_callback(callback):
             keyboard = {"inline_keyboard": []}
             
             for member in members:
-                member_name = member.get('Name', 'Н/Д')
-                member_id = member.get('TelegramID')
-                message += f"👤 {member_name}\n"
-                if user_state.role == "manager" and member_id:
+                member_name = member.get('name', 'Н/Д')
+                member_id = member.get('telegram_id')
+                member_role = member.get('role', '')
+                message += f"👤 {member_name} — {ROLE_TITLES.get(member_role, member_role)}\n"
+                if is_manager(user_state) and member_id and member_role != "owner":
                     keyboard["inline_keyboard"].append([{
                         "text": f"❌ Исключить {member_name}",
                         "callback_data": f"kick_member_{member_id}"
                     }])
+                if user_state.role == "owner" and member_id:
+                    keyboard["inline_keyboard"].append([{
+                        "text": f"👑 Передать команду {member_name}",
+                        "callback_data": f"transfer_to_{member_id}"
+                    }])
             
             keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_team"}])
             send_message(chat_id, message, reply_markup=keyboard)
@@ -410,30 +512,103 @@ def process_callback(callback):
             process_callback({"id": callback_id, "message": callback["message"], "data": "team_members"})
         else:
             send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data.startswith("transfer_to_"):
+        member_id = data.split("_")[2]
+        keyboard = {
+            "inline_keyboard": [
+                [
+                    {"text": "✅ Да, передать", "callback_data": f"confirm_transfer_{member_id}"},
+                    {"text": "❌ Нет, отмена", "callback_data": "team_members"}
+                ]
+            ]
+        }
+        send_message(chat_id, "⚠️ *Передать права владельца команды?*\nПосле того как участник примет предложение, вы станете руководителем.", reply_markup=keyboard)
+    elif data.startswith("confirm_transfer_"):
+        member_id = data.split("_")[2]
+        result = team_transfer_request(chat_id, member_id)
+        if result["success"]:
+            send_message(chat_id, "✅ Предложение отправлено. Права перейдут после того, как участник его примет.")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_team_management_menu(chat_id)
+    elif data.startswith("accept_transfer_") or data.startswith("decline_transfer_"):
+        action, _, transfer_id = data.split("_")
+        result = team_transfer_answer_request(chat_id, transfer_id, action)
+        if result["success"]:
+            if action == "accept":
+                send_message(chat_id, f"👑 Теперь вы владелец команды *{result['data'].get('team_name', '')}*")
+                refresh_active_team(chat_id, user_state)
+            else:
+                send_message(chat_id, "Вы отклонили предложение")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        if user_state.state == "authorized":
+            send_main_menu(chat_id, user_state)
     elif data == "team_delete":
         keyboard = {
             "inline_keyboard": [
                 [
-                    {"text": "✅ Да, удалить", "callback_data": "confirm_team_delete"},
+                    {"text": "✅ Да, в архив", "callback_data": "confirm_team_delete"},
                     {"text": "❌ Нет, отмена", "callback_data": "manage_team"}
                 ]
             ]
         }
-        send_message(chat_id, "⚠️ *Вы уверены, что хотите удалить команду?*\nЭто действие нельзя отменить.", reply_markup=keyboard)
+        send_message(chat_id, "⚠️ *Перенести команду в архив?*\nЗадачи и встречи станут доступны только для просмотра, вступление по приглашениям закроется. Команду можно будет восстановить.", reply_markup=keyboard)
     elif data == "confirm_team_delete":
         result = team_delete_request(chat_id)
         if result["success"]:
-            send_message(chat_id, "✅ Команда успешно удалена")
-            user_state.team_name = ""
-            user_state.team_id = None
+            send_message(chat_id, "✅ Команда перенесена в архив")
+            process_callback({"id": callback_id, "message": callback["message"], "data": "team_info"})
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+            send_team_management_menu(chat_id)
+    elif data == "team_restore":
+        result = team_archive_action_request(chat_id, "post", "restore")
+        if result["success"]:
+            send_message(chat_id, "✅ Команда восстановлена из архива")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_team_management_menu(chat_id)
+    elif data == "team_purge":
+        result = team_archive_action_request(chat_id, "get", "purge")
+        if result["success"]:
+            preview = result["data"]
+            message = (
+                f"⚠️ *Удалить команду {preview.get('team_name', '')} навсегда?*\n\n"
+                f"Будут удалены:\n"
+                f"📋 задачи: {preview.get('tasks', 0)}\n"
+                f"📅 встречи: {preview.get('meetings', 0)}\n"
+                f"🔗 приглашения: {preview.get('invite_links', 0)}\n"
+                f"👥 участия: {preview.get('members', 0)}\n\n"
+                f"Это действие нельзя отменить."
+            )
+            keyboard = {
+                "inline_keyboard": [
+                    [
+                        {"text": "🗑 Да, удалить", "callback_data": "confirm_team_purge"},
+                        {"text": "❌ Нет, отмена", "callback_data": "team_info"}
+                    ]
+                ]
+            }
+            send_message(chat_id, message, reply_markup=keyboard)
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+    elif data == "confirm_team_purge":
+        result = team_archive_action_request(chat_id, "delete", "purge")
+        if result["success"]:
+            send_message(chat_id, "✅ Команда удалена навсегда")
+            refresh_active_team(chat_id, user_state)
             send_main_menu(chat_id, user_state)
         else:
             send_message(chat_id, f"❌ Ошибка: {result['error']}")
             send_team_management_menu(chat_id)
+    elif data == "search":
+        user_state.state = "awaiting_search_query"
+        send_message(chat_id, "Что найти? Поиск идёт по задачам, комментариям и встречам ваших команд:")
     elif data == "my_profile":
         send_profile_menu(chat_id, user_state)
     elif data == "leave_team":
-        if user_state.role == "member":
+        if user_state.role != "owner":
             keyboard = {
                 "inline_keyboard": [
                     [
@@ -444,14 +619,13 @@ def process_callback(callback):
             }
             send_message(chat_id, "⚠️ *Вы уверены, что хотите покинуть команду?*", reply_markup=keyboard)
         else:
-            send_message(chat_id, "❌ Руководитель не может покинуть команду")
+            send_message(chat_id, "❌ Владелец не может покинуть команду. Сначала передайте права владельца в списке участников")
             send_profile_menu(chat_id, user_state)
     elif data == "confirm_leave_team":
         result = team_leave_request(chat_id)
         if result["success"]:
             send_message(chat_id, "✅ Вы успешно покинули команду")
-            user_state.team_id = None
-            user_state.team_name = ""
+            refresh_active_team(chat_id, user_state)
             send_main_menu(chat_id, user_state)
         else:
             send_message(chat_id, f"❌ Ошибка: {result['error']}")
@@ -460,6 +634,14 @@ def process_callback(callback):
         send_task_management_menu(chat_id, user_state)
     elif data == "my_tasks":
         send_my_tasks_menu(chat_id)
+    elif data == "my_tasks_overdue":
+        send_my_tasks_menu(chat_id, overdue=True)
+    elif data == "my_tasks_more":
+        send_my_tasks_menu(
+            chat_id,
+            overdue=user_state.data.get("my_tasks_overdue", False),
+            cursor=user_state.data.get("my_tasks_cursor"),
+        )
     elif data == "issued_tasks":
         send_issued_tasks_menu(chat_id)
     elif data == "create_task":
@@ -481,25 +663,35 @@ def process_callback(callback):
     elif data.startswith("update_task_status_"):
         task_id = data.split("_")[-1]
         user_state.data["updating_task_id"] = task_id
-        keyboard = {
-            "inline_keyboard": [
-                [
-                    {"text": "🔄 В работе", "callback_data": f"set_status_in_progress_{task_id}"},
-                    {"text": "✅ Завершена", "callback_data": f"set_status_completed_{task_id}"}
-                ],
-                [{"text": "🔙 Назад", "callback_data": "my_tasks"}]
-            ]
-        }
+        result = team_workflow_request(chat_id)
+        if not result["success"]:
+            send_message(chat_id, f"❌ Ошибка получения процесса команды: {result['error']}")
+            return
+        workflow = result["data"]
+        statuses = {s["key"]: s for s in workflow.get("statuses", [])}
+        # Статусы, в которые можно перейти не из статуса проверки: решения по проверке принимает автор задачи
+        targets = []
+        for transition in workflow.get("transitions", []):
+            source = statuses.get(transition["from"], {})
+            if not source.get("review") and transition["to"] not in targets:
+                targets.append(transition["to"])
+        user_state.data["report_statuses"] = [
+            key for key, status in statuses.items() if status.get("terminal") or status.get("review")
+        ]
+        keyboard = {"inline_keyboard": []}
+        for key in [s["key"] for s in workflow.get("statuses", []) if s["key"] in targets]:
+            status = statuses[key]
+            title = f"📨 {status['title']}" if status.get("terminal") or status.get("review") else f"🔄 {status['title']}"
+            keyboard["inline_keyboard"].append([{"text": title, "callback_data": f"set_status_{key}_{task_id}"}])
+        keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "my_tasks"}])
         send_message(chat_id, "Выберите новый статус задачи:", reply_markup=keyboard)
     elif data.startswith("set_status_"):
-        parts = data.split("_")
-        status = parts[-2]
-        task_id = parts[-1]
+        status, task_id = data[len("set_status_"):].rsplit("_", 1)
         user_state.data["updating_task_id"] = task_id
         user_state.data["new_status"] = status
         user_state.state = "awaiting_completion_text"
-        if status == "completed":
-            send_message(chat_id, "Введите отчет о выполнении задачи (или отправьте '-' если отчет не требуется):")
+        if status in user_state.data.get("report_statuses", ["completed"]):
+            send_message(chat_id, "Введите отчет о выполнении задачи для проверки автором (или отправьте '-' если отчет не требуется):")
         else:
             result = tasks_update_status_request(chat_id, task_id, status)
             if result["success"]:
@@ -507,6 +699,153 @@ def process_callback(callback):
                 send_my_tasks_menu(chat_id)
             else:
                 send_message(chat_id, f"❌ Ошибка обновления статуса: {result['error']}")
+    elif data == "task_reviews":
+        send_reviews_menu(chat_id)
+    elif data == "task_templates":
+        send_templates_menu(chat_id)
+    elif data.startswith("use_template_"):
+        template_id = data.split("_")[-1]
+        result = team_template_request(chat_id, template_id)
+        if not result["success"]:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+            return
+        user_state.data["template_id"] = template_id
+        user_state.data["template_variables"] = result["data"].get("variables") or []
+        user_state.data["template_values"] = {}
+        ask_template_variable(chat_id, user_state)
+    elif data.startswith("task_history_"):
+        send_task_history(chat_id, data.split("_")[-1])
+    elif data.startswith("task_comments_"):
+        send_task_comments(chat_id, data.split("_")[-1])
+    elif data.startswith("task_checklist_"):
+        send_task_checklist(chat_id, data.split("_")[-1])
+    elif data.startswith("toggle_check_"):
+        _, _, task_id, item_id, done = data.split("_")
+        result = tasks_checklist_update_request(chat_id, task_id, item_id, {"done": done == "1"})
+        if not result["success"]:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_task_checklist(chat_id, task_id)
+    elif data.startswith("add_check_"):
+        user_state.data["checklist_task_id"] = data.split("_")[-1]
+        user_state.state = "awaiting_checklist_text"
+        send_message(chat_id, "Введите текст пункта чек-листа:")
+    elif data.startswith("task_files_"):
+        send_task_files(chat_id, data.split("_")[-1])
+    elif data.startswith("add_attachment_"):
+        user_state.data["attachment_task_id"] = data.split("_")[-1]
+        user_state.state = "awaiting_attachment"
+        send_message(chat_id, "Отправьте файл или фото, которое нужно приложить к задаче:")
+    elif data.startswith("get_attachment_"):
+        _, _, task_id, attachment_id = data.split("_")
+        result = tasks_download_attachment_request(chat_id, task_id, attachment_id)
+        if result["success"]:
+            send_document(chat_id, result["file_name"], result["content"])
+        else:
+            send_message(chat_id, f"❌ Ошибка скачивания файла: {result['error']}")
+    elif data.startswith("add_comment_"):
+        _, _, task_id, parent_id = (data.split("_") + [""])[:4]
+        user_state.data["comment_task_id"] = task_id
+        user_state.data["comment_parent_id"] = parent_id
+        user_state.state = "awaiting_comment_text"
+        send_message(chat_id, "Напишите комментарий. Чтобы упомянуть участника, укажите @Имя:")
+    elif data.startswith("approve_review_"):
+        review_id = data.split("_")[-1]
+        result = tasks_review_decision_request(chat_id, review_id, "approve")
+        if result["success"]:
+            send_message(chat_id, f"✅ Работа {result['data'].get('name', '')} принята")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_reviews_menu(chat_id)
+    elif data.startswith("reject_review_"):
+        user_state.data["review_id"] = data.split("_")[-1]
+        user_state.state = "awaiting_review_comment"
+        send_message(chat_id, "Напишите, что нужно доработать:")
+    elif data.startswith("edit_task_field_"):
+        _, _, _, field, task_id = data.split("_", 4)
+        user_state.data["editing_task_id"] = task_id
+        user_state.data["editing_task_field"] = field
+        user_state.state = "awaiting_task_edit_value"
+        prompts = {
+            "title": "Введите новый заголовок задачи:",
+            "description": "Введите новое описание задачи:",
+            "deadline": "Введите новый срок в формате ГГГГ-ММ-ДД ЧЧ:ММ\nНапример: 2024-03-25 15:00",
+        }
+        send_message(chat_id, prompts.get(field, "Введите новое значение:"))
+    elif data.startswith("edit_task_priority_"):
+        task_id = data.split("_")[-1]
+        keyboard = {"inline_keyboard": [
+            [{"text": f"{icon} {title}", "callback_data": f"set_priority_{key}_{task_id}"}]
+            for key, (icon, title) in TASK_PRIORITIES.items()
+        ]}
+        keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": f"edit_task_{task_id}"}])
+        send_message(chat_id, "Выберите приоритет задачи:", reply_markup=keyboard)
+    elif data.startswith("set_priority_"):
+        _, _, priority, task_id = data.split("_")
+        result = tasks_update_request(chat_id, task_id, {"priority": priority})
+        if result["success"]:
+            send_message(chat_id, "✅ Приоритет изменён")
+        else:
+            send_message(chat_id, f"❌ Ошибка изменения задачи: {result['error']}")
+        send_issued_tasks_menu(chat_id)
+    elif data.startswith("edit_task_"):
+        task_id = data.split("_")[-1]
+        keyboard = {
+            "inline_keyboard": [
+                [{"text": "📝 Заголовок", "callback_data": f"edit_task_field_title_{task_id}"}],
+                [{"text": "📄 Описание", "callback_data": f"edit_task_field_description_{task_id}"}],
+                [{"text": "⏰ Срок", "callback_data": f"edit_task_field_deadline_{task_id}"}],
+                [{"text": "🔥 Приоритет", "callback_data": f"edit_task_priority_{task_id}"}],
+                [{"text": "🔙 Назад", "callback_data": "issued_tasks"}]
+            ]
+        }
+        send_message(chat_id, "Что изменить в задаче?", reply_markup=keyboard)
+    elif data == "bulk_tasks":
+        user_state.data["bulk_task_ids"] = []
+        send_bulk_tasks_menu(chat_id, user_state)
+    elif data.startswith("bulk_toggle_"):
+        task_id = int(data.split("_")[-1])
+        selected = user_state.data.setdefault("bulk_task_ids", [])
+        if task_id in selected:
+            selected.remove(task_id)
+        else:
+            selected.append(task_id)
+        send_bulk_tasks_menu(chat_id, user_state)
+    elif data == "bulk_deadline":
+        if not user_state.data.get("bulk_task_ids"):
+            send_message(chat_id, "Сначала выберите задачи")
+            return
+        user_state.state = "awaiting_bulk_deadline"
+        send_message(chat_id, "Введите новый срок для выбранных задач в формате ГГГГ-ММ-ДД ЧЧ:ММ\nНапример: 2024-03-25 15:00")
+    elif data == "bulk_delete":
+        selected = user_state.data.get("bulk_task_ids") or []
+        if not selected:
+            send_message(chat_id, "Сначала выберите задачи")
+            return
+        keyboard = {
+            "inline_keyboard": [
+                [
+                    {"text": "✅ Да, удалить", "callback_data": "bulk_confirm_delete"},
+                    {"text": "❌ Нет, отмена", "callback_data": "issued_tasks"}
+                ]
+            ]
+        }
+        send_message(chat_id, f"⚠️ Удалить выбранные задачи ({len(selected)})?", reply_markup=keyboard)
+    elif data == "bulk_confirm_delete":
+        result = tasks_bulk_request(chat_id, user_state.data.get("bulk_task_ids") or [], {"action": "delete"})
+        if result["success"]:
+            send_message(chat_id, f"✅ Удалено задач: {len(result['data'].get('deleted') or [])}")
+        else:
+            send_message(chat_id, f"❌ Ошибка удаления задач: {result['error']}")
+        user_state.data["bulk_task_ids"] = []
+        send_issued_tasks_menu(chat_id)
+    elif data.startswith("stop_series_"):
+        series_id = data.split("_")[-1]
+        result = tasks_series_stop_request(chat_id, series_id)
+        if result["success"]:
+            send_message(chat_id, "⏹ Повторение остановлено, новые задачи создаваться не будут")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        send_issued_tasks_menu(chat_id)
     elif data.startswith("delete_task_"):
         task_id = data.split("_")[-1]
         keyboard = {
@@ -576,7 +915,7 @@ def process_callback(callback):
                 
                 message += "\n"
                 
-                if user_state.role == "manager":
+                if is_manager(user_state):
                     keyboard["inline_keyboard"].append([{
                         "text": f"❌ Отменить: {meeting['Title']}",
                         "callback_data": f"delete_meeting_{meeting['ID']}"
@@ -672,6 +1011,10 @@ def process_message(message):
         user_state = UserState(state="initial", data={})
         user_states[chat_id] = user_state
 
+    if user_state.state == "awaiting_attachment" and (message.get("document") or message.get("photo")):
+        process_attachment_message(chat_id, user_state, message)
+        return
+
     if text.startswith('/'):
         if text == '/start':
             process_start_command(chat_id)
@@ -684,8 +1027,7 @@ def process_message(message):
 
     if user_state.state == "awaiting_name":
         user_state.data["name"] = text
-        user_state.state = "awaiting_role"
-        send_role_selection(chat_id, text)
+        register_user(chat_id, user_state, text)
         return
 
     if user_state.state == "awaiting_team_name":
@@ -699,9 +1041,8 @@ def process_message(message):
         result = team_create_request(chat_id, user_state.data["team_name"], description)
         if result["success"]:
             send_message(chat_id, "✅ Команда успешно создана!")
-            # Обновляем состояние пользователя
-            user_state.team_name = user_state.data["team_name"]
-            user_state.team_id = result["data"].get("ID")  # Используем 'ID' вместо 'id'
+            # Новая команда становится активной
+            refresh_active_team(chat_id, user_state)
             user_state.state = "authorized"
             send_main_menu(chat_id, user_state)
         else:
@@ -712,12 +1053,7 @@ def process_message(message):
     elif user_state.state == "awaiting_invite_code":
         result = team_join_request(chat_id, text)
         if result["success"]:
-            send_message(chat_id, "✅ Вы успешно присоединились к команде!")
-            # Обновляем информацию о команде
-            team_info = team_get_my_request(chat_id)
-            if team_info["success"]:
-                user_state.team_name = team_info["data"].get("Name", "")  # Используем 'Name' вместо 'name'
-                user_state.team_id = team_info["data"].get("ID")  # Используем 'ID' вместо 'id'
+            report_join_result(chat_id, user_state, result)
             user_state.state = "authorized"
             send_main_menu(chat_id, user_state)
         else:
@@ -750,8 +1086,8 @@ def process_message(message):
                     keyboard = {"inline_keyboard": []}
                     for member in members:
                         keyboard["inline_keyboard"].append([{
-                            "text": member.get("Name", "Н/Д"),
-                            "callback_data": f"assign_task_{member.get('TelegramID')}"
+                            "text": member.get("name", "Н/Д"),
+                            "callback_data": f"assign_task_{member.get('telegram_id')}"
                         }])
                     keyboard["inline_keyboard"].append([{"text": "🔙 Отмена", "callback_data": "manage_tasks"}])
                     send_message(chat_id, "Выберите исполнителя задачи:", reply_markup=keyboard)
@@ -775,6 +1111,26 @@ def process_message(message):
                     send_task_management_menu(chat_id, user_state)
         except ValueError:
             send_message(chat_id, "❌ Неверный формат даты. Попробуйте еще раз.\nФормат: ГГГГ-ММ-ДД ЧЧ:ММ")
+    elif user_state.state == "awaiting_task_edit_value":
+        field = user_state.data.get("editing_task_field")
+        value = text
+        if field == "deadline":
+            try:
+                value = datetime.strptime(text, "%Y-%m-%d %H:%M").replace(tzinfo=timezone.utc).isoformat()
+            except ValueError:
+                send_message(chat_id, "❌ Неверный формат даты. Попробуйте еще раз.\nФормат: ГГГГ-ММ-ДД ЧЧ:ММ")
+                return
+        result = tasks_update_request(chat_id, user_state.data["editing_task_id"], {field: value})
+        if result["success"]:
+            changes = result["data"].get("changes") or []
+            if changes:
+                send_message(chat_id, "✅ Задача изменена, участники получили уведомление")
+            else:
+                send_message(chat_id, "Изменений нет")
+        else:
+            send_message(chat_id, f"❌ Ошибка изменения задачи: {result['error']}")
+        user_state.state = "authorized"
+        send_issued_tasks_menu(chat_id)
     elif user_state.state == "awaiting_completion_text":
         completion_text = None if text == "-" else text
         result = tasks_update_status_request(
@@ -784,11 +1140,62 @@ def process_message(message):
             completion_text=completion_text
         )
         if result["success"]:
-            send_message(chat_id, "✅ Статус задачи обновлен")
+            send_message(chat_id, f"✅ {result['data'].get('message', 'Статус задачи обновлен')}")
             send_my_tasks_menu(chat_id)
         else:
             send_message(chat_id, f"❌ Ошибка обновления статуса: {result['error']}")
         user_state.state = "authorized"
+    elif user_state.state == "awaiting_review_comment":
+        result = tasks_review_decision_request(chat_id, user_state.data["review_id"], "reject", text)
+        if result["success"]:
+            send_message(chat_id, "↩️ Работа возвращена на доработку, исполнитель получил комментарий")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        user_state.state = "authorized"
+        send_reviews_menu(chat_id)
+
+    elif user_state.state == "awaiting_bulk_deadline":
+        try:
+            deadline = datetime.strptime(text, "%Y-%m-%d %H:%M").replace(tzinfo=timezone.utc).isoformat()
+        except ValueError:
+            send_message(chat_id, "❌ Неверный формат даты. Попробуйте еще раз.\nФормат: ГГГГ-ММ-ДД ЧЧ:ММ")
+            return
+        result = tasks_bulk_request(chat_id, user_state.data.get("bulk_task_ids") or [], {"action": "deadline", "deadline": deadline})
+        if result["success"]:
+            send_message(chat_id, f"✅ Срок изменён у задач: {len(result['data'].get('tasks') or [])}, исполнители получили уведомление")
+        else:
+            send_message(chat_id, f"❌ Ошибка изменения сроков: {result['error']}")
+        user_state.state = "authorized"
+        user_state.data["bulk_task_ids"] = []
+        send_issued_tasks_menu(chat_id)
+
+    elif user_state.state == "awaiting_template_variable":
+        name = user_state.data["template_variables"][len(user_state.data["template_values"])]
+        user_state.data["template_values"][name] = text
+        ask_template_variable(chat_id, user_state)
+
+    elif user_state.state == "awaiting_search_query":
+        user_state.state = "authorized"
+        send_search_results(chat_id, text)
+
+    elif user_state.state == "awaiting_checklist_text":
+        task_id = user_state.data["checklist_task_id"]
+        result = tasks_checklist_add_request(chat_id, task_id, text)
+        if not result["success"]:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        user_state.state = "authorized"
+        send_task_checklist(chat_id, task_id)
+
+    elif user_state.state == "awaiting_comment_text":
+        task_id = user_state.data["comment_task_id"]
+        parent_id = user_state.data.get("comment_parent_id")
+        result = tasks_comment_request(chat_id, task_id, text, int(parent_id) if parent_id else None)
+        if result["success"]:
+            send_message(chat_id, "💬 Комментарий добавлен")
+        else:
+            send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        user_state.state = "authorized"
+        send_task_comments(chat_id, task_id)
 
     elif user_state.state == "awaiting_meeting_title":
         user_state.data["title"] = text
@@ -802,7 +1209,7 @@ def process_message(message):
             
             if user_state.data["meeting_type"] == "offline":
                 # Получаем доступные слоты для выбранной аудитории
-                result = meetings_get_available_slots_request(user_state.data["room"], text)
+                result = meetings_get_available_slots_request(chat_id, user_state.data["room"], text)
                 if result["success"]:
                     available_slots = result["data"].get("available_slots", [])
                     if not available_slots:
@@ -844,15 +1251,15 @@ def process_message(message):
 
 def team_create_request(chat_id, name, description):
     url = f"{BACKEND_BASE_URL}/team"
-    params = {"telegram_id": str(chat_id)}
     payload = {"name": name, "description": description}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
     try:
-        response = requests.post(url, params=params, json=payload, headers=headers)
+        response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -862,9 +1269,10 @@ def team_create_request(chat_id, name, description):
 
 def team_join_request(chat_id, invite_code):
     url = f"{BACKEND_BASE_URL}/team/join"
-    payload = {"telegram_id": str(chat_id), "invite_code": invite_code}
+    payload = {"invite_code": invite_code}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
@@ -872,20 +1280,70 @@ def team_join_request(chat_id, invite_code):
         response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
+        elif response.status_code == 202:
+            # Команда требует одобрения: создана заявка на вступление
+            return {"success": True, "pending": True, "data": response.json()}
         else:
             return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
     except Exception as e:
         return {"success": False, "error": str(e)}
 
+def report_join_result(chat_id, user_state: UserState, result):
+    if result.get("pending"):
+        send_message(chat_id, "📨 Заявка на вступление отправлена. Вы получите уведомление, когда руководитель её рассмотрит.")
+        return
+    send_message(chat_id, "✅ Вы успешно присоединились к команде!")
+    # Новая команда становится активной
+    refresh_active_team(chat_id, user_state)
+
 def team_get_my_request(chat_id):
+    # /team/my возвращает все команды пользователя, здесь нужна только активная
+    result = team_list_request(chat_id)
+    if not result["success"]:
+        return result
+    for team in result["data"]:
+        if team.get("is_active"):
+            return {"success": True, "data": team}
+    return {"success": False, "error": "Нет активной команды"}
+
+def refresh_active_team(chat_id, user_state: UserState):
+    result = team_get_my_request(chat_id)
+    if result["success"]:
+        user_state.team_id = result["data"].get("id")
+        user_state.team_name = result["data"].get("name", "")
+        user_state.role = result["data"].get("role", "")
+    else:
+        user_state.team_id = None
+        user_state.team_name = ""
+        user_state.role = ""
+
+def team_set_active_request(chat_id, team_id):
+    url = f"{BACKEND_BASE_URL}/team/active"
+    payload = {"team_id": team_id}
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.put(url, json=payload, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_list_request(chat_id):
     url = f"{BACKEND_BASE_URL}/team/my"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -895,13 +1353,13 @@ def team_get_my_request(chat_id):
 
 def team_get_invite_request(chat_id):
     url = f"{BACKEND_BASE_URL}/team/invite"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.text}  # Возвращает URL для приглашения
         else:
@@ -909,15 +1367,17 @@ def team_get_invite_request(chat_id):
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def team_get_members_request(chat_id):
-    url = f"{BACKEND_BASE_URL}/team/members"
-    params = {"telegram_id": str(chat_id)}
+def team_create_invite_request(chat_id, role="member", max_uses=0, expires_in_hours=24):
+    url = f"{BACKEND_BASE_URL}/team/invites"
+    payload = {"role": role, "max_uses": max_uses, "expires_in_hours": expires_in_hours}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
+        "Content-Type": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -925,18 +1385,15 @@ def team_get_members_request(chat_id):
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def team_kick_member_request(chat_id, kick_telegram_id):
-    url = f"{BACKEND_BASE_URL}/team/kick"
-    params = {
-        "telegram_id": str(chat_id),
-        "kick_telegram_id": str(kick_telegram_id)
-    }
+def team_list_invites_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/invites"
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -944,15 +1401,15 @@ def team_kick_member_request(chat_id, kick_telegram_id):
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def team_leave_request(chat_id):
-    url = f"{BACKEND_BASE_URL}/team/leave"
-    params = {"telegram_id": str(chat_id)}
+def team_revoke_invite_request(chat_id, code):
+    url = f"{BACKEND_BASE_URL}/team/invites/{code}"
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.delete(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -960,37 +1417,15 @@ def team_leave_request(chat_id):
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-# Добавим функции для отображения меню команды
-def send_team_management_menu(chat_id):
-    keyboard = {
-        "inline_keyboard": [
-            [{"text": "📋 Информация о команде", "callback_data": "team_info"}],
-            [{"text": "🔗 Получить ссылку-приглашение", "callback_data": "team_invite"}],
-            [{"text": "👥 Список участников", "callback_data": "team_members"}],
-            [{"text": "❌ Удалить команду", "callback_data": "team_delete"}],
-            [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
-        ]
-    }
-    send_message(chat_id, "*Управление командой*\nВыберите действие:", reply_markup=keyboard)
-
-def send_team_join_menu(chat_id):
-    keyboard = {
-        "inline_keyboard": [
-            [{"text": "🔗 Ввести код приглашения", "callback_data": "enter_invite_code"}],
-            [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
-        ]
-    }
-    send_message(chat_id, "*Присоединение к команде*\nВыберите действие:", reply_markup=keyboard)
-
-def team_delete_request(chat_id):
-    url = f"{BACKEND_BASE_URL}/team"
-    params = {"telegram_id": str(chat_id)}
+def team_get_members_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/members"
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.delete(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -998,9 +1433,297 @@ def team_delete_request(chat_id):
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def tasks_create_request(chat_id, title, description, deadline, is_team=False, assigned_to=None):
-    url = f"{BACKEND_BASE_URL}/tasks"
-    params = {"telegram_id": str(chat_id)}
+def team_kick_member_request(chat_id, kick_telegram_id):
+    url = f"{BACKEND_BASE_URL}/team/kick"
+    params = {
+        "kick_telegram_id": str(kick_telegram_id)
+    }
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, params=params, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_transfer_request(chat_id, telegram_id):
+    url = f"{BACKEND_BASE_URL}/team/transfer"
+    payload = {"telegram_id": str(telegram_id)}
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.post(url, json=payload, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_transfer_answer_request(chat_id, transfer_id, action):
+    # action: "accept" или "decline"
+    url = f"{BACKEND_BASE_URL}/team/transfer/{transfer_id}/{action}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.post(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_update_request(chat_id, name, description, requires_approval):
+    url = f"{BACKEND_BASE_URL}/team"
+    payload = {"name": name, "description": description, "requires_approval": requires_approval}
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.put(url, json=payload, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_join_requests_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/requests"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_join_decision_request(chat_id, request_id, action):
+    # action: "approve" или "reject"
+    url = f"{BACKEND_BASE_URL}/team/requests/{request_id}/{action}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.post(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_leave_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/leave"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+# Добавим функции для отображения меню команды
+def send_team_management_menu(chat_id):
+    keyboard = {
+        "inline_keyboard": [
+            [{"text": "📋 Информация о команде", "callback_data": "team_info"}],
+            [{"text": "🔗 Получить ссылку-приглашение", "callback_data": "team_invite"}],
+            [{"text": "📨 Активные приглашения", "callback_data": "team_invites"}],
+            [{"text": "👥 Список участников", "callback_data": "team_members"}],
+            [{"text": "📥 Заявки на вступление", "callback_data": "team_requests"}],
+            [{"text": "🔀 Процесс задач", "callback_data": "team_workflow"}],
+            [{"text": "🗄 Архивировать команду", "callback_data": "team_delete"}],
+            [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
+        ]
+    }
+    send_message(chat_id, "*Управление командой*\nВыберите действие:", reply_markup=keyboard)
+
+def send_my_teams_menu(chat_id):
+    result = team_list_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        return
+
+    message = "*Мои команды*\n\n"
+    keyboard = {"inline_keyboard": []}
+    for team in result["data"]:
+        role = ROLE_TITLES.get(team.get("role"), team.get("role", ""))
+        mark = "✅ " if team.get("is_active") else ""
+        message += f"{mark}*{team.get('name', 'Н/Д')}* — {role}\n"
+        if not team.get("is_active"):
+            keyboard["inline_keyboard"].append([{
+                "text": f"🔀 {team.get('name', 'Н/Д')}",
+                "callback_data": f"switch_team_{team.get('id')}"
+            }])
+    keyboard["inline_keyboard"].extend([
+        [{"text": "📝 Создать команду", "callback_data": "create_team"}],
+        [{"text": "🤝 Присоединиться к команде", "callback_data": "join_team"}],
+        [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
+    ])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def send_team_invites_menu(chat_id):
+    result = team_list_invites_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        return
+
+    invites = result["data"]
+    message = "*Активные приглашения*\n\n"
+    if not invites:
+        message += "_Нет активных приглашений_\n"
+    keyboard = {"inline_keyboard": []}
+    for invite in invites:
+        limit = invite.get("max_uses") or "∞"
+        role = ROLE_TITLES.get(invite.get("role"), invite.get("role", ""))
+        expires = invite.get("expires_at", "")[:16].replace("T", " ")
+        message += f"`{invite.get('code')}` — {role}, использований {invite.get('uses', 0)}/{limit}, до {expires}\n"
+        keyboard["inline_keyboard"].append([{
+            "text": f"🚫 Отозвать {invite.get('code')}",
+            "callback_data": f"revoke_invite_{invite.get('code')}"
+        }])
+    keyboard["inline_keyboard"].extend([
+        [{"text": "➕ Одноразовая ссылка", "callback_data": "team_invite_single"}],
+        [{"text": "🔙 Назад", "callback_data": "manage_team"}]
+    ])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def team_workflow_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/workflow"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def send_team_workflow_menu(chat_id):
+    result = team_workflow_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        return
+
+    workflow = result["data"]
+    titles = {s["key"]: s["title"] for s in workflow.get("statuses", [])}
+    message = "*Процесс задач команды*\n"
+    message += "_Настроен командой_\n\n" if workflow.get("custom") else "_Стандартный_\n\n"
+    message += "*Статусы:*\n"
+    for status in workflow.get("statuses", []):
+        mark = " — завершающий" if status.get("terminal") else " — проверка" if status.get("review") else ""
+        message += f"▫️ {status['title']} (`{status['key']}`){mark}\n"
+    message += "\n*Переходы:*\n"
+    for transition in workflow.get("transitions", []):
+        roles = ", ".join(ROLE_TITLES.get(r, r) for r in transition.get("roles", [])) or "все исполнители"
+        message += f"▫️ {titles.get(transition['from'])} → {titles.get(transition['to'])}: {roles}\n"
+    message += "\n_Изменить процесс можно через API: PUT /team/workflow_"
+    keyboard = {"inline_keyboard": [[{"text": "🔙 Назад", "callback_data": "manage_team"}]]}
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def send_team_requests_menu(chat_id):
+    result = team_join_requests_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка: {result['error']}")
+        return
+
+    requests_list = result["data"]
+    message = "*Заявки на вступление*\n\n"
+    if not requests_list:
+        message += "_Нет заявок, ожидающих решения_\n"
+    keyboard = {"inline_keyboard": []}
+    for request in requests_list:
+        role = ROLE_TITLES.get(request.get("role"), request.get("role", ""))
+        created = request.get("created_at", "")[:16].replace("T", " ")
+        message += f"👤 {request.get('name', 'Н/Д')} — {role}, {created}\n"
+        keyboard["inline_keyboard"].append([
+            {"text": f"✅ {request.get('name', 'Н/Д')}", "callback_data": f"approve_join_{request.get('id')}"},
+            {"text": "❌ Отклонить", "callback_data": f"reject_join_{request.get('id')}"}
+        ])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_team"}])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def send_team_join_menu(chat_id):
+    keyboard = {
+        "inline_keyboard": [
+            [{"text": "🔗 Ввести код приглашения", "callback_data": "enter_invite_code"}],
+            [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
+        ]
+    }
+    send_message(chat_id, "*Присоединение к команде*\nВыберите действие:", reply_markup=keyboard)
+
+def team_archive_action_request(chat_id, method, action):
+    # action: "restore" (POST) или "purge" (GET — предпросмотр, DELETE — удаление)
+    url = f"{BACKEND_BASE_URL}/team/{action}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.request(method, url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_delete_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.delete(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_create_request(chat_id, title, description, deadline, is_team=False, assigned_to=None):
+    url = f"{BACKEND_BASE_URL}/tasks"
     payload = {
         "title": title,
         "description": description,
@@ -1012,11 +1735,12 @@ def tasks_create_request(chat_id, title, description, deadline, is_team=False, a
 
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
     try:
-        response = requests.post(url, params=params, json=payload, headers=headers)
+        response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1024,15 +1748,15 @@ def tasks_create_request(chat_id, title, description, deadline, is_team=False, a
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def tasks_get_request(chat_id):
+def tasks_get_request(chat_id, params=None):
     url = f"{BACKEND_BASE_URL}/tasks"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers, params=params)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1042,13 +1766,30 @@ def tasks_get_request(chat_id):
 
 def tasks_get_issued_request(chat_id):
     url = f"{BACKEND_BASE_URL}/tasks/issued"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_update_request(chat_id, task_id, changes):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.put(url, json=changes, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1058,13 +1799,50 @@ def tasks_get_issued_request(chat_id):
 
 def tasks_delete_request(chat_id, task_id):
     url = f"{BACKEND_BASE_URL}/tasks/{task_id}"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.delete(url, params=params, headers=headers)
+        response = requests.delete(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_bulk_request(chat_id, task_ids, operation):
+    url = f"{BACKEND_BASE_URL}/tasks/bulk"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.post(url, json={"task_ids": task_ids, **operation}, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        body = response.json()
+        error = body.get("error", f"Статус: {response.status_code}")
+        # Причины по задачам, из-за которых операция не выполнена
+        for failure in body.get("failures") or []:
+            error += f"\n▫️ Задача {failure.get('task_id')}: {failure.get('error')}"
+        return {"success": False, "error": error}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_series_stop_request(chat_id, series_id):
+    url = f"{BACKEND_BASE_URL}/tasks/series/{series_id}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.delete(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1074,7 +1852,6 @@ def tasks_delete_request(chat_id, task_id):
 
 def tasks_update_status_request(chat_id, task_id, status, completion_text=None, attachment=None):
     url = f"{BACKEND_BASE_URL}/tasks/{task_id}/status"
-    params = {"telegram_id": str(chat_id)}
     payload = {"status": status}
     if completion_text:
         payload["completion_text"] = completion_text
@@ -1083,11 +1860,50 @@ def tasks_update_status_request(chat_id, task_id, status, completion_text=None,
 
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.put(url, json=payload, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_reviews_request(chat_id, status="pending"):
+    url = f"{BACKEND_BASE_URL}/tasks/reviews"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, params={"status": status}, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_review_decision_request(chat_id, review_id, action, comment=None):
+    # action: "approve" или "reject"
+    url = f"{BACKEND_BASE_URL}/tasks/reviews/{review_id}/{action}"
+    payload = {}
+    if comment:
+        payload["comment"] = comment
+
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
     try:
-        response = requests.put(url, params=params, json=payload, headers=headers)
+        response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1099,11 +1915,13 @@ def format_task_status(status):
     status_emojis = {
         "assigned": "📝",
         "in_progress": "🔄",
+        "in_review": "🔎",
         "completed": "✅"
     }
     status_names = {
         "assigned": "Назначена",
         "in_progress": "В работе",
+        "in_review": "На проверке",
         "completed": "Завершена"
     }
     return f"{status_emojis.get(status, '❓')} {status_names.get(status, status)}"
@@ -1130,26 +1948,44 @@ def send_task_management_menu(chat_id, user_state: UserState):
         "inline_keyboard": [
             [{"text": "📝 Создать задачу", "callback_data": "create_task"}],
             [{"text": "📋 Выданные задачи", "callback_data": "issued_tasks"}],
+            [{"text": "🧩 По шаблону", "callback_data": "task_templates"}],
+            [{"text": "🔎 На проверке", "callback_data": "task_reviews"}],
             [{"text": "🔙 Назад", "callback_data": "back_to_main"}]
         ]
     }
     send_message(chat_id, "*Управление задачами*\nВыберите действие:", reply_markup=keyboard)
 
-def send_my_tasks_menu(chat_id):
-    result = tasks_get_request(chat_id)
+MY_TASKS_PAGE_SIZE = 10
+
+def send_my_tasks_menu(chat_id, overdue=False, cursor=None):
+    params = {"sort": "priority", "order": "desc", "limit": MY_TASKS_PAGE_SIZE}
+    if overdue:
+        params["overdue"] = "true"
+    if cursor:
+        params["cursor"] = cursor
+    result = tasks_get_request(chat_id, params)
     if not result["success"]:
         send_message(chat_id, f"❌ Ошибка получения задач: {result['error']}")
         return
 
-    tasks = result["data"]
+    page = result["data"]
+    tasks = page.get("items") or []
     if not tasks:
         keyboard = {
             "inline_keyboard": [[{"text": "🔙 Назад", "callback_data": "back_to_main"}]]
         }
-        send_message(chat_id, "*Мои задачи*\n\n_У вас пока нет задач_", reply_markup=keyboard)
+        empty = "_Просроченных задач нет_" if overdue else "_У вас пока нет задач_"
+        send_message(chat_id, f"*Мои задачи*\n\n{empty}", reply_markup=keyboard)
         return
 
-    message = "*Мои задачи:*\n\n"
+    # Курсор следующей страницы не помещается в callback_data, поэтому хранится в состоянии
+    user_state = user_states.get(chat_id)
+    if user_state:
+        user_state.data["my_tasks_cursor"] = page.get("next_cursor")
+        user_state.data["my_tasks_overdue"] = overdue
+
+    title = "Просроченные задачи" if overdue else "Мои задачи"
+    message = f"*{title}* ({page.get('total', len(tasks))}):\n\n"
     keyboard = {"inline_keyboard": []}
 
     for task in tasks:
@@ -1158,15 +1994,536 @@ def send_my_tasks_menu(chat_id):
         message += f"*{task.get('title', 'Без названия')}*\n"
         message += f"_{task.get('description', 'Без описания')}_\n"
         message += f"Статус: {status}\n"
-        message += f"Срок: {deadline}\n\n"
+        if task.get("is_team"):
+            message += f"Прогресс команды: {task.get('progress', 0)}%\n"
+        message += f"Срок: {deadline}\n"
+        message += format_task_breakdown(task)
+        message += f"Комментарии: {task.get('comment_count', 0)}, файлы: {len(task.get('attachments') or [])}\n\n"
         
-        if task.get("status") != "completed":
-            keyboard["inline_keyboard"].append([{
+        row = []
+        if task.get("status") not in ("completed", "in_review"):
+            row.append({
                 "text": f"✏️ Обновить статус: {task.get('title')}",
                 "callback_data": f"update_task_status_{task.get('id')}"
+            })
+        row.append({"text": "☑️", "callback_data": f"task_checklist_{task.get('id')}"})
+        row.append({"text": "💬", "callback_data": f"task_comments_{task.get('id')}"})
+        keyboard["inline_keyboard"].append(row)
+
+    navigation = []
+    if page.get("next_cursor"):
+        navigation.append({"text": "➡️ Ещё", "callback_data": "my_tasks_more"})
+    if overdue:
+        navigation.append({"text": "📋 Все задачи", "callback_data": "my_tasks"})
+    else:
+        navigation.append({"text": "⏰ Просроченные", "callback_data": "my_tasks_overdue"})
+    keyboard["inline_keyboard"].append(navigation)
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "back_to_main"}])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def team_templates_request(chat_id):
+    url = f"{BACKEND_BASE_URL}/team/templates"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_template_request(chat_id, template_id):
+    url = f"{BACKEND_BASE_URL}/team/templates/{template_id}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def team_template_instantiate_request(chat_id, template_id, variables):
+    url = f"{BACKEND_BASE_URL}/team/templates/{template_id}/instantiate"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+        "Content-Type": "application/json",
+    }
+    try:
+        response = requests.post(url, json={"items": [{"variables": variables}]}, headers=headers)
+        if response.status_code == 201:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def send_templates_menu(chat_id):
+    result = team_templates_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения шаблонов: {result['error']}")
+        return
+
+    keyboard = {"inline_keyboard": []}
+    if not result["data"]:
+        message = "*Шаблоны задач*\n\n_В команде пока нет шаблонов_"
+    else:
+        message = "*Шаблоны задач*\nВыберите шаблон:\n\n"
+        for template in result["data"]:
+            kind = "командная" if template.get("is_team") else "персональная"
+            message += f"🧩 *{template.get('name')}* — {kind}, срок {template.get('deadline')}\n"
+            keyboard["inline_keyboard"].append([{
+                "text": f"🧩 {template.get('name')}",
+                "callback_data": f"use_template_{template.get('id')}"
             }])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_tasks"}])
+    send_message(chat_id, message, reply_markup=keyboard)
 
-    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "back_to_main"}])
+def ask_template_variable(chat_id, user_state: UserState):
+    variables = user_state.data["template_variables"]
+    values = user_state.data["template_values"]
+    if len(values) < len(variables):
+        user_state.state = "awaiting_template_variable"
+        send_message(chat_id, f"Введите значение для {{{{{variables[len(values)]}}}}}:")
+        return
+
+    user_state.state = "authorized"
+    result = team_template_instantiate_request(chat_id, user_state.data["template_id"], values)
+    if result["success"]:
+        send_message(chat_id, f"✅ Создано задач по шаблону: {len(result['data'])}")
+    else:
+        send_message(chat_id, f"❌ Ошибка создания задач: {result['error']}")
+    send_task_management_menu(chat_id, user_state)
+
+def search_request(chat_id, query):
+    url = f"{BACKEND_BASE_URL}/search"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers, params={"q": query, "limit": 10})
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+SEARCH_RESULT_ICONS = {"task": "📋", "comment": "💬", "meeting": "📅"}
+
+def format_snippet(snippet):
+    # Сервер выделяет найденные слова тегами <b></b>, бот показывает их жирным в Markdown
+    for ch in ("*", "_", "`", "["):
+        snippet = snippet.replace(ch, "")
+    return snippet.replace("<b>", "*").replace("</b>", "*")
+
+def send_search_results(chat_id, query):
+    result = search_request(chat_id, query)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка поиска: {result['error']}")
+        return
+
+    keyboard = {"inline_keyboard": []}
+    if not result["data"]:
+        message = f"🔍 По запросу «{query}» ничего не найдено"
+    else:
+        message = f"🔍 *Результаты поиска:* {query}\n\n"
+        shown_tasks = set()
+        for item in result["data"]:
+            icon = SEARCH_RESULT_ICONS.get(item.get("type"), "▫️")
+            message += f"{icon} *{item.get('title', '')}*\n{format_snippet(item.get('snippet', ''))}\n\n"
+            if item.get("task_id") and item["task_id"] not in shown_tasks:
+                shown_tasks.add(item["task_id"])
+                keyboard["inline_keyboard"].append([{
+                    "text": f"💬 {item.get('title', '')}",
+                    "callback_data": f"task_comments_{item['task_id']}"
+                }])
+    keyboard["inline_keyboard"].append([
+        {"text": "🔍 Искать ещё", "callback_data": "search"},
+        {"text": "🔙 Назад", "callback_data": "back_to_main"}
+    ])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def tasks_history_request(chat_id, task_id):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/history"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+TASK_EVENT_TITLES = {
+    "created": "создал(а) задачу",
+    "updated": "изменил(а) задачу",
+    "reassigned": "сменил(а) исполнителя",
+    "status_changed": "сменил(а) статус",
+    "review_approved": "принял(а) работу",
+    "review_rejected": "вернул(а) на доработку",
+    "deleted": "удалил(а) задачу",
+}
+
+def send_task_history(chat_id, task_id):
+    result = tasks_history_request(chat_id, task_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения истории: {result['error']}")
+        return
+
+    message = "*История задачи*\n\n"
+    for event in result["data"]:
+        created = event.get("created_at", "")[:16].replace("T", " ")
+        title = TASK_EVENT_TITLES.get(event.get("type"), event.get("type"))
+        message += f"🕘 {created} — {event.get('actor_name', 'Н/Д')} {title}\n"
+        if event.get("type") == "status_changed":
+            message += f"   {format_task_status(event.get('from_status'))} → {format_task_status(event.get('to_status'))}\n"
+        for change in event.get("changes", []):
+            message += f"   {change['field']}: {change['old']} → {change['new']}\n"
+        if event.get("completion_text"):
+            message += f"   _{event['completion_text']}_\n"
+        if event.get("comment"):
+            message += f"   💬 {event['comment']}\n"
+    keyboard = {"inline_keyboard": [[{"text": "🔙 Назад", "callback_data": "manage_tasks"}]]}
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def tasks_comments_request(chat_id, task_id):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/comments"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_comment_request(chat_id, task_id, text, parent_id=None):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/comments"
+    headers = {
+        "Content-Type": "application/json",
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+    }
+    payload = {"text": text}
+    if parent_id:
+        payload["parent_id"] = parent_id
+    try:
+        response = requests.post(url, json=payload, headers=headers)
+        if response.status_code == 201:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+TASK_PRIORITIES = {
+    "urgent": ("🔴", "Срочный"),
+    "high": ("🟠", "Высокий"),
+    "medium": ("🟡", "Обычный"),
+    "low": ("⚪️", "Низкий"),
+}
+
+def format_task_breakdown(task):
+    text = ""
+    icon, title = TASK_PRIORITIES.get(task.get("priority"), TASK_PRIORITIES["medium"])
+    text += f"Приоритет: {icon} {title}\n"
+    estimate = []
+    if task.get("story_points") is not None:
+        estimate.append(f"{task['story_points']} SP")
+    if task.get("estimate_hours") is not None:
+        estimate.append(f"{task['estimate_hours']:g} ч")
+    if estimate:
+        text += f"Оценка: {', '.join(estimate)}\n"
+    if task.get("labels"):
+        text += "Метки: " + ", ".join(f"#{label['name']}" for label in task["labels"]) + "\n"
+    if task.get("subtask_count"):
+        text += f"Подзадачи: {task.get('subtasks_done', 0)}/{task['subtask_count']}, прогресс {task.get('progress', 0)}%\n"
+    if task.get("checklist_total"):
+        text += f"Чек-лист: {task.get('checklist_done', 0)}/{task['checklist_total']}\n"
+    if task.get("series_id"):
+        text += "🔁 Повторяющаяся задача\n"
+    if task.get("blocked_by"):
+        text += f"⛔ Заблокирована: ждёт завершения задач ({task['blocked_by']})\n"
+    return text
+
+def tasks_checklist_request(chat_id, task_id):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/checklist"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_checklist_add_request(chat_id, task_id, text):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/checklist"
+    headers = {
+        "Content-Type": "application/json",
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+    }
+    try:
+        response = requests.post(url, json={"text": text}, headers=headers)
+        if response.status_code == 201:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_checklist_update_request(chat_id, task_id, item_id, changes):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/checklist/{item_id}"
+    headers = {
+        "Content-Type": "application/json",
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+    }
+    try:
+        response = requests.put(url, json=changes, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def send_task_checklist(chat_id, task_id):
+    result = tasks_checklist_request(chat_id, task_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения чек-листа: {result['error']}")
+        return
+
+    items = result["data"]
+    message = "*Чек-лист задачи*\n\n"
+    if not items:
+        message += "_Пунктов пока нет_\n"
+    keyboard = {"inline_keyboard": []}
+    for item in items:
+        mark = "✅" if item.get("done") else "⬜️"
+        message += f"{mark} {item.get('text')}\n"
+        keyboard["inline_keyboard"].append([{
+            "text": f"{mark} {item.get('text')}",
+            "callback_data": f"toggle_check_{task_id}_{item.get('id')}_{0 if item.get('done') else 1}"
+        }])
+    keyboard["inline_keyboard"].append([{"text": "➕ Добавить пункт", "callback_data": f"add_check_{task_id}"}])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_tasks"}])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def tasks_attachments_request(chat_id, task_id):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/attachments"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+        "Accept": "application/json",
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_upload_attachment_request(chat_id, task_id, file_name, content):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/attachments"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+    }
+    try:
+        response = requests.post(url, files={"file": (file_name, content)}, headers=headers)
+        if response.status_code == 201:
+            return {"success": True, "data": response.json()}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def tasks_download_attachment_request(chat_id, task_id, attachment_id):
+    url = f"{BACKEND_BASE_URL}/tasks/{task_id}/attachments/{attachment_id}"
+    headers = {
+        "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
+    }
+    try:
+        response = requests.get(url, headers=headers)
+        if response.status_code == 200:
+            file_name = f"file_{attachment_id}"
+            disposition = response.headers.get("Content-Disposition", "")
+            if "filename" in disposition:
+                _, params = cgi_parse_header(disposition)
+                file_name = params.get("filename", file_name)
+            return {"success": True, "file_name": file_name, "content": response.content}
+        else:
+            return {"success": False, "error": response.json().get("error", f"Статус: {response.status_code}")}
+    except Exception as e:
+        return {"success": False, "error": str(e)}
+
+def cgi_parse_header(value):
+    """Разбирает заголовок вида attachment; filename*=utf-8''%D0%BE.pdf."""
+    parts = [p.strip() for p in value.split(";")]
+    params = {}
+    for part in parts[1:]:
+        if "=" not in part:
+            continue
+        key, val = part.split("=", 1)
+        key = key.strip().lower()
+        val = val.strip().strip('"')
+        if key.endswith("*"):
+            key = key[:-1]
+            val = unquote(val.split("''", 1)[-1])
+        params[key] = val
+    return parts[0], params
+
+def format_file_size(size):
+    if size >= 1 << 20:
+        return f"{size / (1 << 20):.1f} МБ"
+    if size >= 1 << 10:
+        return f"{size / (1 << 10):.0f} КБ"
+    return f"{size} Б"
+
+def send_task_files(chat_id, task_id):
+    result = tasks_attachments_request(chat_id, task_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения файлов: {result['error']}")
+        return
+
+    files = result["data"]
+    message = "*Файлы задачи*\n\n"
+    if not files:
+        message += "_Файлов пока нет_\n"
+    keyboard = {"inline_keyboard": []}
+    for f in files:
+        message += f"📎 {f.get('file_name')} — {format_file_size(f.get('size', 0))}, {f.get('uploader_name', 'Н/Д')}\n"
+        keyboard["inline_keyboard"].append([{
+            "text": f"📥 {f.get('file_name')}",
+            "callback_data": f"get_attachment_{task_id}_{f.get('id')}"
+        }])
+    keyboard["inline_keyboard"].append([{"text": "📎 Прикрепить файл", "callback_data": f"add_attachment_{task_id}"}])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_tasks"}])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def process_attachment_message(chat_id, user_state: UserState, message):
+    task_id = user_state.data["attachment_task_id"]
+    if message.get("document"):
+        document = message["document"]
+        file_id = document["file_id"]
+        file_name = document.get("file_name", "file")
+    else:
+        # Фото приходит в нескольких размерах, последний — самый большой
+        file_id = message["photo"][-1]["file_id"]
+        file_name = f"photo_{message.get('message_id', '')}.jpg"
+
+    content = download_telegram_file(file_id)
+    user_state.state = "authorized"
+    if content is None:
+        send_message(chat_id, "❌ Не удалось получить файл из Telegram")
+        return
+    result = tasks_upload_attachment_request(chat_id, task_id, file_name, content)
+    if result["success"]:
+        send_message(chat_id, f"📎 Файл {file_name} прикреплён к задаче")
+    else:
+        send_message(chat_id, f"❌ Ошибка загрузки файла: {result['error']}")
+    send_task_files(chat_id, task_id)
+
+def format_comments(comments, depth=0):
+    text = ""
+    for comment in comments:
+        indent = "    " * depth
+        created = comment.get("created_at", "")[:16].replace("T", " ")
+        if comment.get("deleted"):
+            text += f"{indent}💬 _Комментарий удалён_\n"
+        else:
+            edited = " (изм.)" if comment.get("edited_at") else ""
+            text += f"{indent}💬 *{comment.get('author_name', 'Н/Д')}* {created}{edited} [#{comment.get('id')}]\n"
+            text += f"{indent}{comment.get('text', '')}\n"
+            for f in comment.get("attachments", []):
+                text += f"{indent}📎 {f.get('file_name')}\n"
+        text += format_comments(comment.get("replies", []), depth + 1)
+    return text
+
+def send_task_comments(chat_id, task_id):
+    result = tasks_comments_request(chat_id, task_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения комментариев: {result['error']}")
+        return
+
+    comments = result["data"]
+    message = "*Обсуждение задачи*\n\n"
+    if not comments:
+        message += "_Комментариев пока нет_\n"
+    message += format_comments(comments)
+
+    keyboard = {"inline_keyboard": [[
+        {"text": "✍️ Написать", "callback_data": f"add_comment_{task_id}"},
+        {"text": "🗂 Файлы", "callback_data": f"task_files_{task_id}"}
+    ]]}
+    for comment in comments:
+        if not comment.get("deleted"):
+            keyboard["inline_keyboard"].append([{
+                "text": f"↪️ Ответить #{comment.get('id')} ({comment.get('author_name', 'Н/Д')})",
+                "callback_data": f"add_comment_{task_id}_{comment.get('id')}"
+            }])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_tasks"}])
+    send_message(chat_id, message, reply_markup=keyboard)
+
+def send_reviews_menu(chat_id):
+    result = tasks_reviews_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения работ на проверке: {result['error']}")
+        return
+
+    reviews = result["data"]
+    message = "*Работы на проверке*\n\n"
+    if not reviews:
+        message += "_Нет работ, ожидающих проверки_\n"
+    keyboard = {"inline_keyboard": []}
+    for review in reviews:
+        message += f"*{review.get('task_title', 'Без названия')}* — {review.get('name', 'Н/Д')}\n"
+        if review.get("completion_text"):
+            message += f"_{review.get('completion_text')}_\n"
+        if review.get("attachment"):
+            message += f"Вложение: {review.get('attachment')}\n"
+        message += "\n"
+        keyboard["inline_keyboard"].append([
+            {"text": f"✅ {review.get('task_title')}", "callback_data": f"approve_review_{review.get('id')}"},
+            {"text": "↩️ Вернуть", "callback_data": f"reject_review_{review.get('id')}"},
+            {"text": "🕘 История", "callback_data": f"task_history_{review.get('task_id')}"}
+        ])
+    keyboard["inline_keyboard"].append([{"text": "🔙 Назад", "callback_data": "manage_tasks"}])
     send_message(chat_id, message, reply_markup=keyboard)
 
 def send_issued_tasks_menu(chat_id):
@@ -1198,20 +2555,54 @@ def send_issued_tasks_menu(chat_id):
         message += f"_{task.get('description', 'Без описания')}_\n"
         message += f"Кому: {assigned_to}\n"
         message += f"Статус: {status}\n"
-        message += f"Срок: {deadline}\n\n"
+        if task.get("is_team"):
+            message += f"Прогресс команды: {task.get('progress', 0)}%\n"
+        message += f"Срок: {deadline}\n"
+        message += format_task_breakdown(task)
+        message += f"Комментарии: {task.get('comment_count', 0)}, файлы: {len(task.get('attachments') or [])}\n\n"
         
-        keyboard["inline_keyboard"].append([{
-            "text": f"❌ Удалить: {task.get('title')}",
-            "callback_data": f"delete_task_{task.get('id')}"
-        }])
+        keyboard["inline_keyboard"].append([
+            {"text": f"✏️ Изменить: {task.get('title')}", "callback_data": f"edit_task_{task.get('id')}"},
+            {"text": "❌ Удалить", "callback_data": f"delete_task_{task.get('id')}"},
+            {"text": "🕘", "callback_data": f"task_history_{task.get('id')}"},
+            {"text": "☑️", "callback_data": f"task_checklist_{task.get('id')}"},
+            {"text": "💬", "callback_data": f"task_comments_{task.get('id')}"}
+        ])
+        if task.get("series_id"):
+            keyboard["inline_keyboard"].append([
+                {"text": f"⏹ Остановить повторение: {task.get('title')}", "callback_data": f"stop_series_{task['series_id']}"}
+            ])
 
     keyboard["inline_keyboard"].extend([
+        [{"text": "🗂 Выбрать несколько", "callback_data": "bulk_tasks"}],
         [{"text": "📝 Создать задачу", "callback_data": "create_task"}],
         [{"text": "🔙 Назад", "callback_data": "manage_tasks"}]
     ])
     
     send_message(chat_id, message, reply_markup=keyboard)
 
+def send_bulk_tasks_menu(chat_id, user_state: UserState):
+    result = tasks_get_issued_request(chat_id)
+    if not result["success"]:
+        send_message(chat_id, f"❌ Ошибка получения выданных задач: {result['error']}")
+        return
+
+    selected = user_state.data.get("bulk_task_ids") or []
+    keyboard = {"inline_keyboard": []}
+    for task in result["data"]:
+        mark = "✅" if task.get("id") in selected else "⬜"
+        keyboard["inline_keyboard"].append([
+            {"text": f"{mark} {task.get('title', 'Без названия')}", "callback_data": f"bulk_toggle_{task.get('id')}"}
+        ])
+    keyboard["inline_keyboard"].extend([
+        [
+            {"text": "⏰ Перенести срок", "callback_data": "bulk_deadline"},
+            {"text": "❌ Удалить", "callback_data": "bulk_delete"}
+        ],
+        [{"text": "🔙 Назад", "callback_data": "issued_tasks"}]
+    ])
+    send_message(chat_id, f"*Выберите задачи*\nВыбрано: {len(selected)}", reply_markup=keyboard)
+
 def send_meeting_management_menu(chat_id, user_state: UserState):
     keyboard = {
         "inline_keyboard": [
@@ -1224,7 +2615,6 @@ def send_meeting_management_menu(chat_id, user_state: UserState):
 
 def meetings_create_request(chat_id, title, meeting_type, date, start_time, end_time, room=None):
     url = f"{BACKEND_BASE_URL}/meetings"
-    params = {"telegram_id": str(chat_id)}
     payload = {
         "title": title,
         "meeting_type": meeting_type,
@@ -1237,11 +2627,12 @@ def meetings_create_request(chat_id, title, meeting_type, date, start_time, end_
 
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
         "Content-Type": "application/json",
     }
     try:
-        response = requests.post(url, params=params, json=payload, headers=headers)
+        response = requests.post(url, json=payload, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1249,7 +2640,7 @@ def meetings_create_request(chat_id, title, meeting_type, date, start_time, end_
     except Exception as e:
         return {"success": False, "error": str(e)}
 
-def meetings_get_available_slots_request(room, date):
+def meetings_get_available_slots_request(chat_id, room, date):
     url = f"{BACKEND_BASE_URL}/meetings/available-slots"
     params = {
         "room": room,
@@ -1257,6 +2648,7 @@ def meetings_get_available_slots_request(room, date):
     }
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
@@ -1270,13 +2662,13 @@ def meetings_get_available_slots_request(room, date):
 
 def meetings_get_my_request(chat_id):
     url = f"{BACKEND_BASE_URL}/meetings/my"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.get(url, params=params, headers=headers)
+        response = requests.get(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else:
@@ -1286,13 +2678,13 @@ def meetings_get_my_request(chat_id):
 
 def meetings_delete_request(chat_id, meeting_id):
     url = f"{BACKEND_BASE_URL}/meetings/{meeting_id}"
-    params = {"telegram_id": str(chat_id)}
     headers = {
         "User-Agent": "TelegramBot/1.0",
+        **backend_auth_headers(chat_id),
         "Accept": "application/json",
     }
     try:
-        response = requests.delete(url, params=params, headers=headers)
+        response = requests.delete(url, headers=headers)
         if response.status_code == 200:
             return {"success": True, "data": response.json()}
         else: